@@ -1,10 +1,13 @@
 package mvct
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"reflect"
-	"runtime"
+	"slices"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -22,10 +25,46 @@ type Application[M any] struct {
 	// key handlers are registered during the init method when the application
 	// starts or when a route changes
 	keyHandlers KeyHandlers
-	// message handlers are registered by type - auto-discovered via reflection
-	msgHandlers map[reflect.Type]reflect.Value
+	// message handlers are registered by type - taken from the generated
+	// HandlerTable or auto-discovered via reflection
+	msgHandlers map[reflect.Type]MessageHandler
 	model       M
 	layoutFunc  func(content string, width, height int) string
+	// notifications queued with Notify, rendered on top of the layout
+	notifications *notifier
+	// wizards registered with RegisterWizard
+	wizards []*Wizard
+	// events published with Publish
+	events *EventBus
+	// subscriptions added with Subscribe and the running subscriptions of
+	// the application and of the active controller
+	appSubscriptions []Subscription
+	running          []*runningSubscription
+	routeRunning     []*runningSubscription
+	// background jobs started with StartJob
+	jobs *jobManager
+	// undo history of the application, set by UseUndo
+	undo       *UndoHistory
+	undoConfig UndoConfig
+	// bound in the key handlers of every controller before its Init, so
+	// the controller can rebind their keys
+	keyBindings []KeyBinding
+	// session persistence, set by UseSession
+	session *SessionConfig
+	// start route from the command line, set by UseDeepLink
+	deepLink *DeepLinkConfig
+	// commands of the start route, run by Init
+	startup []tea.Cmd
+	// build status of mvct dev, set when running under it
+	dev *DevStatus
+	// inspector overlay, set by UseDevtools
+	devtools *devtools
+	// spans and metrics, set by UseTracing
+	tracer *tracer
+	// spanCtx holds the span being handled, viewCtx the Update that View
+	// renders
+	spanCtx context.Context
+	viewCtx context.Context
 
 	Errors []error
 }
@@ -42,6 +81,9 @@ func NewApplication[M any](config Config, model M) *Application[M] {
 		router:         NewRouter(),
 		globalHandlers: []GlobalHandler{},
 		model:          model,
+		notifications:  newNotifier(NotificationConfig{}),
+		events:         &EventBus{},
+		jobs:           &jobManager{config: JobsConfig{}.withDefaults()},
 	}
 
 	if config.DefaultRoute != "" {
@@ -110,20 +152,37 @@ func (a *Application[M]) GetSetting(s string) (any, error) {
 // Init implements tea.Model
 func (a *Application[M]) Init() tea.Cmd {
 	slog.Debug("Initializing application")
-	a.scanMessageHandlers()
-	cmd := a.router.Current().Init(a.keyHandlers)
-	return unwrapCmd(cmd)
+	a.keyHandlers = a.newKeyHandlers()
+	a.bindMessageHandlers()
+	// a wizard can be the first route
+	a.trackWizards("", a.router.CurrentRoute())
+	ctlr := a.router.Current()
+	cmd := a.router.initCurrent(a.keyHandlers)
+	startup := a.startup
+	a.startup = nil
+	batch := tea.Batch(unwrapCmd(cmd), a.startAppSubscriptions(), a.activated(ctlr), tea.Batch(startup...))
+	if a.tracer != nil {
+		ctx, span := a.tracer.start(context.Background(), "mvct.init", true, Attr("mvct.route", a.router.CurrentRoute()))
+		span.End()
+		return a.tracer.traceCmd(ctx, batch)
+	}
+	return batch
 }
 
 func (a *Application[M]) View() string {
 	slog.Debug("Application View called")
-	content := a.router.Current().View()
+	if a.tracer != nil {
+		_, span := a.tracer.start(a.viewCtx, "mvct.view", true, Attr("mvct.route", a.router.CurrentRoute()))
+		defer span.End()
+	}
+	view := a.router.Current().View()
 
 	if a.layoutFunc != nil {
-		return a.layoutFunc(content, a.width, a.height)
+		view = a.layoutFunc(view, a.width, a.height)
 	}
 
-	return content
+	view = a.notifications.overlay(view, a.width, a.height)
+	return a.devtoolsOverlay(a.devOverlay(view))
 }
 
 func (a *Application[M]) SetLayout(fn func(content string, width, height int) string) {
@@ -133,8 +192,25 @@ func (a *Application[M]) SetLayout(fn func(content string, width, height int) st
 
 func (a *Application[M]) Run() error {
 	slog.Info("Starting application run loop")
+	a.startDev()
+	a.restoreSession()
+	a.startDeepLink()
 	p := tea.NewProgram(a)
 	_, err := p.Run()
+	a.stopSubscriptions()
+	a.jobs.cancelAll()
+	if a.session != nil {
+		if saveErr := a.SaveSession(); saveErr != nil {
+			slog.Error("Failed to save session", "error", saveErr)
+		}
+	}
+	if a.tracer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if closeErr := a.tracer.close(ctx); closeErr != nil {
+			slog.Error("Failed to export the last spans", "error", closeErr)
+		}
+		cancel()
+	}
 	slog.Info("Application stopped")
 	return err
 }
@@ -142,19 +218,38 @@ func (a *Application[M]) Run() error {
 func (a *Application[M]) logHandlers() {
 	slog.Debug("=== Registered Handlers ===")
 
-	for key, handler := range a.keyHandlers {
-		fnName := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	keys, msgs := a.handlerNames()
+	for _, key := range keys {
 		slog.Debug("key handler",
-			"key", key,
-			"function", fnName,
+			"key", key[0],
+			"function", key[1],
 		)
 	}
 
-	for msgType, method := range a.msgHandlers {
-		fnName := runtime.FuncForPC(method.Pointer()).Name()
+	for _, msg := range msgs {
 		slog.Debug("message handler",
-			"handles", msgType.String(),
-			"method", fnName,
+			"handles", msg[0],
+			"method", msg[1],
 		)
 	}
 }
+
+// newKeyHandlers returns the key handler map of a controller activation
+func (a *Application[M]) newKeyHandlers() KeyHandlers {
+	return Keys(a.keyBindings...)
+}
+
+// handlerNames returns the key handlers and message handlers of the
+// current controller as sorted key or message type and function pairs
+func (a *Application[M]) handlerNames() (keys, msgs [][2]string) {
+	for key, handler := range a.keyHandlers {
+		keys = append(keys, [2]string{key, funcName(handler)})
+	}
+	for msgType, handler := range a.msgHandlers {
+		msgs = append(msgs, [2]string{msgType.String(), handler.Name})
+	}
+	byFirst := func(a, b [2]string) int { return strings.Compare(a[0], b[0]) }
+	slices.SortFunc(keys, byFirst)
+	slices.SortFunc(msgs, byFirst)
+	return keys, msgs
+}
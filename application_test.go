@@ -1,6 +1,7 @@
 package mvct
 
 import (
+	"reflect"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -124,6 +125,53 @@ func TestApplicationUpdate_ReflectionHandler(t *testing.T) {
 	}
 }
 
+// TableController has a hand written dispatch table that routes StringMsg
+// to a method reflection would not discover
+type TableController struct {
+	ReflectionController
+	tableMsg string
+}
+
+func (c *TableController) MessageHandlers() []MessageHandler {
+	return []MessageHandler{
+		HandleMsg(c.handleString),
+	}
+}
+
+func (c *TableController) handleString(msg StringMsg) Cmd {
+	c.tableMsg = msg.Value
+	return nil
+}
+
+// staleMsg is handled by a method added after the table was written
+type staleMsg struct{}
+
+func (c *TableController) OnStaleMsg(msg staleMsg) Cmd {
+	c.tableMsg = "stale"
+	return nil
+}
+
+func TestApplicationUpdate_HandlerTable(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/table"}, "model")
+	tc := &TableController{}
+	app.RegisterController("/table", tc)
+	app.Init()
+
+	app.Update(StringMsg{Value: "hello"})
+
+	if tc.tableMsg != "hello" {
+		t.Errorf("expected table handler to receive 'hello', got '%s'", tc.tableMsg)
+	}
+	if tc.receivedMsg != "" {
+		t.Error("reflection should not be used for messages in the handler table")
+	}
+
+	app.Update(staleMsg{})
+	if tc.tableMsg != "stale" {
+		t.Error("expected an On method missing from the table to be called through reflection")
+	}
+}
+
 func TestApplicationUpdate_KeyHandler(t *testing.T) {
 	app := NewApplication(Config{DefaultRoute: "/key"}, "model")
 	rc := &ReflectionController{}
@@ -158,3 +206,74 @@ func TestApplicationUpdate_KeyHandler(t *testing.T) {
 		t.Error("Global handler should return Quit cmd")
 	}
 }
+
+// CountingController counts Init calls and handled messages
+type CountingController struct {
+	inits    int
+	received []string
+}
+
+func (c *CountingController) Init(handlers KeyHandlers) Cmd {
+	c.inits++
+	return nil
+}
+
+func (c *CountingController) View() string { return "counting" }
+
+func (c *CountingController) OnStringMsg(msg StringMsg) Cmd {
+	c.received = append(c.received, msg.Value)
+	return nil
+}
+
+func TestApplicationUpdate_InitOncePerActivation(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/a"}, "model")
+	a := &CountingController{}
+	b := &CountingController{}
+	app.RegisterController("/a", a)
+	app.RegisterController("/b", b)
+
+	app.Init()
+	app.Update(NavigateMsg{Route: "/b"})
+	app.Update(NavigateMsg{Route: "/a"})
+	app.Update(NavigateMsg{Route: "/b"})
+
+	if a.inits != 2 {
+		t.Errorf("expected 2 inits of /a, got %d", a.inits)
+	}
+	if b.inits != 2 {
+		t.Errorf("expected 2 inits of /b, got %d", b.inits)
+	}
+
+	// handlers are bound to the active instance of a shared type
+	app.Update(StringMsg{Value: "hello"})
+	if len(b.received) != 1 || len(a.received) != 0 {
+		t.Errorf("expected only /b to receive the message, got a=%v b=%v", a.received, b.received)
+	}
+}
+
+func TestApplicationUpdate_BlockedNavigationKeepsKeyHandlers(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/key"}, "model")
+	rc := &ReflectionController{}
+	app.RegisterController("/key", rc)
+	app.RegisterController("/other", &MockController{name: "other"})
+	app.Use(&MockMiddleware{shouldBlock: true})
+	app.Init()
+
+	app.Update(NavigateMsg{Route: "/other"})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+
+	if !rc.keyHandled {
+		t.Error("key handlers should survive a blocked navigation")
+	}
+}
+
+func TestReflectedHandlersCached(t *testing.T) {
+	typ := reflect.TypeFor[*CountingController]()
+	first := reflectedHandlersOf(typ)
+	if len(first) != 1 || first[0].msgType != reflect.TypeFor[StringMsg]() {
+		t.Fatalf("expected a single StringMsg handler, got %+v", first)
+	}
+	if second := reflectedHandlersOf(typ); &second[0] != &first[0] {
+		t.Error("expected the cached handlers to be reused")
+	}
+}
@@ -0,0 +1,156 @@
+package mvct
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type (
+	benchMsg0  struct{ n int }
+	benchMsg1  struct{ n int }
+	benchMsg2  struct{ n int }
+	benchMsg3  struct{ n int }
+	benchMsg4  struct{ n int }
+	benchMsg5  struct{ n int }
+	benchMsg6  struct{ n int }
+	benchMsg7  struct{ n int }
+	benchMsg8  struct{ n int }
+	benchMsg9  struct{ n int }
+	benchMsg10 struct{ n int }
+	benchMsg11 struct{ n int }
+	benchMsg12 struct{ n int }
+	benchMsg13 struct{ n int }
+	benchMsg14 struct{ n int }
+	benchMsg15 struct{ n int }
+)
+
+// benchMsgs has one message of every handled type and one without a handler
+var benchMsgs = []tea.Msg{
+	benchMsg0{}, benchMsg1{}, benchMsg2{}, benchMsg3{},
+	benchMsg4{}, benchMsg5{}, benchMsg6{}, benchMsg7{},
+	benchMsg8{}, benchMsg9{}, benchMsg10{}, benchMsg11{},
+	benchMsg12{}, benchMsg13{}, benchMsg14{}, benchMsg15{},
+	StringMsg{},
+}
+
+// BenchController has sixteen handlers found by reflection
+type BenchController struct {
+	handled int
+}
+
+func (c *BenchController) Init(handlers KeyHandlers) Cmd {
+	for _, key := range []string{"up", "down", "enter", "esc", "a", "b", "c", "d"} {
+		handlers[key] = func(msg KeyMsg) Cmd {
+			c.handled++
+			return nil
+		}
+	}
+	return nil
+}
+
+func (c *BenchController) View() string { return "bench" }
+
+func (c *BenchController) OnBenchMsg0(msg benchMsg0) Cmd   { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg1(msg benchMsg1) Cmd   { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg2(msg benchMsg2) Cmd   { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg3(msg benchMsg3) Cmd   { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg4(msg benchMsg4) Cmd   { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg5(msg benchMsg5) Cmd   { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg6(msg benchMsg6) Cmd   { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg7(msg benchMsg7) Cmd   { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg8(msg benchMsg8) Cmd   { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg9(msg benchMsg9) Cmd   { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg10(msg benchMsg10) Cmd { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg11(msg benchMsg11) Cmd { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg12(msg benchMsg12) Cmd { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg13(msg benchMsg13) Cmd { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg14(msg benchMsg14) Cmd { c.handled++; return nil }
+func (c *BenchController) OnBenchMsg15(msg benchMsg15) Cmd { c.handled++; return nil }
+
+// BenchTableController dispatches the same handlers through a table
+type BenchTableController struct {
+	BenchController
+}
+
+func (c *BenchTableController) MessageHandlers() []MessageHandler {
+	return []MessageHandler{
+		HandleMsg(c.OnBenchMsg0), HandleMsg(c.OnBenchMsg1), HandleMsg(c.OnBenchMsg2), HandleMsg(c.OnBenchMsg3),
+		HandleMsg(c.OnBenchMsg4), HandleMsg(c.OnBenchMsg5), HandleMsg(c.OnBenchMsg6), HandleMsg(c.OnBenchMsg7),
+		HandleMsg(c.OnBenchMsg8), HandleMsg(c.OnBenchMsg9), HandleMsg(c.OnBenchMsg10), HandleMsg(c.OnBenchMsg11),
+		HandleMsg(c.OnBenchMsg12), HandleMsg(c.OnBenchMsg13), HandleMsg(c.OnBenchMsg14), HandleMsg(c.OnBenchMsg15),
+	}
+}
+
+// quietLogs silences the debug logging of Update for the benchmark
+func quietLogs(b *testing.B) {
+	logger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError})))
+	b.Cleanup(func() { slog.SetDefault(logger) })
+}
+
+func benchApp(ctlr Controller) *Application[string] {
+	app := NewApplication(Config{DefaultRoute: "/bench"}, "model")
+	app.RegisterController("/bench", ctlr)
+	app.RegisterController("/other", &BenchController{})
+	app.UseGlobalHandler(QuitHandler("ctrl+c"))
+	app.Init()
+	return app
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	quietLogs(b)
+	for _, bench := range []struct {
+		name string
+		ctlr Controller
+	}{
+		{"reflection", &BenchController{}},
+		{"table", &BenchTableController{}},
+	} {
+		app := benchApp(bench.ctlr)
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; b.Loop(); i++ {
+				app.Update(benchMsgs[i%len(benchMsgs)])
+			}
+		})
+	}
+}
+
+func BenchmarkUpdate_Keys(b *testing.B) {
+	quietLogs(b)
+	app := benchApp(&BenchController{})
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyUp},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune("a")},
+		{Type: tea.KeyRunes, Runes: []rune("z")},
+	}
+
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		app.Update(keys[i%len(keys)])
+	}
+}
+
+func BenchmarkUpdate_Navigate(b *testing.B) {
+	quietLogs(b)
+	for _, bench := range []struct {
+		name string
+		ctlr Controller
+	}{
+		{"reflection", &BenchController{}},
+		{"table", &BenchTableController{}},
+	} {
+		app := benchApp(bench.ctlr)
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			routes := []NavigateMsg{{Route: "/other"}, {Route: "/bench"}}
+			for i := 0; b.Loop(); i++ {
+				app.Update(routes[i%len(routes)])
+			}
+		})
+	}
+}
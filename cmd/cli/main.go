@@ -1,10 +1,21 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strings"
 
+	"github.com/michael-duren/mvct/internal/dev"
+	"github.com/michael-duren/mvct/internal/gen"
 	"github.com/michael-duren/mvct/internal/scaffold"
+	"github.com/michael-duren/mvct/internal/scaffoldui"
+	"github.com/michael-duren/mvct/internal/templates"
+	"github.com/michael-duren/mvct/lint"
 	"github.com/spf13/cobra"
 )
 
@@ -13,31 +24,263 @@ func main() {
 		Use:   "mvct",
 		Short: "MVCT is a Model-View-Controller framework for Bubble Tea",
 		Long:  `A framework for building terminal user interfaces with Bubble Tea using an MVC architecture.`,
+		// main prints the error
+		SilenceErrors: true,
 	}
 
-	var withDB bool
+	var withDB, dryRun, force, skip, merge, noGit, noTidy bool
+	var module, replace, template, logging, theme string
+	var values map[string]string
 
 	var scaffoldCmd = &cobra.Command{
 		Use:   "scaffold [project-name]",
 		Short: "Scaffold a new MVCT project",
-		Args:  cobra.ExactArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			projectName := args[0]
+		Long: `Scaffold creates a new project in a directory named after it. Without a
+project name it asks for the configuration in a terminal UI and previews the
+files before writing them.
+
+Existing files that are up to date are left alone, so scaffolding again only
+adds what is missing. Existing files that differ stop the run unless --force,
+--skip or --merge says what to do with them, and a failed run removes what it
+created.`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			config := scaffold.ProjectConfiguration{
-				Name:   projectName,
-				Path:   projectName,
-				WithDB: withDB,
+				Module:   module,
+				WithDB:   withDB,
+				Replace:  replace,
+				Template: template,
+				Values:   values,
+				Prompt:   promptStdin,
+				Logging:  logging,
+				Theme:    theme,
+				DryRun:   dryRun,
+				NoGit:    noGit,
+				NoTidy:   noTidy,
+			}
+			switch {
+			case force:
+				config.Conflict = scaffold.ConflictForce
+			case skip:
+				config.Conflict = scaffold.ConflictSkip
+			case merge:
+				config.Conflict = scaffold.ConflictMerge
+			}
+			if len(args) == 1 {
+				config.Name, config.Path = args[0], args[0]
+				return scaffold.ScaffoldProject(config)
+			}
+
+			config, ok, err := scaffoldui.Run(config)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Cancelled, nothing was written")
+				return nil
+			}
+			return scaffold.ScaffoldProject(config)
+		},
+	}
+
+	scaffoldCmd.Flags().BoolVar(&withDB, "db", false, "Add a SQLite data layer with migrations and a todo list")
+	scaffoldCmd.Flags().StringVar(&module, "module", "", "Module path of the project (default the project name)")
+	scaffoldCmd.Flags().StringVar(&replace, "replace", "", "Build against a local mvct checkout")
+	scaffoldCmd.Flags().StringVarP(&template, "template", "t", templates.DefaultTemplate, "Project template, "+templateNames()+" or a template directory")
+	scaffoldCmd.Flags().StringToStringVar(&values, "set", nil, "Answer template prompts, like --set noun=task")
+	scaffoldCmd.Flags().StringVar(&logging, "logging", "debug", "Log level of the app, one of "+strings.Join(scaffold.LogLevels, ", "))
+	scaffoldCmd.Flags().StringVar(&theme, "theme", "", "Theme of the app, auto or a theme name (default no themes)")
+	scaffoldCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the files and the diffs of existing ones without writing anything")
+	scaffoldCmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files that differ")
+	scaffoldCmd.Flags().BoolVar(&skip, "skip", false, "Keep existing files that differ")
+	scaffoldCmd.Flags().BoolVar(&merge, "merge", false, "Mark the differences in existing files with conflict markers")
+	scaffoldCmd.Flags().BoolVar(&noGit, "no-git", false, "Do not run git init")
+	scaffoldCmd.Flags().BoolVar(&noTidy, "no-tidy", false, "Do not run go mod tidy")
+	scaffoldCmd.MarkFlagsMutuallyExclusive("force", "skip", "merge")
+
+	var genOutput string
+
+	var genCmd = &cobra.Command{
+		Use:   "gen [packages]",
+		Short: "Generate typed On* dispatch tables for controllers",
+		Long: `Generate writes a MessageHandlers method for every controller in the given
+packages (default: the current package). The application uses it instead of
+reflection, and On* handlers with the wrong signature fail to compile.
+
+Add it to a controllers package with:
+
+	//go:generate mvct gen`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := gen.Generate(".", genOutput, args...)
+			if err != nil {
+				return err
+			}
+			for _, file := range files {
+				for _, problem := range file.Problems {
+					fmt.Fprintln(os.Stderr, "warning:", problem)
+				}
+				for _, skipped := range file.Skipped {
+					fmt.Fprintln(os.Stderr, "warning:", skipped)
+				}
+				if file.Source != nil {
+					fmt.Printf("%s: %d controller(s)\n", file.Path, len(file.Controllers))
+				}
+			}
+			return gen.Write(files)
+		},
+	}
+
+	genCmd.Flags().StringVarP(&genOutput, "output", "o", gen.DefaultOutput, "Name of the generated file in each package")
+
+	var lintCmd = &cobra.Command{
+		Use:   "lint [packages]",
+		Short: "Report common mvct mistakes",
+		Long: `Lint checks the given packages (default: ./...) for On* handlers with an
+invalid signature, navigation to routes that are never registered and key
+strings bubbletea never produces, such as "Enter" instead of "enter".`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			n, err := lint.Check(".", os.Stdout, args...)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				return fmt.Errorf("%d problem(s) found", n)
+			}
+			return nil
+		},
+	}
+
+	var routesFormat string
+
+	var routesCmd = &cobra.Command{
+		Use:   "routes [packages]",
+		Short: "Print the route table of the project",
+		Long: `Routes reads the given packages (default: ./...) and prints every registered
+route with its controller, the keys the controller binds and the routes it
+navigates to, followed by the middleware added with Use. The dot and mermaid
+formats draw the navigation graph built from the Navigate calls.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			table, err := lint.Routes(".", args...)
+			if err != nil {
+				return err
+			}
+			return table.Write(os.Stdout, routesFormat)
+		},
+	}
+	routesCmd.Flags().StringVarP(&routesFormat, "format", "f", "text", "Output format, one of "+strings.Join(lint.Formats, ", "))
+
+	var extensions []string
+
+	var devCmd = &cobra.Command{
+		Use:   "dev [package] [-- app arguments]",
+		Short: "Run the project and restart it when its source changes",
+		Long: `Dev builds and runs the main package (default: main.go or the only package
+under cmd/) and watches the Go files of the project. A change rebuilds it and
+restarts the app on the route it was on, with its navigation history and the
+state of Persistable controllers. A failed build is shown over the running
+app until the errors are fixed.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := dev.Config{Extensions: extensions}
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				args, config.Args = args[:dash], args[dash:]
+			}
+			switch len(args) {
+			case 0:
+			case 1:
+				config.Package = args[0]
+			default:
+				return fmt.Errorf("expected one package, got %d, pass app arguments after --", len(args))
 			}
-			scaffold.ScaffoldProject(config)
+			for i, ext := range config.Extensions {
+				if !strings.HasPrefix(ext, ".") {
+					config.Extensions[i] = "." + ext
+				}
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			return dev.Run(ctx, config)
 		},
 	}
+	devCmd.Flags().StringSliceVar(&extensions, "ext", nil, "Other file extensions that trigger a rebuild, like tmpl for embedded files")
 
-	scaffoldCmd.Flags().BoolVar(&withDB, "db", false, "Include SQLite database with sqlc setup")
+	var mainFile string
+
+	var generateCmd = &cobra.Command{
+		Use:     "generate",
+		Aliases: []string{"g"},
+		Short:   "Add a controller, middleware or component to the project",
+		Long: `Generate creates files from templates in the project in the current
+directory. A controller also gets a route in controllers/routing.go and is
+registered in main.go, a middleware is added with app.Use.`,
+	}
+
+	generator := func(kind string, generate func(scaffold.Project, string) ([]scaffold.File, error)) *cobra.Command {
+		return &cobra.Command{
+			Use:          kind + " [name]",
+			Short:        "Generate a " + kind,
+			Args:         cobra.ExactArgs(1),
+			SilenceUsage: true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				files, err := generate(scaffold.Project{Dir: ".", Main: mainFile}, args[0])
+				if err != nil {
+					return err
+				}
+				if err := scaffold.Write(files); err != nil {
+					return err
+				}
+				for _, file := range files {
+					if file.New {
+						fmt.Println("created", file.Path)
+					} else {
+						fmt.Println("updated", file.Path)
+					}
+				}
+				return nil
+			},
+		}
+	}
+
+	generateCmd.PersistentFlags().StringVar(&mainFile, "main", "", "File registering the controllers (default cmd/cli/main.go or main.go)")
+	generateCmd.AddCommand(generator("controller", scaffold.GenerateController))
+	generateCmd.AddCommand(generator("middleware", scaffold.GenerateMiddleware))
+	generateCmd.AddCommand(generator("component", scaffold.GenerateComponent))
 
 	rootCmd.AddCommand(scaffoldCmd)
+	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(genCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(routesCmd)
+	rootCmd.AddCommand(devCmd)
 
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
 }
+
+// templateNames lists the built-in templates for the help of --template
+func templateNames() string {
+	var names []string
+	for _, t := range templates.Builtin() {
+		names = append(names, fmt.Sprintf("%s (%s)", t.Name, t.Description))
+	}
+	return strings.Join(names, ", ")
+}
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// promptStdin asks for a template prompt on the terminal, an empty answer
+// keeps the default
+func promptStdin(prompt templates.Prompt) (string, error) {
+	fmt.Printf("%s [%s]: ", prompt.Message, prompt.Default)
+	answer, err := stdin.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimSpace(answer), nil
+}
@@ -0,0 +1,84 @@
+// Package components provides reusable widgets built on the same contract as
+// mvct controllers. A component registers its key bindings in Init, renders
+// itself in View and exposes On<Msg> methods the owning controller forwards
+// messages to:
+//
+//	func (c *InboxController) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+//		return components.Init(handlers, c.list, c.spinner)
+//	}
+//
+//	func (c *InboxController) OnSpinnerTickMsg(msg components.SpinnerTickMsg) mvct.Cmd {
+//		return c.spinner.OnSpinnerTickMsg(msg)
+//	}
+package components
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/michael-duren/mvct"
+)
+
+// Component is the contract shared by every component in this package
+type Component interface {
+	// Init registers the component's key bindings, they only handle keys
+	// while the component is focused
+	Init(handlers mvct.KeyHandlers) mvct.Cmd
+
+	// View renders the component
+	View() string
+
+	// Bindings returns the key bindings of the component, including their
+	// help descriptions
+	Bindings() []mvct.KeyBinding
+}
+
+// Focusable is implemented by components that take keyboard input
+type Focusable interface {
+	Component
+	Focus()
+	Blur()
+	Focused() bool
+}
+
+// Init initializes several components at once and batches their commands
+func Init(handlers mvct.KeyHandlers, components ...Component) mvct.Cmd {
+	cmds := make([]mvct.Cmd, 0, len(components))
+	for _, c := range components {
+		cmds = append(cmds, c.Init(handlers))
+	}
+	return mvct.Batch(cmds...)
+}
+
+// focusState is embedded by components to implement Focusable. Components
+// start focused so a single component works without a FocusGroup
+type focusState struct {
+	blurred bool
+}
+
+// Focus gives the component keyboard focus
+func (f *focusState) Focus() {
+	f.blurred = false
+}
+
+// Blur removes keyboard focus from the component
+func (f *focusState) Blur() {
+	f.blurred = true
+}
+
+// Focused reports whether the component has keyboard focus
+func (f *focusState) Focused() bool {
+	return !f.blurred
+}
+
+// padLeft right aligns n to the number of digits in widest
+func padLeft(n, widest int) string {
+	return fmt.Sprintf("%*d", len(strconv.Itoa(widest)), n)
+}
+
+func clamp(v, low, high int) int {
+	if high < low {
+		return low
+	}
+	return min(max(v, low), high)
+}
@@ -0,0 +1,129 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/michael-duren/mvct"
+)
+
+// FocusGroup moves keyboard focus between components with tab and
+// shift+tab. Only the focused component handles its key bindings. The
+// group is Focusable itself, blurring it blurs every component in it
+type FocusGroup struct {
+	focusState
+
+	items   []Focusable
+	current int
+}
+
+// NewFocusGroup creates a focus group, the first component gets focus
+func NewFocusGroup(items ...Focusable) *FocusGroup {
+	g := &FocusGroup{items: items}
+	g.FocusIndex(0)
+	return g
+}
+
+// Init registers the components of the group followed by the tab and
+// shift+tab bindings of the group itself
+func (g *FocusGroup) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	cmds := make([]mvct.Cmd, 0, len(g.items))
+	for _, item := range g.items {
+		cmds = append(cmds, item.Init(handlers))
+	}
+	mvct.Bind(handlers, g.Focused, g.Bindings()...)
+	return mvct.Batch(cmds...)
+}
+
+// Bindings returns the focus movement bindings
+func (g *FocusGroup) Bindings() []mvct.KeyBinding {
+	return []mvct.KeyBinding{
+		mvct.Key(mvct.KeyTab.String()).To(g.onNext).Help("next field"),
+		mvct.Key(mvct.KeyShiftTab.String()).To(g.onPrev).Help("previous field"),
+	}
+}
+
+// View renders the components of the group one per line
+func (g *FocusGroup) View() string {
+	views := make([]string, len(g.items))
+	for i, item := range g.items {
+		views[i] = item.View()
+	}
+	return strings.Join(views, "\n")
+}
+
+// Focus focuses the group and restores focus to its current component
+func (g *FocusGroup) Focus() {
+	g.focusState.Focus()
+	g.FocusIndex(g.current)
+}
+
+// Blur blurs the group and every component in it
+func (g *FocusGroup) Blur() {
+	g.focusState.Blur()
+	for _, item := range g.items {
+		item.Blur()
+	}
+}
+
+// Current returns the component that has focus within the group
+func (g *FocusGroup) Current() Focusable {
+	if len(g.items) == 0 {
+		return nil
+	}
+	return g.items[g.current]
+}
+
+// Index returns the position of the focused component
+func (g *FocusGroup) Index() int {
+	return g.current
+}
+
+// FocusIndex focuses the component at i and blurs the others. While the
+// group is blurred it only moves the current position
+func (g *FocusGroup) FocusIndex(i int) {
+	if len(g.items) == 0 {
+		return
+	}
+	g.current = clamp(i, 0, len(g.items)-1)
+	for j, item := range g.items {
+		if j == g.current && g.Focused() {
+			item.Focus()
+		} else {
+			item.Blur()
+		}
+	}
+}
+
+// Next moves focus to the next component, wrapping around at the end
+func (g *FocusGroup) Next() {
+	if len(g.items) > 0 {
+		g.FocusIndex((g.current + 1) % len(g.items))
+	}
+}
+
+// Prev moves focus to the previous component, wrapping around at the start
+func (g *FocusGroup) Prev() {
+	if len(g.items) > 0 {
+		g.FocusIndex((g.current - 1 + len(g.items)) % len(g.items))
+	}
+}
+
+// HelpBindings returns the bindings of the focused component followed by
+// the bindings of the group, for use with Help
+func (g *FocusGroup) HelpBindings() []mvct.KeyBinding {
+	var bindings []mvct.KeyBinding
+	if current := g.Current(); current != nil {
+		bindings = append(bindings, current.Bindings()...)
+	}
+	return append(bindings, g.Bindings()...)
+}
+
+func (g *FocusGroup) onNext(msg mvct.KeyMsg) mvct.Cmd {
+	g.Next()
+	return nil
+}
+
+func (g *FocusGroup) onPrev(msg mvct.KeyMsg) mvct.Cmd {
+	g.Prev()
+	return nil
+}
@@ -0,0 +1,75 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michael-duren/mvct"
+)
+
+func TestFocusGroup(t *testing.T) {
+	name, email := NewTextInput(), NewTextInput()
+	group := NewFocusGroup(name, email)
+	handlers := mvct.KeyHandlers{}
+	group.Init(handlers)
+
+	if !name.Focused() || email.Focused() {
+		t.Fatal("expected the first component to be focused")
+	}
+
+	press(handlers, "tab")
+	if name.Focused() || !email.Focused() {
+		t.Error("tab should move focus to the next component")
+	}
+
+	name.OnKeyMsg(mvct.KeyMsg{Type: mvct.KeyRunes, Runes: []rune("x")})
+	email.OnKeyMsg(mvct.KeyMsg{Type: mvct.KeyRunes, Runes: []rune("y")})
+	press(handlers, "backspace")
+	if name.Value() != "" || email.Value() != "" {
+		t.Errorf("only the focused input should receive input, got %q and %q", name.Value(), email.Value())
+	}
+
+	press(handlers, "tab")
+	if group.Index() != 0 {
+		t.Errorf("tab should wrap around, got index %d", group.Index())
+	}
+
+	press(handlers, "shift+tab")
+	if group.Index() != 1 {
+		t.Errorf("shift+tab should wrap around backwards, got index %d", group.Index())
+	}
+
+	group.Blur()
+	press(handlers, "tab")
+	if group.Index() != 1 || email.Focused() {
+		t.Error("blurred group should ignore tab and blur its components")
+	}
+
+	group.Focus()
+	if group.Current() != email || !email.Focused() {
+		t.Error("Focus should restore focus to the current component")
+	}
+}
+
+func TestHelp(t *testing.T) {
+	list := NewList([]string{"a"}, nil)
+	help := NewHelp(list.Bindings)
+	handlers := mvct.KeyHandlers{}
+	Init(handlers, list, help)
+
+	view := help.View()
+	if !strings.Contains(view, "↑/k") || !strings.Contains(view, "up") {
+		t.Errorf("expected list bindings in help, got %q", view)
+	}
+	if strings.Contains(view, "pgup") {
+		t.Errorf("bindings without description should be hidden, got %q", view)
+	}
+	if strings.Contains(view, "\n") {
+		t.Error("short help should render on one line")
+	}
+
+	press(handlers, "?")
+	if !strings.Contains(help.View(), "\n") {
+		t.Error("? should toggle the full help")
+	}
+}
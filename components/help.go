@@ -0,0 +1,89 @@
+package components
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/michael-duren/mvct"
+)
+
+// keySymbols are shown instead of key names in help views
+var keySymbols = map[string]string{
+	"up":        "↑",
+	"down":      "↓",
+	"left":      "←",
+	"right":     "→",
+	"enter":     "↵",
+	"backspace": "⌫",
+	" ":         "space",
+}
+
+// Help renders the help descriptions of key bindings. It shows a single
+// line by default and one binding per line after ? is pressed
+type Help struct {
+	Styles    *Styles
+	Separator string
+	ShowAll   bool
+
+	sources func() []mvct.KeyBinding
+}
+
+// NewHelp creates a help view for the bindings returned by sources, it is
+// called on every render so the help follows focus changes
+func NewHelp(sources func() []mvct.KeyBinding) *Help {
+	return &Help{
+		Separator: " • ",
+		sources:   sources,
+	}
+}
+
+// Init registers the ? binding that toggles the full help
+func (h *Help) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	mvct.Bind(handlers, func() bool { return true }, h.Bindings()...)
+	return nil
+}
+
+// Bindings returns the help toggle binding
+func (h *Help) Bindings() []mvct.KeyBinding {
+	return []mvct.KeyBinding{
+		mvct.Key("?").To(h.onToggle).Help("toggle help"),
+	}
+}
+
+// View renders the bindings that have a help description
+func (h *Help) View() string {
+	styles := h.Styles.Resolve()
+	bindings := slices.Concat(h.sources(), h.Bindings())
+
+	entries := make([]string, 0, len(bindings))
+	for _, binding := range bindings {
+		if binding.Description() == "" || len(binding.Keys()) == 0 {
+			continue
+		}
+		entries = append(entries,
+			styles.HelpKey.Render(KeyLabel(binding))+" "+styles.HelpDesc.Render(binding.Description()))
+	}
+
+	if h.ShowAll {
+		return strings.Join(entries, "\n")
+	}
+	return strings.Join(entries, styles.HelpDesc.Render(h.Separator))
+}
+
+func (h *Help) onToggle(msg mvct.KeyMsg) mvct.Cmd {
+	h.ShowAll = !h.ShowAll
+	return nil
+}
+
+// KeyLabel renders the keys of a binding the way help views show them,
+// e.g. "↑/k"
+func KeyLabel(binding mvct.KeyBinding) string {
+	labels := make([]string, 0, len(binding.Keys()))
+	for _, k := range binding.Keys() {
+		if symbol, ok := keySymbols[k]; ok {
+			k = symbol
+		}
+		labels = append(labels, k)
+	}
+	return strings.Join(labels, "/")
+}
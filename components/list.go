@@ -0,0 +1,169 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/michael-duren/mvct"
+)
+
+// List is a scrollable list of items with a cursor
+type List[T any] struct {
+	focusState
+
+	Styles *Styles
+	// Height is the number of rows shown at once, 0 shows every item
+	Height int
+	// EmptyText is shown when the list has no items
+	EmptyText string
+
+	items    []T
+	render   func(T) string
+	cursor   int
+	offset   int
+	onSelect func(index int, item T) mvct.Cmd
+}
+
+// NewList creates a list. render turns an item into the text of its row,
+// when it is nil items are formatted with fmt
+func NewList[T any](items []T, render func(T) string) *List[T] {
+	if render == nil {
+		render = func(item T) string { return fmt.Sprint(item) }
+	}
+	return &List[T]{
+		EmptyText: "No items",
+		items:     items,
+		render:    render,
+	}
+}
+
+// OnSelect sets the function called when enter is pressed on an item
+func (l *List[T]) OnSelect(fn func(index int, item T) mvct.Cmd) *List[T] {
+	l.onSelect = fn
+	return l
+}
+
+func (l *List[T]) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	mvct.Bind(handlers, l.Focused, l.Bindings()...)
+	return nil
+}
+
+func (l *List[T]) Bindings() []mvct.KeyBinding {
+	return []mvct.KeyBinding{
+		mvct.Key("up", "k").To(l.onUp).Help("up"),
+		mvct.Key("down", "j").To(l.onDown).Help("down"),
+		mvct.Key("home", "g").To(l.onTop).Help("top"),
+		mvct.Key("end", "G").To(l.onBottom).Help("bottom"),
+		mvct.Key("pgup").To(l.onPageUp),
+		mvct.Key("pgdown").To(l.onPageDown),
+		mvct.Key("enter").To(l.onEnter).Help("select"),
+	}
+}
+
+func (l *List[T]) View() string {
+	styles := l.Styles.Resolve()
+	if len(l.items) == 0 {
+		return styles.Muted.Render("  " + l.EmptyText)
+	}
+
+	end := len(l.items)
+	if l.Height > 0 {
+		end = min(l.offset+l.Height, len(l.items))
+	}
+
+	rows := make([]string, 0, end-l.offset)
+	for i := l.offset; i < end; i++ {
+		text := l.render(l.items[i])
+		if i == l.cursor {
+			rows = append(rows, styles.Selected.Render("▶ "+text))
+		} else {
+			rows = append(rows, styles.Normal.Render("  "+text))
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// Items returns the items of the list
+func (l *List[T]) Items() []T {
+	return l.items
+}
+
+// SetItems replaces the items, keeping the cursor in range
+func (l *List[T]) SetItems(items []T) {
+	l.items = items
+	l.Select(l.cursor)
+}
+
+// Cursor returns the index of the item under the cursor
+func (l *List[T]) Cursor() int {
+	return l.cursor
+}
+
+// Selected returns the item under the cursor
+func (l *List[T]) Selected() (T, bool) {
+	if len(l.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return l.items[l.cursor], true
+}
+
+// Select moves the cursor to index i and scrolls it into view
+func (l *List[T]) Select(i int) {
+	l.cursor = clamp(i, 0, len(l.items)-1)
+	if l.Height <= 0 {
+		l.offset = 0
+		return
+	}
+	if l.cursor < l.offset {
+		l.offset = l.cursor
+	}
+	if l.cursor >= l.offset+l.Height {
+		l.offset = l.cursor - l.Height + 1
+	}
+}
+
+func (l *List[T]) pageSize() int {
+	if l.Height > 0 {
+		return l.Height
+	}
+	return max(len(l.items), 1)
+}
+
+func (l *List[T]) onUp(msg mvct.KeyMsg) mvct.Cmd {
+	l.Select(l.cursor - 1)
+	return nil
+}
+
+func (l *List[T]) onDown(msg mvct.KeyMsg) mvct.Cmd {
+	l.Select(l.cursor + 1)
+	return nil
+}
+
+func (l *List[T]) onTop(msg mvct.KeyMsg) mvct.Cmd {
+	l.Select(0)
+	return nil
+}
+
+func (l *List[T]) onBottom(msg mvct.KeyMsg) mvct.Cmd {
+	l.Select(len(l.items) - 1)
+	return nil
+}
+
+func (l *List[T]) onPageUp(msg mvct.KeyMsg) mvct.Cmd {
+	l.Select(l.cursor - l.pageSize())
+	return nil
+}
+
+func (l *List[T]) onPageDown(msg mvct.KeyMsg) mvct.Cmd {
+	l.Select(l.cursor + l.pageSize())
+	return nil
+}
+
+func (l *List[T]) onEnter(msg mvct.KeyMsg) mvct.Cmd {
+	item, ok := l.Selected()
+	if !ok || l.onSelect == nil {
+		return nil
+	}
+	return l.onSelect(l.cursor, item)
+}
@@ -0,0 +1,95 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michael-duren/mvct"
+)
+
+func press(handlers mvct.KeyHandlers, key string) mvct.Cmd {
+	handler, ok := handlers[key]
+	if !ok {
+		return nil
+	}
+	return handler(keyMsg(key))
+}
+
+func keyMsg(key string) mvct.KeyMsg {
+	for _, k := range []mvct.KeyMsg{
+		{Type: mvct.KeyUp}, {Type: mvct.KeyDown}, {Type: mvct.KeyLeft}, {Type: mvct.KeyRight},
+		{Type: mvct.KeyEnter}, {Type: mvct.KeyBackspace}, {Type: mvct.KeyTab}, {Type: mvct.KeyShiftTab},
+		{Type: mvct.KeyHome}, {Type: mvct.KeyEnd},
+	} {
+		if k.String() == key {
+			return k
+		}
+	}
+	return mvct.KeyMsg{Type: mvct.KeyRunes, Runes: []rune(key)}
+}
+
+func TestListNavigation(t *testing.T) {
+	list := NewList([]string{"one", "two", "three"}, nil)
+	handlers := mvct.KeyHandlers{}
+	list.Init(handlers)
+
+	press(handlers, "down")
+	press(handlers, "j")
+	if list.Cursor() != 2 {
+		t.Errorf("expected cursor 2, got %d", list.Cursor())
+	}
+
+	press(handlers, "down")
+	if list.Cursor() != 2 {
+		t.Errorf("cursor should stop at the last item, got %d", list.Cursor())
+	}
+
+	press(handlers, "g")
+	if item, _ := list.Selected(); item != "one" {
+		t.Errorf("expected 'one' selected, got %q", item)
+	}
+}
+
+func TestListSelect(t *testing.T) {
+	var selected string
+	list := NewList([]string{"a", "b"}, nil).OnSelect(func(i int, item string) mvct.Cmd {
+		selected = item
+		return nil
+	})
+	handlers := mvct.KeyHandlers{}
+	list.Init(handlers)
+
+	press(handlers, "down")
+	press(handlers, "enter")
+	if selected != "b" {
+		t.Errorf("expected 'b' to be selected, got %q", selected)
+	}
+}
+
+func TestListScrolling(t *testing.T) {
+	list := NewList([]int{1, 2, 3, 4, 5}, nil)
+	list.Height = 2
+	list.Select(3)
+
+	view := list.View()
+	if strings.Contains(view, "1") || !strings.Contains(view, "3") || !strings.Contains(view, "4") {
+		t.Errorf("expected rows 3 and 4 to be visible, got:\n%s", view)
+	}
+
+	list.SetItems([]int{1})
+	if list.Cursor() != 0 {
+		t.Errorf("SetItems should clamp the cursor, got %d", list.Cursor())
+	}
+}
+
+func TestListBlurred(t *testing.T) {
+	list := NewList([]string{"a", "b"}, nil)
+	handlers := mvct.KeyHandlers{}
+	list.Init(handlers)
+	list.Blur()
+
+	press(handlers, "down")
+	if list.Cursor() != 0 {
+		t.Error("blurred list should ignore keys")
+	}
+}
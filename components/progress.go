@@ -0,0 +1,63 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/michael-duren/mvct"
+)
+
+// Progress renders a horizontal progress bar
+type Progress struct {
+	Styles *Styles
+	// Width is the width of the bar without the percentage
+	Width int
+	// ShowPercentage renders the percentage after the bar
+	ShowPercentage bool
+	Full           rune
+	Empty          rune
+
+	percent float64
+}
+
+// NewProgress creates a progress bar of the given width
+func NewProgress(width int) *Progress {
+	return &Progress{
+		Width:          width,
+		ShowPercentage: true,
+		Full:           '█',
+		Empty:          '░',
+	}
+}
+
+// Init does nothing, progress bars are updated with SetPercent
+func (p *Progress) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	return nil
+}
+
+// Bindings returns nil, progress bars don't handle keys
+func (p *Progress) Bindings() []mvct.KeyBinding {
+	return nil
+}
+
+func (p *Progress) View() string {
+	styles := p.Styles.Resolve()
+	filled := int(p.percent * float64(p.Width))
+
+	bar := styles.BarFull.Render(strings.Repeat(string(p.Full), filled)) +
+		styles.BarEmpty.Render(strings.Repeat(string(p.Empty), p.Width-filled))
+	if !p.ShowPercentage {
+		return bar
+	}
+	return bar + styles.Muted.Render(fmt.Sprintf(" %3.0f%%", p.percent*100))
+}
+
+// Percent returns the progress from 0 to 1
+func (p *Progress) Percent() float64 {
+	return p.percent
+}
+
+// SetPercent sets the progress, values are clamped between 0 and 1
+func (p *Progress) SetPercent(percent float64) {
+	p.percent = min(max(percent, 0), 1)
+}
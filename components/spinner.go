@@ -0,0 +1,110 @@
+package components
+
+import (
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-duren/mvct"
+)
+
+// SpinnerFrames is a set of frames and the delay between them
+type SpinnerFrames struct {
+	Frames   []string
+	Interval time.Duration
+}
+
+var (
+	Dots = SpinnerFrames{
+		Frames:   []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+		Interval: 80 * time.Millisecond,
+	}
+	Line = SpinnerFrames{
+		Frames:   []string{"|", "/", "-", "\\"},
+		Interval: 100 * time.Millisecond,
+	}
+)
+
+// SpinnerTickMsg advances a spinner by one frame. The owning controller
+// forwards it to the spinner's OnSpinnerTickMsg
+type SpinnerTickMsg struct {
+	ID   int
+	Time time.Time
+}
+
+var lastSpinnerID atomic.Int64
+
+// Spinner is an animated activity indicator
+type Spinner struct {
+	Styles *Styles
+	Frames SpinnerFrames
+	// Label is rendered after the spinner
+	Label string
+
+	id      int
+	frame   int
+	running bool
+}
+
+// NewSpinner creates a spinner using the Dots frames
+func NewSpinner() *Spinner {
+	return &Spinner{
+		Frames: Dots,
+		id:     int(lastSpinnerID.Add(1)),
+	}
+}
+
+// Init starts the spinner
+func (s *Spinner) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	return s.Start()
+}
+
+// Bindings returns nil, spinners don't handle keys
+func (s *Spinner) Bindings() []mvct.KeyBinding {
+	return nil
+}
+
+func (s *Spinner) View() string {
+	styles := s.Styles.Resolve()
+	frame := styles.Header.Render(s.Frames.Frames[s.frame%len(s.Frames.Frames)])
+	if s.Label == "" {
+		return frame
+	}
+	return frame + " " + styles.Muted.Render(s.Label)
+}
+
+// Start starts the animation and returns the first tick
+func (s *Spinner) Start() mvct.Cmd {
+	if s.running {
+		return nil
+	}
+	s.running = true
+	return s.tick()
+}
+
+// Stop stops the animation, pending ticks are ignored
+func (s *Spinner) Stop() {
+	s.running = false
+}
+
+// Running reports whether the spinner is animating
+func (s *Spinner) Running() bool {
+	return s.running
+}
+
+// OnSpinnerTickMsg advances the spinner and schedules the next frame.
+// Ticks for other spinners are ignored
+func (s *Spinner) OnSpinnerTickMsg(msg SpinnerTickMsg) mvct.Cmd {
+	if msg.ID != s.id || !s.running {
+		return nil
+	}
+	s.frame = (s.frame + 1) % len(s.Frames.Frames)
+	return s.tick()
+}
+
+func (s *Spinner) tick() mvct.Cmd {
+	id := s.id
+	return mvct.Tick(s.Frames.Interval, func(t time.Time) tea.Msg {
+		return SpinnerTickMsg{ID: id, Time: t}
+	})
+}
@@ -0,0 +1,85 @@
+package components
+
+import (
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/michael-duren/mvct"
+)
+
+// Styles holds the lipgloss styles used to render components. Every
+// component has a Styles field, nil follows the current mvct theme and a
+// non-nil value pins custom styles
+type Styles struct {
+	Normal      lipgloss.Style
+	Selected    lipgloss.Style
+	Muted       lipgloss.Style
+	Header      lipgloss.Style
+	Cursor      lipgloss.Style
+	Placeholder lipgloss.Style
+	Border      lipgloss.Style
+	ActiveTab   lipgloss.Style
+	InactiveTab lipgloss.Style
+	BarFull     lipgloss.Style
+	BarEmpty    lipgloss.Style
+	HelpKey     lipgloss.Style
+	HelpDesc    lipgloss.Style
+	Error       lipgloss.Style
+}
+
+// Resolve returns s, or the styles of the current theme when s is nil
+func (s *Styles) Resolve() Styles {
+	if s != nil {
+		return *s
+	}
+	return DefaultStyles()
+}
+
+// themed caches the styles of the current theme until it changes
+var themed struct {
+	sync.Mutex
+	version uint64
+	built   bool
+	styles  Styles
+}
+
+// DefaultStyles returns the component styles of the current theme
+func DefaultStyles() Styles {
+	themed.Lock()
+	defer themed.Unlock()
+
+	if version := mvct.ThemeVersion(); !themed.built || themed.version != version {
+		themed.styles = ThemeStyles(mvct.CurrentTheme())
+		themed.version = version
+		themed.built = true
+	}
+	return themed.styles
+}
+
+// ThemeStyles builds the component styles from the tokens of a theme.
+// Without color support selection and errors are also set apart with
+// reverse and bold
+func ThemeStyles(theme mvct.Theme) Styles {
+	styles := Styles{
+		Normal:      theme.Style(theme.Text),
+		Selected:    theme.Style(theme.Selected).Bold(true),
+		Muted:       theme.Style(theme.Muted),
+		Header:      theme.Style(theme.Primary).Bold(true),
+		Cursor:      lipgloss.NewStyle().Reverse(true),
+		Placeholder: theme.Style(theme.Muted).Italic(true),
+		Border:      lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(theme.Border.Color()),
+		ActiveTab:   theme.Style(theme.Primary).Bold(true).Underline(true).Padding(0, 1),
+		InactiveTab: theme.Style(theme.Muted).Padding(0, 1),
+		BarFull:     theme.Style(theme.Primary),
+		BarEmpty:    theme.Style(theme.Border),
+		HelpKey:     theme.Style(theme.Text),
+		HelpDesc:    theme.Style(theme.Muted),
+		Error:       theme.Style(theme.Error),
+	}
+
+	if mvct.NoColor() {
+		styles.Selected = styles.Selected.Reverse(true)
+		styles.Error = styles.Error.Bold(true)
+	}
+	return styles
+}
@@ -0,0 +1,185 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/michael-duren/mvct"
+)
+
+// Column describes a table column. A Width of 0 sizes the column to its
+// widest cell
+type Column struct {
+	Title string
+	Width int
+}
+
+// Table shows rows of cells under a header with a row cursor
+type Table struct {
+	focusState
+
+	Styles *Styles
+	// Height is the number of rows shown under the header, 0 shows all rows
+	Height int
+
+	columns  []Column
+	rows     [][]string
+	cursor   int
+	offset   int
+	onSelect func(index int, row []string) mvct.Cmd
+}
+
+// NewTable creates a table with the given columns
+func NewTable(columns ...Column) *Table {
+	return &Table{
+		columns: columns,
+	}
+}
+
+// OnSelect sets the function called when enter is pressed on a row
+func (t *Table) OnSelect(fn func(index int, row []string) mvct.Cmd) *Table {
+	t.onSelect = fn
+	return t
+}
+
+func (t *Table) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	mvct.Bind(handlers, t.Focused, t.Bindings()...)
+	return nil
+}
+
+func (t *Table) Bindings() []mvct.KeyBinding {
+	return []mvct.KeyBinding{
+		mvct.Key("up", "k").To(t.onUp).Help("up"),
+		mvct.Key("down", "j").To(t.onDown).Help("down"),
+		mvct.Key("home", "g").To(t.onTop),
+		mvct.Key("end", "G").To(t.onBottom),
+		mvct.Key("enter").To(t.onEnter).Help("select"),
+	}
+}
+
+func (t *Table) View() string {
+	styles := t.Styles.Resolve()
+	widths := t.columnWidths()
+
+	header := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		header[i] = fitCell(column.Title, widths[i])
+	}
+
+	lines := []string{
+		styles.Header.Render(strings.Join(header, " ")),
+	}
+
+	end := len(t.rows)
+	if t.Height > 0 {
+		end = min(t.offset+t.Height, len(t.rows))
+	}
+	for i := t.offset; i < end; i++ {
+		cells := make([]string, len(t.columns))
+		for j := range t.columns {
+			cell := ""
+			if j < len(t.rows[i]) {
+				cell = t.rows[i][j]
+			}
+			cells[j] = fitCell(cell, widths[j])
+		}
+
+		style := styles.Normal
+		if i == t.cursor && t.Focused() {
+			style = styles.Selected
+		}
+		lines = append(lines, style.Render(strings.Join(cells, " ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Rows returns the rows of the table
+func (t *Table) Rows() [][]string {
+	return t.rows
+}
+
+// SetRows replaces the rows, keeping the cursor in range
+func (t *Table) SetRows(rows [][]string) {
+	t.rows = rows
+	t.Select(t.cursor)
+}
+
+// Cursor returns the index of the row under the cursor
+func (t *Table) Cursor() int {
+	return t.cursor
+}
+
+// SelectedRow returns the row under the cursor
+func (t *Table) SelectedRow() ([]string, bool) {
+	if len(t.rows) == 0 {
+		return nil, false
+	}
+	return t.rows[t.cursor], true
+}
+
+// Select moves the cursor to row i and scrolls it into view
+func (t *Table) Select(i int) {
+	t.cursor = clamp(i, 0, len(t.rows)-1)
+	if t.Height <= 0 {
+		t.offset = 0
+		return
+	}
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.cursor >= t.offset+t.Height {
+		t.offset = t.cursor - t.Height + 1
+	}
+}
+
+func (t *Table) columnWidths() []int {
+	widths := make([]int, len(t.columns))
+	for i, column := range t.columns {
+		if column.Width > 0 {
+			widths[i] = column.Width
+			continue
+		}
+		widths[i] = ansi.StringWidth(column.Title)
+		for _, row := range t.rows {
+			if i < len(row) {
+				widths[i] = max(widths[i], ansi.StringWidth(row[i]))
+			}
+		}
+	}
+	return widths
+}
+
+// fitCell truncates or pads s to exactly width cells
+func fitCell(s string, width int) string {
+	s = ansi.Truncate(s, width, "…")
+	return s + strings.Repeat(" ", max(width-ansi.StringWidth(s), 0))
+}
+
+func (t *Table) onUp(msg mvct.KeyMsg) mvct.Cmd {
+	t.Select(t.cursor - 1)
+	return nil
+}
+
+func (t *Table) onDown(msg mvct.KeyMsg) mvct.Cmd {
+	t.Select(t.cursor + 1)
+	return nil
+}
+
+func (t *Table) onTop(msg mvct.KeyMsg) mvct.Cmd {
+	t.Select(0)
+	return nil
+}
+
+func (t *Table) onBottom(msg mvct.KeyMsg) mvct.Cmd {
+	t.Select(len(t.rows) - 1)
+	return nil
+}
+
+func (t *Table) onEnter(msg mvct.KeyMsg) mvct.Cmd {
+	row, ok := t.SelectedRow()
+	if !ok || t.onSelect == nil {
+		return nil
+	}
+	return t.onSelect(t.cursor, row)
+}
@@ -0,0 +1,90 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/michael-duren/mvct"
+)
+
+// Tabs renders a row of tab titles with one active tab
+type Tabs struct {
+	focusState
+
+	Styles    *Styles
+	Separator string
+
+	titles   []string
+	active   int
+	onChange func(index int) mvct.Cmd
+}
+
+// NewTabs creates tabs with the first tab active
+func NewTabs(titles ...string) *Tabs {
+	return &Tabs{
+		Separator: "│",
+		titles:    titles,
+	}
+}
+
+// OnChange sets the function called when the active tab changes
+func (t *Tabs) OnChange(fn func(index int) mvct.Cmd) *Tabs {
+	t.onChange = fn
+	return t
+}
+
+func (t *Tabs) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	mvct.Bind(handlers, t.Focused, t.Bindings()...)
+	return nil
+}
+
+func (t *Tabs) Bindings() []mvct.KeyBinding {
+	return []mvct.KeyBinding{
+		mvct.Key("left", "h").To(t.onPrev).Help("previous tab"),
+		mvct.Key("right", "l").To(t.onNext).Help("next tab"),
+	}
+}
+
+func (t *Tabs) View() string {
+	styles := t.Styles.Resolve()
+	rendered := make([]string, len(t.titles))
+	for i, title := range t.titles {
+		if i == t.active {
+			rendered[i] = styles.ActiveTab.Render(title)
+		} else {
+			rendered[i] = styles.InactiveTab.Render(title)
+		}
+	}
+	return strings.Join(rendered, styles.Muted.Render(t.Separator))
+}
+
+// Active returns the index of the active tab
+func (t *Tabs) Active() int {
+	return t.active
+}
+
+// SetActive activates tab i and calls the OnChange function if it changed
+func (t *Tabs) SetActive(i int) mvct.Cmd {
+	i = clamp(i, 0, len(t.titles)-1)
+	if i == t.active {
+		return nil
+	}
+	t.active = i
+	if t.onChange == nil {
+		return nil
+	}
+	return t.onChange(i)
+}
+
+func (t *Tabs) onPrev(msg mvct.KeyMsg) mvct.Cmd {
+	if len(t.titles) == 0 {
+		return nil
+	}
+	return t.SetActive((t.active - 1 + len(t.titles)) % len(t.titles))
+}
+
+func (t *Tabs) onNext(msg mvct.KeyMsg) mvct.Cmd {
+	if len(t.titles) == 0 {
+		return nil
+	}
+	return t.SetActive((t.active + 1) % len(t.titles))
+}
@@ -0,0 +1,238 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/michael-duren/mvct"
+)
+
+// TextArea is a multi line text field. Like TextInput, typed characters
+// arrive through OnKeyMsg
+type TextArea struct {
+	focusState
+
+	Styles      *Styles
+	Placeholder string
+	// Height is the number of lines shown, 0 shows every line
+	Height int
+	// ShowLineNumbers prefixes every line with its number
+	ShowLineNumbers bool
+
+	lines  [][]rune
+	row    int
+	col    int
+	offset int
+}
+
+// NewTextArea creates an empty text area
+func NewTextArea() *TextArea {
+	return &TextArea{
+		lines: [][]rune{{}},
+	}
+}
+
+func (t *TextArea) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	mvct.Bind(handlers, t.Focused, t.Bindings()...)
+	return nil
+}
+
+func (t *TextArea) Bindings() []mvct.KeyBinding {
+	return []mvct.KeyBinding{
+		mvct.Key("up").To(t.onUp),
+		mvct.Key("down").To(t.onDown),
+		mvct.Key("left").To(t.onLeft),
+		mvct.Key("right").To(t.onRight),
+		mvct.Key("home", "ctrl+a").To(t.onHome),
+		mvct.Key("end", "ctrl+e").To(t.onEnd),
+		mvct.Key("enter").To(t.onEnter).Help("new line"),
+		mvct.Key("backspace").To(t.onBackspace),
+		mvct.Key("delete").To(t.onDelete),
+	}
+}
+
+// OnKeyMsg inserts typed characters at the cursor
+func (t *TextArea) OnKeyMsg(msg mvct.KeyMsg) mvct.Cmd {
+	if !t.Focused() || msg.Alt {
+		return nil
+	}
+	if msg.Type != mvct.KeyRunes && msg.Type != mvct.KeySpace {
+		return nil
+	}
+
+	t.Insert(string(msg.Runes))
+	return mvct.Handled()
+}
+
+func (t *TextArea) View() string {
+	styles := t.Styles.Resolve()
+	if t.Value() == "" && t.Placeholder != "" && !t.Focused() {
+		return styles.Placeholder.Render(t.Placeholder)
+	}
+
+	end := len(t.lines)
+	if t.Height > 0 {
+		end = min(t.offset+t.Height, len(t.lines))
+	}
+
+	rows := make([]string, 0, end-t.offset)
+	for i := t.offset; i < end; i++ {
+		var b strings.Builder
+		if t.ShowLineNumbers {
+			b.WriteString(styles.Muted.Render(padLeft(i+1, len(t.lines))) + " ")
+		}
+
+		line := t.lines[i]
+		for j, r := range line {
+			if t.Focused() && i == t.row && j == t.col {
+				b.WriteString(styles.Cursor.Render(string(r)))
+			} else {
+				b.WriteString(styles.Normal.Render(string(r)))
+			}
+		}
+		if t.Focused() && i == t.row && t.col == len(line) {
+			b.WriteString(styles.Cursor.Render(" "))
+		}
+		rows = append(rows, b.String())
+	}
+	return strings.Join(rows, "\n")
+}
+
+// Value returns the text with lines joined by newlines
+func (t *TextArea) Value() string {
+	lines := make([]string, len(t.lines))
+	for i, line := range t.lines {
+		lines[i] = string(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetValue replaces the text and moves the cursor to the end
+func (t *TextArea) SetValue(s string) {
+	t.lines = [][]rune{{}}
+	t.row, t.col, t.offset = 0, 0, 0
+	t.Insert(s)
+}
+
+// Cursor returns the line and column of the cursor
+func (t *TextArea) Cursor() (row, col int) {
+	return t.row, t.col
+}
+
+// Insert inserts s at the cursor, newlines split the current line
+func (t *TextArea) Insert(s string) {
+	for i, part := range strings.Split(ansi.Strip(s), "\n") {
+		if i > 0 {
+			t.newline()
+		}
+		runes := []rune(part)
+		line := t.lines[t.row]
+
+		inserted := make([]rune, 0, len(line)+len(runes))
+		inserted = append(inserted, line[:t.col]...)
+		inserted = append(inserted, runes...)
+		inserted = append(inserted, line[t.col:]...)
+		t.lines[t.row] = inserted
+		t.col += len(runes)
+	}
+}
+
+func (t *TextArea) newline() {
+	line := t.lines[t.row]
+	before := append([]rune(nil), line[:t.col]...)
+	after := append([]rune(nil), line[t.col:]...)
+
+	lines := make([][]rune, 0, len(t.lines)+1)
+	lines = append(lines, t.lines[:t.row]...)
+	lines = append(lines, before, after)
+	lines = append(lines, t.lines[t.row+1:]...)
+	t.lines = lines
+	t.moveTo(t.row+1, 0)
+}
+
+func (t *TextArea) moveTo(row, col int) {
+	t.row = clamp(row, 0, len(t.lines)-1)
+	t.col = clamp(col, 0, len(t.lines[t.row]))
+	if t.Height <= 0 {
+		return
+	}
+	if t.row < t.offset {
+		t.offset = t.row
+	}
+	if t.row >= t.offset+t.Height {
+		t.offset = t.row - t.Height + 1
+	}
+}
+
+func (t *TextArea) onUp(msg mvct.KeyMsg) mvct.Cmd {
+	t.moveTo(t.row-1, t.col)
+	return nil
+}
+
+func (t *TextArea) onDown(msg mvct.KeyMsg) mvct.Cmd {
+	t.moveTo(t.row+1, t.col)
+	return nil
+}
+
+func (t *TextArea) onLeft(msg mvct.KeyMsg) mvct.Cmd {
+	if t.col == 0 && t.row > 0 {
+		t.moveTo(t.row-1, len(t.lines[t.row-1]))
+		return nil
+	}
+	t.moveTo(t.row, t.col-1)
+	return nil
+}
+
+func (t *TextArea) onRight(msg mvct.KeyMsg) mvct.Cmd {
+	if t.col == len(t.lines[t.row]) && t.row < len(t.lines)-1 {
+		t.moveTo(t.row+1, 0)
+		return nil
+	}
+	t.moveTo(t.row, t.col+1)
+	return nil
+}
+
+func (t *TextArea) onHome(msg mvct.KeyMsg) mvct.Cmd {
+	t.moveTo(t.row, 0)
+	return nil
+}
+
+func (t *TextArea) onEnd(msg mvct.KeyMsg) mvct.Cmd {
+	t.moveTo(t.row, len(t.lines[t.row]))
+	return nil
+}
+
+func (t *TextArea) onEnter(msg mvct.KeyMsg) mvct.Cmd {
+	t.newline()
+	return nil
+}
+
+func (t *TextArea) onBackspace(msg mvct.KeyMsg) mvct.Cmd {
+	if t.col > 0 {
+		line := t.lines[t.row]
+		t.lines[t.row] = append(line[:t.col-1], line[t.col:]...)
+		t.moveTo(t.row, t.col-1)
+		return nil
+	}
+	if t.row > 0 {
+		previous := t.lines[t.row-1]
+		col := len(previous)
+		t.lines[t.row-1] = append(previous, t.lines[t.row]...)
+		t.lines = append(t.lines[:t.row], t.lines[t.row+1:]...)
+		t.moveTo(t.row-1, col)
+	}
+	return nil
+}
+
+func (t *TextArea) onDelete(msg mvct.KeyMsg) mvct.Cmd {
+	line := t.lines[t.row]
+	if t.col < len(line) {
+		t.lines[t.row] = append(line[:t.col], line[t.col+1:]...)
+		return nil
+	}
+	if t.row < len(t.lines)-1 {
+		t.lines[t.row] = append(line, t.lines[t.row+1]...)
+		t.lines = append(t.lines[:t.row+1], t.lines[t.row+2:]...)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/michael-duren/mvct"
+)
+
+// TextInput is a single line text field. Editing keys are registered as
+// key bindings, typed characters arrive through OnKeyMsg which the owning
+// controller forwards from its own OnKeyMsg
+type TextInput struct {
+	focusState
+
+	Styles      *Styles
+	Prompt      string
+	Placeholder string
+	// Width is the number of characters shown, 0 shows the whole value
+	Width int
+	// CharLimit is the maximum length of the value, 0 means no limit
+	CharLimit int
+	// Mask replaces every character when rendering, e.g. '*' for passwords
+	Mask rune
+
+	value  []rune
+	cursor int
+	offset int
+}
+
+// NewTextInput creates an empty text input
+func NewTextInput() *TextInput {
+	return &TextInput{
+		Prompt: "> ",
+	}
+}
+
+func (t *TextInput) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	mvct.Bind(handlers, t.Focused, t.Bindings()...)
+	return nil
+}
+
+func (t *TextInput) Bindings() []mvct.KeyBinding {
+	return []mvct.KeyBinding{
+		mvct.Key("left", "ctrl+b").To(t.onLeft),
+		mvct.Key("right", "ctrl+f").To(t.onRight),
+		mvct.Key("home", "ctrl+a").To(t.onHome),
+		mvct.Key("end", "ctrl+e").To(t.onEnd),
+		mvct.Key("backspace").To(t.onBackspace),
+		mvct.Key("delete", "ctrl+d").To(t.onDelete),
+		mvct.Key("ctrl+u").To(t.onClearBefore).Help("clear"),
+		mvct.Key("ctrl+k").To(t.onClearAfter),
+	}
+}
+
+// OnKeyMsg inserts typed characters at the cursor. It returns
+// mvct.Handled for consumed keys so global handlers such as a quit key
+// don't fire while typing
+func (t *TextInput) OnKeyMsg(msg mvct.KeyMsg) mvct.Cmd {
+	if !t.Focused() || msg.Alt {
+		return nil
+	}
+	if msg.Type != mvct.KeyRunes && msg.Type != mvct.KeySpace {
+		return nil
+	}
+
+	t.Insert(string(msg.Runes))
+	return mvct.Handled()
+}
+
+func (t *TextInput) View() string {
+	styles := t.Styles.Resolve()
+	var b strings.Builder
+	b.WriteString(t.Prompt)
+
+	if len(t.value) == 0 && t.Placeholder != "" {
+		if t.Focused() {
+			b.WriteString(styles.Cursor.Render(" "))
+		}
+		b.WriteString(styles.Placeholder.Render(t.Placeholder))
+		return b.String()
+	}
+
+	display := t.display()
+	end := len(display)
+	if t.Width > 0 {
+		end = min(t.offset+t.Width, len(display))
+	}
+
+	for i := t.offset; i < end; i++ {
+		if t.Focused() && i == t.cursor {
+			b.WriteString(styles.Cursor.Render(string(display[i])))
+		} else {
+			b.WriteString(styles.Normal.Render(string(display[i])))
+		}
+	}
+	if t.Focused() && t.cursor == len(display) {
+		b.WriteString(styles.Cursor.Render(" "))
+	}
+
+	return b.String()
+}
+
+// Value returns the text of the input
+func (t *TextInput) Value() string {
+	return string(t.value)
+}
+
+// SetValue replaces the text and moves the cursor to the end
+func (t *TextInput) SetValue(s string) {
+	t.value = nil
+	t.cursor = 0
+	t.Insert(s)
+}
+
+// Reset clears the input
+func (t *TextInput) Reset() {
+	t.SetValue("")
+}
+
+// Cursor returns the position of the cursor
+func (t *TextInput) Cursor() int {
+	return t.cursor
+}
+
+// SetCursor moves the cursor to position i
+func (t *TextInput) SetCursor(i int) {
+	t.cursor = clamp(i, 0, len(t.value))
+	if t.Width <= 0 {
+		t.offset = 0
+		return
+	}
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.cursor >= t.offset+t.Width {
+		t.offset = t.cursor - t.Width + 1
+	}
+}
+
+// Insert inserts s at the cursor, respecting CharLimit
+func (t *TextInput) Insert(s string) {
+	runes := []rune(ansi.Strip(s))
+	if t.CharLimit > 0 {
+		runes = runes[:min(len(runes), max(t.CharLimit-len(t.value), 0))]
+	}
+
+	value := make([]rune, 0, len(t.value)+len(runes))
+	value = append(value, t.value[:t.cursor]...)
+	value = append(value, runes...)
+	value = append(value, t.value[t.cursor:]...)
+	t.value = value
+	t.SetCursor(t.cursor + len(runes))
+}
+
+func (t *TextInput) display() []rune {
+	if t.Mask == 0 {
+		return t.value
+	}
+	return []rune(strings.Repeat(string(t.Mask), len(t.value)))
+}
+
+func (t *TextInput) onLeft(msg mvct.KeyMsg) mvct.Cmd {
+	t.SetCursor(t.cursor - 1)
+	return nil
+}
+
+func (t *TextInput) onRight(msg mvct.KeyMsg) mvct.Cmd {
+	t.SetCursor(t.cursor + 1)
+	return nil
+}
+
+func (t *TextInput) onHome(msg mvct.KeyMsg) mvct.Cmd {
+	t.SetCursor(0)
+	return nil
+}
+
+func (t *TextInput) onEnd(msg mvct.KeyMsg) mvct.Cmd {
+	t.SetCursor(len(t.value))
+	return nil
+}
+
+func (t *TextInput) onBackspace(msg mvct.KeyMsg) mvct.Cmd {
+	if t.cursor > 0 {
+		t.value = append(t.value[:t.cursor-1], t.value[t.cursor:]...)
+		t.SetCursor(t.cursor - 1)
+	}
+	return nil
+}
+
+func (t *TextInput) onDelete(msg mvct.KeyMsg) mvct.Cmd {
+	if t.cursor < len(t.value) {
+		t.value = append(t.value[:t.cursor], t.value[t.cursor+1:]...)
+	}
+	return nil
+}
+
+func (t *TextInput) onClearBefore(msg mvct.KeyMsg) mvct.Cmd {
+	t.value = t.value[t.cursor:]
+	t.SetCursor(0)
+	return nil
+}
+
+func (t *TextInput) onClearAfter(msg mvct.KeyMsg) mvct.Cmd {
+	t.value = t.value[:t.cursor]
+	return nil
+}
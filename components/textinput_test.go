@@ -0,0 +1,92 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/michael-duren/mvct"
+)
+
+func typeText(c interface{ OnKeyMsg(mvct.KeyMsg) mvct.Cmd }, s string) {
+	for _, r := range s {
+		c.OnKeyMsg(mvct.KeyMsg{Type: mvct.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestTextInputEditing(t *testing.T) {
+	input := NewTextInput()
+	handlers := mvct.KeyHandlers{}
+	input.Init(handlers)
+
+	typeText(input, "helo")
+	press(handlers, "left")
+	typeText(input, "l")
+	if input.Value() != "hello" {
+		t.Errorf("expected 'hello', got %q", input.Value())
+	}
+
+	press(handlers, "end")
+	press(handlers, "backspace")
+	if input.Value() != "hell" {
+		t.Errorf("expected 'hell', got %q", input.Value())
+	}
+
+	press(handlers, "home")
+	press(handlers, "ctrl+k")
+	if input.Value() != "" {
+		t.Errorf("expected ctrl+k to clear the input, got %q", input.Value())
+	}
+}
+
+func TestTextInputConsumesTyping(t *testing.T) {
+	input := NewTextInput()
+
+	if cmd := input.OnKeyMsg(mvct.KeyMsg{Type: mvct.KeyRunes, Runes: []rune("q")}); cmd == nil {
+		t.Error("typed characters should be reported as handled")
+	}
+
+	input.Blur()
+	if cmd := input.OnKeyMsg(mvct.KeyMsg{Type: mvct.KeyRunes, Runes: []rune("q")}); cmd != nil {
+		t.Error("blurred input should not handle typing")
+	}
+	if input.Value() != "q" {
+		t.Errorf("expected 'q', got %q", input.Value())
+	}
+}
+
+func TestTextInputLimitAndMask(t *testing.T) {
+	input := NewTextInput()
+	input.CharLimit = 3
+	input.Mask = '*'
+	input.Prompt = ""
+	input.Blur()
+
+	input.SetValue("secret")
+	if input.Value() != "sec" {
+		t.Errorf("expected value to be limited to 'sec', got %q", input.Value())
+	}
+	if view := input.View(); view != "***" {
+		t.Errorf("expected masked view '***', got %q", view)
+	}
+}
+
+func TestTextArea(t *testing.T) {
+	area := NewTextArea()
+	handlers := mvct.KeyHandlers{}
+	area.Init(handlers)
+
+	typeText(area, "ab")
+	press(handlers, "enter")
+	typeText(area, "cd")
+	if area.Value() != "ab\ncd" {
+		t.Errorf("expected 'ab\\ncd', got %q", area.Value())
+	}
+
+	press(handlers, "home")
+	press(handlers, "backspace")
+	if area.Value() != "abcd" {
+		t.Errorf("expected backspace at line start to join lines, got %q", area.Value())
+	}
+	if row, col := area.Cursor(); row != 0 || col != 2 {
+		t.Errorf("expected cursor at 0,2 got %d,%d", row, col)
+	}
+}
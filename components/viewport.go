@@ -0,0 +1,147 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/michael-duren/mvct"
+)
+
+// Viewport shows a scrollable window onto a block of text
+type Viewport struct {
+	focusState
+
+	// Width truncates lines to the given width, 0 leaves them as they are
+	Width int
+	// Height is the number of lines shown
+	Height int
+
+	lines  []string
+	offset int
+}
+
+// NewViewport creates a viewport with the given size
+func NewViewport(width, height int) *Viewport {
+	return &Viewport{
+		Width:  width,
+		Height: height,
+	}
+}
+
+func (v *Viewport) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	mvct.Bind(handlers, v.Focused, v.Bindings()...)
+	return nil
+}
+
+func (v *Viewport) Bindings() []mvct.KeyBinding {
+	return []mvct.KeyBinding{
+		mvct.Key("up", "k").To(v.onUp).Help("scroll up"),
+		mvct.Key("down", "j").To(v.onDown).Help("scroll down"),
+		mvct.Key("pgup", "b").To(v.onPageUp).Help("page up"),
+		mvct.Key("pgdown", "f", " ").To(v.onPageDown).Help("page down"),
+		mvct.Key("ctrl+u").To(v.onHalfPageUp),
+		mvct.Key("ctrl+d").To(v.onHalfPageDown),
+		mvct.Key("home", "g").To(v.onTop),
+		mvct.Key("end", "G").To(v.onBottom),
+	}
+}
+
+func (v *Viewport) View() string {
+	end := len(v.lines)
+	if v.Height > 0 {
+		end = min(v.offset+v.Height, len(v.lines))
+	}
+
+	visible := make([]string, 0, max(v.Height, 0))
+	for _, line := range v.lines[v.offset:end] {
+		if v.Width > 0 {
+			line = ansi.Truncate(line, v.Width, "")
+		}
+		visible = append(visible, line)
+	}
+	for len(visible) < v.Height {
+		visible = append(visible, "")
+	}
+	return strings.Join(visible, "\n")
+}
+
+// SetContent replaces the text shown in the viewport
+func (v *Viewport) SetContent(content string) {
+	v.lines = strings.Split(content, "\n")
+	v.SetOffset(v.offset)
+}
+
+// Offset returns the index of the first visible line
+func (v *Viewport) Offset() int {
+	return v.offset
+}
+
+// SetOffset scrolls so line i is the first visible line
+func (v *Viewport) SetOffset(i int) {
+	v.offset = clamp(i, 0, v.maxOffset())
+}
+
+// AtBottom reports whether the last line is visible
+func (v *Viewport) AtBottom() bool {
+	return v.offset >= v.maxOffset()
+}
+
+// ScrollPercent returns how far the viewport is scrolled, from 0 to 1
+func (v *Viewport) ScrollPercent() float64 {
+	if v.maxOffset() == 0 {
+		return 1
+	}
+	return float64(v.offset) / float64(v.maxOffset())
+}
+
+// GotoBottom scrolls to the last line, useful for following logs
+func (v *Viewport) GotoBottom() {
+	v.SetOffset(v.maxOffset())
+}
+
+func (v *Viewport) maxOffset() int {
+	if v.Height <= 0 {
+		return 0
+	}
+	return max(len(v.lines)-v.Height, 0)
+}
+
+func (v *Viewport) onUp(msg mvct.KeyMsg) mvct.Cmd {
+	v.SetOffset(v.offset - 1)
+	return nil
+}
+
+func (v *Viewport) onDown(msg mvct.KeyMsg) mvct.Cmd {
+	v.SetOffset(v.offset + 1)
+	return nil
+}
+
+func (v *Viewport) onPageUp(msg mvct.KeyMsg) mvct.Cmd {
+	v.SetOffset(v.offset - v.Height)
+	return nil
+}
+
+func (v *Viewport) onPageDown(msg mvct.KeyMsg) mvct.Cmd {
+	v.SetOffset(v.offset + v.Height)
+	return nil
+}
+
+func (v *Viewport) onHalfPageUp(msg mvct.KeyMsg) mvct.Cmd {
+	v.SetOffset(v.offset - v.Height/2)
+	return nil
+}
+
+func (v *Viewport) onHalfPageDown(msg mvct.KeyMsg) mvct.Cmd {
+	v.SetOffset(v.offset + v.Height/2)
+	return nil
+}
+
+func (v *Viewport) onTop(msg mvct.KeyMsg) mvct.Cmd {
+	v.SetOffset(0)
+	return nil
+}
+
+func (v *Viewport) onBottom(msg mvct.KeyMsg) mvct.Cmd {
+	v.GotoBottom()
+	return nil
+}
@@ -0,0 +1,97 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michael-duren/mvct"
+)
+
+func TestViewport(t *testing.T) {
+	viewport := NewViewport(0, 2)
+	viewport.SetContent("1\n2\n3\n4")
+	handlers := mvct.KeyHandlers{}
+	viewport.Init(handlers)
+
+	press(handlers, "j")
+	if viewport.View() != "2\n3" {
+		t.Errorf("expected lines 2 and 3, got %q", viewport.View())
+	}
+
+	press(handlers, "G")
+	if !viewport.AtBottom() || viewport.ScrollPercent() != 1 {
+		t.Error("G should scroll to the bottom")
+	}
+
+	press(handlers, "j")
+	if viewport.Offset() != 2 {
+		t.Errorf("viewport should not scroll past the bottom, got offset %d", viewport.Offset())
+	}
+}
+
+func TestTable(t *testing.T) {
+	table := NewTable(Column{Title: "Name"}, Column{Title: "Age", Width: 3})
+	table.SetRows([][]string{{"Alice", "30"}, {"Bob", "4000"}})
+	handlers := mvct.KeyHandlers{}
+	table.Init(handlers)
+
+	press(handlers, "down")
+	row, _ := table.SelectedRow()
+	if row[0] != "Bob" {
+		t.Errorf("expected Bob to be selected, got %v", row)
+	}
+
+	lines := strings.Split(table.View(), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d lines", len(lines))
+	}
+	if !strings.Contains(lines[2], "40…") {
+		t.Errorf("expected cell to be truncated to the column width, got %q", lines[2])
+	}
+}
+
+func TestSpinnerTick(t *testing.T) {
+	spinner := NewSpinner()
+	if cmd := spinner.Init(mvct.KeyHandlers{}); cmd == nil {
+		t.Fatal("Init should start the spinner")
+	}
+
+	first := spinner.View()
+	if cmd := spinner.OnSpinnerTickMsg(SpinnerTickMsg{ID: spinner.id}); cmd == nil {
+		t.Error("tick should schedule the next frame")
+	}
+	if spinner.View() == first {
+		t.Error("tick should advance the frame")
+	}
+
+	if cmd := spinner.OnSpinnerTickMsg(SpinnerTickMsg{ID: spinner.id + 1}); cmd != nil {
+		t.Error("ticks for other spinners should be ignored")
+	}
+
+	spinner.Stop()
+	if cmd := spinner.OnSpinnerTickMsg(SpinnerTickMsg{ID: spinner.id}); cmd != nil {
+		t.Error("stopped spinner should not schedule ticks")
+	}
+}
+
+func TestProgressAndTabs(t *testing.T) {
+	progress := NewProgress(10)
+	progress.ShowPercentage = false
+	progress.SetPercent(1.5)
+	if progress.Percent() != 1 || !strings.Contains(progress.View(), strings.Repeat("█", 10)) {
+		t.Errorf("expected a full bar, got %q", progress.View())
+	}
+
+	var changed int
+	tabs := NewTabs("One", "Two", "Three").OnChange(func(i int) mvct.Cmd {
+		changed = i
+		return nil
+	})
+	handlers := mvct.KeyHandlers{}
+	tabs.Init(handlers)
+
+	press(handlers, "left")
+	if tabs.Active() != 2 || changed != 2 {
+		t.Errorf("left should wrap to the last tab, got %d", tabs.Active())
+	}
+}
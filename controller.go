@@ -2,6 +2,8 @@ package mvct
 
 import (
 	"context"
+	"reflect"
+	"runtime"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -19,6 +21,35 @@ type Controller interface {
 	View() string
 }
 
+// MessageHandler dispatches one message type to an On* handler
+type MessageHandler struct {
+	Type   reflect.Type
+	Handle func(msg tea.Msg) Cmd
+	// Name identifies the handler in logs
+	Name string
+}
+
+// HandleMsg creates the MessageHandler of an On* method. Generated dispatch
+// tables call it for every handler so a method with the wrong signature
+// fails to compile
+func HandleMsg[T any](fn func(msg T) Cmd) MessageHandler {
+	return MessageHandler{
+		Type: reflect.TypeFor[T](),
+		Handle: func(msg tea.Msg) Cmd {
+			return fn(msg.(T))
+		},
+		Name: runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name(),
+	}
+}
+
+// HandlerTable is implemented by controllers with a dispatch table
+// generated by mvct gen. The application uses the table instead of
+// discovering On* methods with reflection, only On* methods missing from
+// the table are reflected
+type HandlerTable interface {
+	MessageHandlers() []MessageHandler
+}
+
 // Msg wraps the tea.Msg with additional context
 type Msg struct {
 	Inner   tea.Msg
@@ -30,9 +61,15 @@ type Cmd func() Msg
 
 // Navigate should be called by controllers to change routes
 func Navigate(route string) Cmd {
+	return NavigateWith(route, nil)
+}
+
+// NavigateWith changes routes with data for the middleware and the loader
+// of the route, like the id of the entity to show
+func NavigateWith(route string, data map[string]any) Cmd {
 	return func() Msg {
 		return Msg{
-			Inner: NavigateMsg{Route: route},
+			Inner: NavigateMsg{Route: route, Data: data},
 		}
 	}
 }
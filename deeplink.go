@@ -0,0 +1,196 @@
+package mvct
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DeepLinkConfig configures where the start route is read from
+type DeepLinkConfig struct {
+	// Args are the command line arguments, usually os.Args[1:]. The link
+	// is given as --link=<link>, or as the first argument matching a
+	// registered route so a path like /var/log/app is not taken for one.
+	// An optional JSON payload is given as --data=<json> or as an argument
+	// starting with "{". Other arguments are ignored
+	Args []string
+	// Env names an environment variable holding a link, used when Args has
+	// none. The variable with a _PAYLOAD suffix holds the payload
+	Env string
+}
+
+// DeepLink is a route to start on with its parameters and payload
+type DeepLink struct {
+	// Link is the link as given
+	Link string
+	// Route is the registered route the link resolved to
+	Route string
+	// Params holds the query parameters and the :name segments of the
+	// route pattern
+	Params  map[string]string
+	Payload json.RawMessage
+}
+
+// DeepLinkMsg is sent to the controller of the start route after its Init
+type DeepLinkMsg struct {
+	DeepLink
+}
+
+// UseDeepLink starts the application on a route taken from the command
+// line or the environment instead of DefaultRoute. Run resolves the link
+// and sends the middleware a navigation from no route with the params and
+// payload in Context.Data. When the link is invalid or blocked the
+// application starts as usual and shows an error notification
+func (a *Application[M]) UseDeepLink(config DeepLinkConfig) {
+	a.deepLink = &config
+}
+
+// deepLinkFrom returns the link and payload in args or the environment,
+// positional links are only taken when they match a route of r
+func deepLinkFrom(config DeepLinkConfig, r *Router) (link, payload string) {
+	explicit := false
+	for _, arg := range config.Args {
+		switch {
+		case !explicit && strings.HasPrefix(arg, "--link="):
+			link = strings.TrimPrefix(arg, "--link=")
+			explicit = true
+		case link == "" && strings.HasPrefix(arg, "/") && r.matchesLink(arg):
+			link = arg
+		case payload == "" && strings.HasPrefix(arg, "--data="):
+			payload = strings.TrimPrefix(arg, "--data=")
+		case payload == "" && strings.HasPrefix(arg, "{"):
+			payload = arg
+		}
+	}
+	if link != "" {
+		return link, payload
+	}
+	if config.Env != "" {
+		return os.Getenv(config.Env), os.Getenv(config.Env + "_PAYLOAD")
+	}
+	return "", ""
+}
+
+// ResolveDeepLink matches a link against the registered routes. A route
+// may contain :name segments, like /projects/:id, which only deep links
+// match
+func (r *Router) ResolveDeepLink(link, payload string) (DeepLink, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return DeepLink{}, fmt.Errorf("invalid deep link %q: %w", link, err)
+	}
+
+	deepLink := DeepLink{Link: link, Params: map[string]string{}}
+	for key, values := range u.Query() {
+		deepLink.Params[key] = values[len(values)-1]
+	}
+	if payload != "" {
+		if !json.Valid([]byte(payload)) {
+			return DeepLink{}, fmt.Errorf("payload of deep link %q is not JSON", link)
+		}
+		deepLink.Payload = json.RawMessage(payload)
+	}
+
+	route, params, ok := r.routeFor(u.Path)
+	if !ok {
+		return DeepLink{}, fmt.Errorf("route not found: %s", u.Path)
+	}
+	deepLink.Route = route
+	for key, value := range params {
+		deepLink.Params[key] = value
+	}
+	return deepLink, nil
+}
+
+// matchesLink reports whether the path of link matches a registered route
+func (r *Router) matchesLink(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	_, _, ok := r.routeFor(u.Path)
+	return ok
+}
+
+// routeFor returns the registered route matching path with the values of
+// its :name segments
+func (r *Router) routeFor(path string) (string, map[string]string, bool) {
+	if _, ok := r.routes[path]; ok {
+		return path, nil, true
+	}
+
+	// try patterns in a fixed order so the result does not depend on the map
+	patterns := make([]string, 0, len(r.routes))
+	for route := range r.routes {
+		if strings.Contains(route, "/:") {
+			patterns = append(patterns, route)
+		}
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if params, ok := matchRoute(pattern, path); ok {
+			return pattern, params, true
+		}
+	}
+	return "", nil, false
+}
+
+// matchRoute matches a path against a pattern with :name segments
+func matchRoute(pattern, path string) (map[string]string, bool) {
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathParts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternParts) != len(pathParts) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+	for i, part := range patternParts {
+		switch {
+		case strings.HasPrefix(part, ":"):
+			value, err := url.PathUnescape(pathParts[i])
+			if err != nil || value == "" {
+				return nil, false
+			}
+			params[part[1:]] = value
+		case part != pathParts[i]:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// startDeepLink makes the configured deep link the start route, the
+// DeepLinkMsg or the error notification is sent by Init
+func (a *Application[M]) startDeepLink() {
+	if a.deepLink == nil {
+		return
+	}
+	link, payload := deepLinkFrom(*a.deepLink, a.router)
+	if link == "" {
+		return
+	}
+
+	deepLink, err := a.router.ResolveDeepLink(link, payload)
+	if err == nil {
+		err = a.router.start(deepLink.Route, map[string]any{
+			"params":  deepLink.Params,
+			"payload": deepLink.Payload,
+		})
+	}
+	if err != nil {
+		err = fmt.Errorf("cannot open %s: %w", link, err)
+		slog.Error("Deep link failed", "link", link, "error", err)
+		a.Errors = append(a.Errors, err)
+		a.startup = append(a.startup, unwrapCmd(Notify(NotifyError, err.Error())))
+		return
+	}
+
+	slog.Info("Starting on deep link", "link", link, "route", deepLink.Route, "params", deepLink.Params)
+	a.startup = append(a.startup, func() tea.Msg { return DeepLinkMsg{DeepLink: deepLink} })
+}
@@ -0,0 +1,127 @@
+package mvct
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func newDeepLinkApp(args ...string) (*Application[string], *MockController) {
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	project := &MockController{name: "project"}
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.RegisterController("/projects", &MockController{name: "projects"})
+	app.RegisterController("/projects/:id", project)
+	app.UseDeepLink(DeepLinkConfig{Args: args})
+	return app, project
+}
+
+func TestResolveDeepLink(t *testing.T) {
+	app, _ := newDeepLinkApp()
+
+	link, err := app.router.ResolveDeepLink("/projects/42?tab=issues", `{"filter":"open"}`)
+	if err != nil {
+		t.Fatalf("expected the link to resolve, got %v", err)
+	}
+	if link.Route != "/projects/:id" {
+		t.Errorf("expected the pattern route, got %s", link.Route)
+	}
+	if link.Params["id"] != "42" || link.Params["tab"] != "issues" {
+		t.Errorf("expected path and query params, got %v", link.Params)
+	}
+	if string(link.Payload) != `{"filter":"open"}` {
+		t.Errorf("expected the payload, got %s", link.Payload)
+	}
+
+	if link, _ := app.router.ResolveDeepLink("/projects", ""); link.Route != "/projects" {
+		t.Errorf("expected an exact route to win, got %s", link.Route)
+	}
+	if _, err := app.router.ResolveDeepLink("/projects/42/edit", ""); err == nil {
+		t.Error("expected an unknown route to fail")
+	}
+	if _, err := app.router.ResolveDeepLink("/projects/42", "{broken"); err == nil {
+		t.Error("expected a payload that is not JSON to fail")
+	}
+}
+
+func TestApplicationDeepLink(t *testing.T) {
+	app, _ := newDeepLinkApp("open", "/projects/42", `{"filter":"open"}`)
+	var data map[string]any
+	app.Use(MiddlewareFunc(func(ctx *Context) bool {
+		data = ctx.Data
+		return true
+	}))
+
+	app.startDeepLink()
+	msgs := collectMsgs(app.Init())
+
+	if route := app.router.CurrentRoute(); route != "/projects/:id" {
+		t.Fatalf("expected to start on the deep link, got %s", route)
+	}
+	if params, _ := data["params"].(map[string]string); params["id"] != "42" {
+		t.Errorf("expected middleware to see the params, got %v", data)
+	}
+
+	var deepLink *DeepLinkMsg
+	for _, msg := range msgs {
+		if m, ok := msg.(DeepLinkMsg); ok {
+			deepLink = &m
+		}
+	}
+	if deepLink == nil {
+		t.Fatal("expected a DeepLinkMsg")
+	}
+	var payload struct{ Filter string }
+	if err := json.Unmarshal(deepLink.Payload, &payload); err != nil || payload.Filter != "open" {
+		t.Errorf("expected the payload in the DeepLinkMsg, got %s", deepLink.Payload)
+	}
+}
+
+func TestApplicationDeepLink_Fallback(t *testing.T) {
+	app, _ := newDeepLinkApp("/projects/42")
+	app.Use(AuthMiddleware([]string{"/projects/:id"}, func() bool { return false }))
+
+	app.startDeepLink()
+	for _, msg := range collectMsgs(app.Init()) {
+		app.Update(msg)
+	}
+
+	if route := app.router.CurrentRoute(); route != "/home" {
+		t.Errorf("expected a blocked deep link to fall back to the default route, got %s", route)
+	}
+	if len(app.Errors) != 1 {
+		t.Errorf("expected the error to be recorded, got %v", app.Errors)
+	}
+	if notifications := app.Notifications(); len(notifications) != 1 || notifications[0].Level != NotifyError {
+		t.Errorf("expected an error notification, got %+v", notifications)
+	}
+
+	t.Setenv("DEEPLINK_TEST_LINK", "/projects")
+	env, _ := newDeepLinkApp()
+	env.UseDeepLink(DeepLinkConfig{Env: "DEEPLINK_TEST_LINK"})
+	env.startDeepLink()
+	if route := env.router.CurrentRoute(); route != "/projects" {
+		t.Errorf("expected the link from the environment, got %s", route)
+	}
+}
+
+func TestDeepLinkFrom(t *testing.T) {
+	tests := []struct {
+		args          []string
+		link, payload string
+	}{
+		{[]string{"/projects/42", "--verbose"}, "/projects/42", ""},
+		{[]string{"/projects/42", "--data={\"filter\":\"open\"}"}, "/projects/42", `{"filter":"open"}`},
+		{[]string{"-v", "/projects/42", "extra", `{"filter":"open"}`}, "/projects/42", `{"filter":"open"}`},
+		{[]string{"open", "--data=[]"}, "", ""},
+		{[]string{"/var/log/app", "/projects?tab=issues"}, "/projects?tab=issues", ""},
+		{[]string{"/var/log/app"}, "", ""},
+		{[]string{"/projects", "--link=/missing"}, "/missing", ""},
+	}
+	app, _ := newDeepLinkApp()
+	for _, test := range tests {
+		link, payload := deepLinkFrom(DeepLinkConfig{Args: test.args}, app.router)
+		if link != test.link || payload != test.payload {
+			t.Errorf("%v: expected %q %q, got %q %q", test.args, test.link, test.payload, link, payload)
+		}
+	}
+}
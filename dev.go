@@ -0,0 +1,193 @@
+package mvct
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/fsnotify/fsnotify"
+)
+
+// DevEnv is set by mvct dev to a directory it shares with the app it runs.
+// Run keeps the session there, so a restart reopens the same route, and
+// shows the build status mvct dev writes to it
+const DevEnv = "MVCT_DEV"
+
+const (
+	// DevStatusFile holds the DevStatus in the DevEnv directory
+	DevStatusFile = "status.json"
+	// DevSessionFile holds the session in the DevEnv directory
+	DevSessionFile = "session.json"
+)
+
+// DevStatus is the state of the build of mvct dev
+type DevStatus struct {
+	// Building is set while the project is rebuilt
+	Building bool `json:"building,omitempty"`
+	// Errors is the output of a failed build, shown until a build succeeds
+	Errors string `json:"errors,omitempty"`
+	// Restart asks the app to save its session and exit so the new build
+	// takes over
+	Restart bool `json:"restart,omitempty"`
+}
+
+// WriteDevStatus replaces the status file in dir
+func WriteDevStatus(dir string, status DevStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	// rename so the app never reads half a status
+	path := filepath.Join(dir, DevStatusFile)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// readDevStatus reads the status file in dir, a missing file is an empty
+// status
+func readDevStatus(dir string) (DevStatus, error) {
+	var status DevStatus
+	data, err := os.ReadFile(filepath.Join(dir, DevStatusFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return status, nil
+	}
+	if err != nil {
+		return status, err
+	}
+	return status, json.Unmarshal(data, &status)
+}
+
+// devStatusMsg carries a new DevStatus into Update
+type devStatusMsg struct {
+	status DevStatus
+}
+
+// startDev enables dev mode when the app runs under mvct dev. The session
+// moves to the dev directory, and is enabled when the app doesn't use one,
+// so every restart restores the route, the history and the controller
+// state. The deep link only applies to the first run
+func (a *Application[M]) startDev() {
+	dir := os.Getenv(DevEnv)
+	if dir == "" {
+		return
+	}
+
+	session := SessionConfig{}
+	if a.session != nil {
+		session = *a.session
+	}
+	session.Path = filepath.Join(dir, DevSessionFile)
+	a.session = &session
+	if _, err := os.Stat(session.Path); err == nil {
+		a.deepLink = nil
+	}
+
+	slog.Info("Running under mvct dev", "dir", dir)
+	a.dev = &DevStatus{}
+	a.Subscribe(watchDevStatus(dir))
+}
+
+// watchDevStatus sends the status in dir when it starts and every time it
+// is written
+func watchDevStatus(dir string) Subscription {
+	return Subscription{
+		Name: "mvct dev",
+		Run: func(ctx context.Context, send func(msg tea.Msg)) error {
+			watcher, err := fsnotify.NewWatcher()
+			if err != nil {
+				return fmt.Errorf("failed to create file watcher: %w", err)
+			}
+			defer watcher.Close()
+			// the directory is watched since the file is replaced
+			if err := watcher.Add(dir); err != nil {
+				return fmt.Errorf("failed to watch %s: %w", dir, err)
+			}
+
+			read := func() {
+				status, err := readDevStatus(dir)
+				if err != nil {
+					slog.Warn("Failed to read the dev status", "error", err)
+					return
+				}
+				send(devStatusMsg{status: status})
+			}
+			read()
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case event, ok := <-watcher.Events:
+					if !ok {
+						return nil
+					}
+					if filepath.Base(event.Name) == DevStatusFile && event.Op.Has(fsnotify.Create|fsnotify.Write) {
+						read()
+					}
+				case err, ok := <-watcher.Errors:
+					if !ok {
+						return nil
+					}
+					return fmt.Errorf("file watcher failed: %w", err)
+				}
+			}
+		},
+	}
+}
+
+func (a *Application[M]) handleDevStatus(msg devStatusMsg) (tea.Model, tea.Cmd) {
+	if a.dev == nil {
+		return a, nil
+	}
+	*a.dev = msg.status
+	if msg.status.Restart {
+		slog.Info("Restarting for a new build")
+		return a, tea.Quit
+	}
+	return a, nil
+}
+
+// devOverlay draws the build errors over view, or a small notice while the
+// project is rebuilt
+func (a *Application[M]) devOverlay(view string) string {
+	if a.dev == nil {
+		return view
+	}
+	theme := CurrentTheme()
+
+	if a.dev.Errors != "" {
+		width := a.width
+		if width <= 0 {
+			width = max(lipgloss.Width(view), 80)
+		}
+		lines := strings.Split(strings.TrimRight(a.dev.Errors, "\n"), "\n")
+		if a.height > 0 {
+			// title and border
+			lines = lines[:min(len(lines), max(a.height-4, 1))]
+		}
+		for i, line := range lines {
+			lines[i] = ansi.Truncate(line, max(width-4, 8), "…")
+		}
+		title := theme.Style(theme.Error).Bold(true).Render("build failed, fix the errors to reload")
+		box := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Error.Color()).
+			Padding(0, 1).
+			Render(title + "\n" + strings.Join(lines, "\n"))
+		return placeOverlay(0, 0, box, view)
+	}
+
+	if a.dev.Building {
+		return placeInCorner(BottomRight, theme.Style(theme.Muted).Render(" rebuilding… "), view, a.width, a.height)
+	}
+	return view
+}
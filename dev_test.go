@@ -0,0 +1,116 @@
+package mvct
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDev_SessionInDevDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(DevEnv, dir)
+
+	app, draft := newSessionApp(t, filepath.Join(t.TempDir(), "session.json"), 3)
+	app.UseDeepLink(DeepLinkConfig{Args: []string{"/list"}})
+	app.startDev()
+	if app.session.Path != filepath.Join(dir, DevSessionFile) || app.session.Version != 3 {
+		t.Fatalf("expected the session in the dev dir with its version, got %+v", app.session)
+	}
+	if app.deepLink == nil {
+		t.Error("expected the deep link to apply to the first run")
+	}
+	app.Init()
+	app.Update(NavigateMsg{Route: "/compose"})
+	draft.Draft = "Dear Ada"
+	if err := app.SaveSession(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the app after a rebuild
+	restarted, restartedDraft := newSessionApp(t, filepath.Join(t.TempDir(), "session.json"), 3)
+	restarted.UseDeepLink(DeepLinkConfig{Args: []string{"/list"}})
+	restarted.startDev()
+	if restarted.deepLink != nil {
+		t.Error("expected the deep link to be skipped after a restart")
+	}
+	restarted.restoreSession()
+	restarted.Init()
+	if route := restarted.router.CurrentRoute(); route != "/compose" {
+		t.Errorf("expected the route before the restart, got %s", route)
+	}
+	if restartedDraft.Draft != "Dear Ada" {
+		t.Errorf("expected the controller state, got %+v", restartedDraft)
+	}
+}
+
+func TestDev_SessionWithoutUseSession(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(DevEnv, dir)
+
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.startDev()
+	if app.session == nil || app.session.Path != filepath.Join(dir, DevSessionFile) {
+		t.Errorf("expected dev mode to enable sessions, got %+v", app.session)
+	}
+	if len(app.appSubscriptions) != 1 {
+		t.Errorf("expected the status subscription, got %d", len(app.appSubscriptions))
+	}
+}
+
+func TestDev_NotUnderDev(t *testing.T) {
+	t.Setenv(DevEnv, "")
+
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.startDev()
+	if app.session != nil || app.dev != nil {
+		t.Error("expected dev mode to stay off")
+	}
+}
+
+func TestDev_Status(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.dev = &DevStatus{}
+	app.Init()
+
+	app.Update(devStatusMsg{status: DevStatus{Building: true}})
+	if view := app.View(); !strings.Contains(view, "rebuilding") {
+		t.Errorf("expected the rebuilding notice, got %q", view)
+	}
+
+	app.Update(devStatusMsg{status: DevStatus{Errors: "controllers/home.go:25:18: undefined: x\n"}})
+	view := app.View()
+	if !strings.Contains(view, "build failed") || !strings.Contains(view, "undefined: x") {
+		t.Errorf("expected the build errors, got %q", view)
+	}
+
+	app.Update(devStatusMsg{status: DevStatus{}})
+	if view := app.View(); strings.Contains(view, "build failed") {
+		t.Errorf("expected the errors to clear, got %q", view)
+	}
+
+	_, cmd := app.Update(devStatusMsg{status: DevStatus{Restart: true}})
+	if cmd == nil {
+		t.Fatal("expected a restart to quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("expected a restart to quit")
+	}
+}
+
+func TestDev_StatusFile(t *testing.T) {
+	dir := t.TempDir()
+	if status, err := readDevStatus(dir); err != nil || status != (DevStatus{}) {
+		t.Errorf("expected an empty status without a file, got %+v, %v", status, err)
+	}
+	if err := WriteDevStatus(dir, DevStatus{Errors: "boom"}); err != nil {
+		t.Fatal(err)
+	}
+	if status, err := readDevStatus(dir); err != nil || status.Errors != "boom" {
+		t.Errorf("expected the written status, got %+v, %v", status, err)
+	}
+}
@@ -0,0 +1,472 @@
+package mvct
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DevtoolsConfig configures the devtools overlay
+type DevtoolsConfig struct {
+	// Keys toggle the overlay, f12 when empty. They are checked before
+	// the controller sees a key
+	Keys []string
+	// Messages is the number of messages kept in the log, 200 when zero
+	Messages int
+	// Navigations is the number of navigations kept with their middleware
+	// decisions, 20 when zero
+	Navigations int
+}
+
+// devtoolsPanes are the panes of the overlay in tab order
+var devtoolsPanes = []string{"Route", "Handlers", "Messages", "Model", "Middleware"}
+
+const (
+	paneRoute = iota
+	paneHandlers
+	paneMessages
+	paneModel
+	paneMiddleware
+)
+
+// loggedMsg is a message in the devtools log, it is formatted when the
+// Messages pane is drawn so a closed overlay costs no formatting
+type loggedMsg struct {
+	time  time.Time
+	route string
+	msg   tea.Msg
+	// took is the time Update took to handle the message
+	took time.Duration
+	// text is msg formatted, built the first time it is shown
+	text string
+}
+
+func (m *loggedMsg) typ() string {
+	return fmt.Sprintf("%T", m.msg)
+}
+
+func (m *loggedMsg) describe() string {
+	if m.text != "" || m.msg == nil {
+		return m.text
+	}
+	if key, ok := m.msg.(tea.KeyMsg); ok {
+		m.text = key.String()
+	} else {
+		m.text = strings.Join(strings.Fields(fmt.Sprintf("%+v", m.msg)), " ")
+	}
+	m.text = ansi.Truncate(m.text, 120, "…")
+	return m.text
+}
+
+// devtools is the state of the overlay, it records messages and
+// navigations while it is closed
+type devtools struct {
+	config DevtoolsConfig
+	open   bool
+	pane   int
+	// scroll is the first line shown of each pane
+	scroll      map[int]int
+	messages    []loggedMsg
+	navigations []NavigationTrace
+}
+
+// UseDevtools adds an overlay inspecting the running app: the route and
+// history, the handlers of the current controller, a log of the messages
+// with the time Update took for them, the global model and what the
+// middleware decided for the last navigations. While it is open it takes
+// the keyboard, tab switches panes and esc closes it
+func (a *Application[M]) UseDevtools(config DevtoolsConfig) {
+	if len(config.Keys) == 0 {
+		config.Keys = []string{"f12"}
+	}
+	if config.Messages <= 0 {
+		config.Messages = 200
+	}
+	if config.Navigations <= 0 {
+		config.Navigations = 20
+	}
+	slog.Debug("Configuring devtools", "keys", config.Keys)
+
+	a.devtools = &devtools{config: config, scroll: map[int]int{}}
+	a.router.traced = a.devtools.traceNavigation
+}
+
+func (d *devtools) traceNavigation(trace NavigationTrace) {
+	d.navigations = append(d.navigations, trace)
+	if over := len(d.navigations) - d.config.Navigations; over > 0 {
+		d.navigations = slices.Delete(d.navigations, 0, over)
+	}
+}
+
+// logMsg records msg once Update handled it, started is when Update was
+// called
+func (d *devtools) logMsg(msg tea.Msg, route string, started time.Time) {
+	d.messages = append(d.messages, loggedMsg{
+		time:  started,
+		route: route,
+		msg:   msg,
+		took:  time.Since(started),
+	})
+	if over := len(d.messages) - d.config.Messages; over > 0 {
+		d.messages = slices.Delete(d.messages, 0, over)
+	}
+}
+
+// handleDevtoolsKey toggles the overlay and handles every key while it is
+// open, it reports whether the key was used
+func (a *Application[M]) handleDevtoolsKey(msg tea.KeyMsg) bool {
+	d := a.devtools
+	key := msg.String()
+	if slices.Contains(d.config.Keys, key) {
+		d.open = !d.open
+		return true
+	}
+	if !d.open {
+		return false
+	}
+
+	page := max(a.devtoolsSize().contentHeight, 1)
+	switch key {
+	case "esc":
+		d.open = false
+	case "tab", "right", "l":
+		d.pane = (d.pane + 1) % len(devtoolsPanes)
+	case "shift+tab", "left", "h":
+		d.pane = (d.pane + len(devtoolsPanes) - 1) % len(devtoolsPanes)
+	case "1", "2", "3", "4", "5":
+		d.pane = int(key[0] - '1')
+	case "up", "k":
+		d.scroll[d.pane]--
+	case "down", "j":
+		d.scroll[d.pane]++
+	case "pgup":
+		d.scroll[d.pane] -= page
+	case "pgdown", " ":
+		d.scroll[d.pane] += page
+	case "home", "g":
+		d.scroll[d.pane] = 0
+	case "end", "G":
+		d.scroll[d.pane] = len(a.devtoolsLines(d.pane))
+	}
+	d.scroll[d.pane] = max(min(d.scroll[d.pane], len(a.devtoolsLines(d.pane))-page), 0)
+	return true
+}
+
+// devtoolsSize is the size of the overlay, the whole screen
+type devtoolsSize struct {
+	width, height, contentHeight int
+}
+
+func (a *Application[M]) devtoolsSize() devtoolsSize {
+	width, height := a.width, a.height
+	if width <= 0 {
+		width = 80
+	}
+	if height <= 0 {
+		height = 24
+	}
+	// border, tabs, the line below them and the help line
+	return devtoolsSize{width: width, height: height, contentHeight: height - 5}
+}
+
+// devtoolsOverlay draws the open overlay over view
+func (a *Application[M]) devtoolsOverlay(view string) string {
+	d := a.devtools
+	if d == nil || !d.open {
+		return view
+	}
+	theme := CurrentTheme()
+	size := a.devtoolsSize()
+	innerWidth := max(size.width-4, 10)
+
+	tabs := make([]string, len(devtoolsPanes))
+	for i, pane := range devtoolsPanes {
+		label := fmt.Sprintf(" %d %s ", i+1, pane)
+		if i == d.pane {
+			tabs[i] = theme.Style(theme.Selected).Bold(true).Reverse(true).Render(label)
+		} else {
+			tabs[i] = theme.Style(theme.Muted).Render(label)
+		}
+	}
+
+	lines := a.devtoolsLines(d.pane)
+	start := min(d.scroll[d.pane], max(len(lines)-1, 0))
+	content := make([]string, 0, size.contentHeight)
+	for _, line := range lines[start:] {
+		if len(content) == size.contentHeight {
+			break
+		}
+		content = append(content, ansi.Truncate(line, innerWidth, "…"))
+	}
+	for len(content) < size.contentHeight {
+		content = append(content, "")
+	}
+
+	help := theme.Style(theme.Muted).Render(fmt.Sprintf("tab: next pane • ↑/↓ pgup/pgdown: scroll • esc: close   %d/%d",
+		min(start+size.contentHeight, len(lines)), len(lines)))
+	body := strings.Join(tabs, " ") + "\n\n" + strings.Join(content, "\n") + "\n" + help
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Primary.Color()).
+		Padding(0, 1).
+		Width(size.width - 2).
+		Render(body)
+	return placeOverlay(0, 0, box, view)
+}
+
+// devtoolsLines returns the content of a pane
+func (a *Application[M]) devtoolsLines(pane int) []string {
+	d := a.devtools
+	theme := CurrentTheme()
+	heading := theme.Style(theme.Primary).Bold(true).Render
+	muted := theme.Style(theme.Muted).Render
+	var lines []string
+
+	switch pane {
+	case paneRoute:
+		lines = append(lines,
+			"current   "+a.router.CurrentRoute(),
+			"previous  "+a.router.PreviousRoute(),
+			"default   "+a.router.defaultRoute,
+			"", heading("History, newest first"))
+		history := a.router.History()
+		if len(history) == 0 {
+			lines = append(lines, muted("no navigations yet"))
+		}
+		for i := len(history) - 1; i >= 0; i-- {
+			lines = append(lines, fmt.Sprintf("%3d  %s", len(history)-i, history[i]))
+		}
+		lines = append(lines, "", heading("Routes"))
+		routes := make([]string, 0, len(a.router.routes))
+		for route := range a.router.routes {
+			routes = append(routes, route)
+		}
+		slices.Sort(routes)
+		for _, route := range routes {
+			marker := "  "
+			if route == a.router.CurrentRoute() {
+				marker = "▸ "
+			}
+			lines = append(lines, marker+route+"  "+muted(reflect.TypeOf(a.router.routes[route]).String()))
+		}
+
+	case paneHandlers:
+		keys, msgs := a.handlerNames()
+		none := "  " + muted("none")
+		lines = append(lines, heading(fmt.Sprintf("Key handlers of %s", a.router.CurrentRoute())))
+		if len(keys) == 0 {
+			lines = append(lines, none)
+		}
+		for _, key := range keys {
+			lines = append(lines, fmt.Sprintf("  %-12s %s", strconv.Quote(key[0]), key[1]))
+		}
+		lines = append(lines, "", heading("Message handlers"))
+		if len(msgs) == 0 {
+			lines = append(lines, none)
+		}
+		for _, msg := range msgs {
+			lines = append(lines, fmt.Sprintf("  %-30s %s", msg[0], msg[1]))
+		}
+		lines = append(lines, "", heading("Global handlers, in the order they run"))
+		if len(a.globalHandlers) == 0 {
+			lines = append(lines, none)
+		}
+		for _, handler := range a.globalHandlers {
+			lines = append(lines, "  "+globalHandlerName(handler))
+		}
+
+	case paneMessages:
+		if len(d.messages) == 0 {
+			lines = append(lines, muted("no messages yet"))
+		}
+		for i := len(d.messages) - 1; i >= 0; i-- {
+			msg := &d.messages[i]
+			lines = append(lines, fmt.Sprintf("%s %8s  %-12s %s %s",
+				msg.time.Format("15:04:05.000"), msg.took.Round(time.Microsecond), msg.route, msg.typ(), muted(msg.describe())))
+		}
+
+	case paneModel:
+		lines = modelTree(a.model)
+
+	case paneMiddleware:
+		lines = append(lines, heading("Middleware, in the order it runs"))
+		for _, m := range a.router.middleware {
+			lines = append(lines, "  "+middlewareName(m))
+		}
+		lines = append(lines, "", heading("Navigations, newest first"))
+		if len(d.navigations) == 0 {
+			lines = append(lines, muted("no navigations yet"))
+		}
+		for i := len(d.navigations) - 1; i >= 0; i-- {
+			nav := d.navigations[i]
+			from := nav.From
+			if from == "" {
+				from = "start"
+			}
+			result := theme.Style(theme.Success).Render("allowed")
+			if nav.Err != nil {
+				result = theme.Style(theme.Error).Render(nav.Err.Error())
+			}
+			lines = append(lines, fmt.Sprintf("%s %s → %s  %s", nav.Time.Format("15:04:05.000"), from, nav.To, result))
+			for _, decision := range nav.Decisions {
+				mark := theme.Style(theme.Success).Render("✓")
+				if !decision.Allowed {
+					mark = theme.Style(theme.Error).Render("✗")
+				}
+				lines = append(lines, "    "+mark+" "+decision.Middleware)
+			}
+		}
+	}
+	return lines
+}
+
+const (
+	// modelTreeDepth is how deep modelTree expands values
+	modelTreeDepth = 6
+	// modelTreeItems is how many elements of a slice or map modelTree shows
+	modelTreeItems = 20
+)
+
+// modelTree renders the exported fields, elements and map entries of
+// model as a tree, one line per value
+func modelTree(model any) []string {
+	v := reflect.ValueOf(model)
+	t := &treeWriter{lines: []string{treeSummary(v)}, seen: map[uintptr]bool{}}
+	t.children(v, "", 0)
+	return t.lines
+}
+
+type treeWriter struct {
+	lines []string
+	// seen are the pointers already expanded, for cycles
+	seen map[uintptr]bool
+}
+
+// treeNode is a child value with its field name, index or key
+type treeNode struct {
+	label string
+	value reflect.Value
+}
+
+func (t *treeWriter) children(v reflect.Value, prefix string, depth int) {
+	nodes, more := treeChildren(v)
+	if len(nodes) > 0 && depth >= modelTreeDepth {
+		t.lines = append(t.lines, prefix+"└─ …")
+		return
+	}
+	for i, node := range nodes {
+		branch, next := "├─ ", "│  "
+		if i == len(nodes)-1 && more == 0 {
+			branch, next = "└─ ", "   "
+		}
+		t.lines = append(t.lines, prefix+branch+node.label+": "+treeSummary(node.value))
+
+		if node.value.Kind() == reflect.Pointer && !node.value.IsNil() {
+			if t.seen[node.value.Pointer()] {
+				continue
+			}
+			t.seen[node.value.Pointer()] = true
+		}
+		t.children(node.value, prefix+next, depth+1)
+	}
+	if more > 0 {
+		t.lines = append(t.lines, fmt.Sprintf("%s└─ … %d more", prefix, more))
+	}
+}
+
+// treeChildren returns the children of v to show and how many were left
+// out
+func treeChildren(v reflect.Value) ([]treeNode, int) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, 0
+		}
+		v = v.Elem()
+	}
+	if stringer(v) {
+		return nil, 0
+	}
+
+	var nodes []treeNode
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() {
+				nodes = append(nodes, treeNode{field.Name, v.Field(i)})
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < min(v.Len(), modelTreeItems); i++ {
+			nodes = append(nodes, treeNode{fmt.Sprintf("[%d]", i), v.Index(i)})
+		}
+		return nodes, max(v.Len()-modelTreeItems, 0)
+	case reflect.Map:
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(treeSummary(a), treeSummary(b))
+		})
+		for _, key := range keys[:min(len(keys), modelTreeItems)] {
+			nodes = append(nodes, treeNode{treeSummary(key), v.MapIndex(key)})
+		}
+		return nodes, max(len(keys)-modelTreeItems, 0)
+	}
+	return nodes, 0
+}
+
+// stringer reports whether v is a struct shown by its String method, like
+// time.Time
+func stringer(v reflect.Value) bool {
+	if v.Kind() != reflect.Struct || !v.CanInterface() {
+		return false
+	}
+	_, ok := v.Interface().(fmt.Stringer)
+	return ok
+}
+
+// treeSummary returns the value of a basic value or the type and length of
+// a composite one
+func treeSummary(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return "nil"
+		}
+		if v.Kind() == reflect.Interface {
+			return treeSummary(v.Elem())
+		}
+		return "&" + treeSummary(v.Elem())
+	case reflect.String:
+		return strconv.Quote(ansi.Truncate(v.String(), 60, "…"))
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return v.Type().String() + " nil"
+		}
+		return fmt.Sprintf("%s len %d", v.Type(), v.Len())
+	case reflect.Array:
+		return fmt.Sprintf("%s len %d", v.Type(), v.Len())
+	case reflect.Struct:
+		if stringer(v) {
+			return v.Interface().(fmt.Stringer).String()
+		}
+	}
+	return v.Type().String()
+}
@@ -0,0 +1,152 @@
+package mvct
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type devtoolsModel struct {
+	User    *devtoolsUser
+	Tags    []string
+	Counts  map[string]int
+	Started time.Time
+	secret  string
+}
+
+type devtoolsUser struct {
+	Name    string
+	Friends []*devtoolsUser
+}
+
+func newDevtoolsApp() (*Application[string], *ReflectionController) {
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	home := &ReflectionController{}
+	app.RegisterController("/home", home)
+	app.RegisterController("/settings", &MockController{name: "settings"})
+	app.UseDevtools(DevtoolsConfig{})
+	app.Init()
+	return app, home
+}
+
+func TestDevtools_Toggle(t *testing.T) {
+	app, home := newDevtoolsApp()
+
+	app.Update(tea.KeyMsg{Type: tea.KeyF12})
+	if !app.devtools.open {
+		t.Fatal("expected f12 to open the devtools")
+	}
+	view := app.View()
+	if !strings.Contains(view, "Route") || !strings.Contains(view, "current   /home") {
+		t.Errorf("expected the route pane, got:\n%s", view)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if home.keyHandled {
+		t.Error("expected the open devtools to take the keyboard")
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if app.devtools.pane != paneHandlers {
+		t.Errorf("expected tab to switch to the handlers pane, got %d", app.devtools.pane)
+	}
+	if view := app.View(); !strings.Contains(view, `"x"`) || !strings.Contains(view, "OnStringMsg") {
+		t.Errorf("expected the key and message handlers, got:\n%s", view)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.devtools.open {
+		t.Error("expected esc to close the devtools")
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if !home.keyHandled {
+		t.Error("expected keys to reach the controller once closed")
+	}
+}
+
+func TestDevtools_MessageLog(t *testing.T) {
+	app, _ := newDevtoolsApp()
+	app.devtools.config.Messages = 2
+
+	app.Update(StringMsg{Value: "first"})
+	app.Update(StringMsg{Value: "second"})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+
+	if len(app.devtools.messages) != 2 {
+		t.Fatalf("expected the log to keep 2 messages, got %d", len(app.devtools.messages))
+	}
+	if text := app.devtools.messages[0].text; text != "" {
+		t.Errorf("expected messages to be formatted only when shown, got %q", text)
+	}
+	last := app.devtools.messages[1]
+	if last.typ() != "tea.KeyMsg" || last.describe() != "x" || last.route != "/home" {
+		t.Errorf("expected the key on /home, got %+v", last)
+	}
+
+	lines := app.devtoolsLines(paneMessages)
+	if len(lines) != 2 || !strings.Contains(lines[0], "tea.KeyMsg") || !strings.Contains(lines[1], "{Value:second}") {
+		t.Errorf("expected the newest message first, got %q", lines)
+	}
+}
+
+func TestDevtools_MiddlewareDecisions(t *testing.T) {
+	app, _ := newDevtoolsApp()
+	app.Use(LoggingMiddleware())
+	app.Use(AuthMiddleware([]string{"/settings"}, func() bool { return false }))
+
+	app.Update(NavigateMsg{Route: "/settings"})
+	app.Update(NavigateMsg{Route: "/missing"})
+
+	navs := app.devtools.navigations
+	if len(navs) != 2 {
+		t.Fatalf("expected 2 navigations, got %d", len(navs))
+	}
+	blocked := navs[0]
+	if blocked.Err == nil || len(blocked.Decisions) != 2 || !blocked.Decisions[0].Allowed || blocked.Decisions[1].Allowed {
+		t.Errorf("expected the auth middleware to block, got %+v", blocked)
+	}
+	if !strings.Contains(blocked.Decisions[1].Middleware, "AuthMiddleware") {
+		t.Errorf("expected the middleware to be named by its function, got %q", blocked.Decisions[1].Middleware)
+	}
+	if navs[1].Err == nil || len(navs[1].Decisions) != 0 {
+		t.Errorf("expected an unknown route to fail before the middleware, got %+v", navs[1])
+	}
+
+	lines := strings.Join(app.devtoolsLines(paneMiddleware), "\n")
+	if !strings.Contains(lines, "/home → /missing") || !strings.Contains(lines, "✗ mvct.AuthMiddleware") {
+		t.Errorf("expected the navigations with their decisions, got:\n%s", lines)
+	}
+}
+
+func TestModelTree(t *testing.T) {
+	ada := &devtoolsUser{Name: "Ada"}
+	ada.Friends = []*devtoolsUser{ada}
+	model := devtoolsModel{
+		User:    ada,
+		Counts:  map[string]int{"b": 2, "a": 1},
+		Started: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		secret:  "hidden",
+	}
+
+	tree := strings.Join(modelTree(model), "\n")
+	for _, want := range []string{
+		"mvct.devtoolsModel",
+		`├─ User: &mvct.devtoolsUser`,
+		`│  ├─ Name: "Ada"`,
+		`│  └─ Friends: []*mvct.devtoolsUser len 1`,
+		`├─ Tags: []string nil`,
+		"├─ Counts: map[string]int len 2\n│  ├─ \"a\": 1\n│  └─ \"b\": 2",
+		"└─ Started: 2024-01-02 03:04:05 +0000 UTC",
+	} {
+		if !strings.Contains(tree, want) {
+			t.Errorf("expected the tree to contain %q, got:\n%s", want, tree)
+		}
+	}
+	if strings.Contains(tree, "hidden") {
+		t.Errorf("expected unexported fields to be left out, got:\n%s", tree)
+	}
+	if strings.Count(tree, `Name: "Ada"`) != 1 {
+		t.Errorf("expected a cycle to be expanded once, got:\n%s", tree)
+	}
+}
@@ -0,0 +1,221 @@
+package mvct
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxQueuedEvents bounds the events queued for an inactive subscriber, the
+// oldest event is dropped when it is exceeded
+const maxQueuedEvents = 256
+
+// Delivery decides what happens to an event published while the owner of a
+// subscription is not the active controller. Subscriptions without an owner
+// and subscriptions of the active controller always receive events
+// immediately
+type Delivery int
+
+const (
+	// DeliverQueued keeps every event and delivers them in order right after
+	// the owner is activated
+	DeliverQueued Delivery = iota
+	// DeliverLatest keeps only the most recent event and replays it on
+	// activation
+	DeliverLatest
+	// DeliverImmediately calls the handler even while the owner is inactive
+	DeliverImmediately
+)
+
+func (d Delivery) String() string {
+	switch d {
+	case DeliverQueued:
+		return "queued"
+	case DeliverLatest:
+		return "latest"
+	case DeliverImmediately:
+		return "immediately"
+	}
+	return fmt.Sprintf("delivery(%d)", int(d))
+}
+
+// PublishMsg publishes an event on the event bus of the application
+type PublishMsg struct {
+	Event any
+}
+
+// Publish sends an event to every subscriber of its type
+func Publish(event any) Cmd {
+	return func() Msg {
+		return Msg{
+			Inner: PublishMsg{Event: event},
+		}
+	}
+}
+
+// eventSubscription is the handler of one event type for one owner
+type eventSubscription struct {
+	owner    Controller
+	handler  MessageHandler
+	delivery Delivery
+	pending  []any
+}
+
+// EventBus delivers published events to subscribers, including controllers
+// that are not active and overlays that are not controllers at all
+type EventBus struct {
+	mu            sync.Mutex
+	subscriptions []*eventSubscription
+}
+
+// Subscribe registers handlers created with HandleMsg for owner. A nil
+// owner receives events immediately, which suits overlays and app level
+// code. Subscribing the same owner to an event type again replaces the
+// handler and keeps its queued events, so subscribing in Init is safe. The
+// returned function removes the subscriptions. The owner is matched with
+// ==, so controllers that are not comparable, like a struct value holding
+// a slice, are rejected with an error: subscribe a pointer to them instead
+func (b *EventBus) Subscribe(owner Controller, delivery Delivery, handlers ...MessageHandler) (unsubscribe func(), err error) {
+	if owner != nil && !isComparable(owner) {
+		return nil, fmt.Errorf("event owner %T is not comparable, subscribe a pointer to it", owner)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var added []*eventSubscription
+	for _, handler := range handlers {
+		if i := b.find(owner, handler.Type); i >= 0 {
+			b.subscriptions[i].handler = handler
+			b.subscriptions[i].delivery = delivery
+			added = append(added, b.subscriptions[i])
+			continue
+		}
+
+		sub := &eventSubscription{owner: owner, handler: handler, delivery: delivery}
+		b.subscriptions = append(b.subscriptions, sub)
+		added = append(added, sub)
+		slog.Debug("Subscribed to event", "event_type", handler.Type.String(), "owner", reflect.TypeOf(owner), "delivery", delivery)
+	}
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.subscriptions = slices.DeleteFunc(b.subscriptions, func(sub *eventSubscription) bool {
+			return slices.Contains(added, sub)
+		})
+	}, nil
+}
+
+func (b *EventBus) find(owner Controller, eventType reflect.Type) int {
+	return slices.IndexFunc(b.subscriptions, func(sub *eventSubscription) bool {
+		return sub.handler.Type == eventType && sameController(sub.owner, owner)
+	})
+}
+
+// publish calls the handlers that receive the event now and queues it for
+// inactive owners
+func (b *EventBus) publish(event any, active Controller) tea.Cmd {
+	eventType := reflect.TypeOf(event)
+
+	b.mu.Lock()
+	var handlers []MessageHandler
+	for _, sub := range b.subscriptions {
+		if sub.handler.Type != eventType {
+			continue
+		}
+		switch {
+		case sub.owner == nil, sub.delivery == DeliverImmediately, sameController(sub.owner, active):
+			handlers = append(handlers, sub.handler)
+		case sub.delivery == DeliverLatest:
+			sub.pending = []any{event}
+		default:
+			if len(sub.pending) >= maxQueuedEvents {
+				slog.Warn("Dropping queued event", "event_type", eventType.String(), "owner", reflect.TypeOf(sub.owner))
+				sub.pending = sub.pending[1:]
+			}
+			sub.pending = append(sub.pending, event)
+		}
+	}
+	b.mu.Unlock()
+
+	slog.Debug("Published event", "event_type", eventType, "receivers", len(handlers))
+	return callEventHandlers(handlers, event)
+}
+
+// activate delivers the events queued for a controller that became active
+func (b *EventBus) activate(ctlr Controller) tea.Cmd {
+	type delivery struct {
+		handler MessageHandler
+		events  []any
+	}
+
+	b.mu.Lock()
+	var deliveries []delivery
+	for _, sub := range b.subscriptions {
+		if len(sub.pending) == 0 || !sameController(sub.owner, ctlr) {
+			continue
+		}
+		deliveries = append(deliveries, delivery{sub.handler, sub.pending})
+		sub.pending = nil
+	}
+	b.mu.Unlock()
+
+	var cmds []tea.Cmd
+	for _, d := range deliveries {
+		slog.Debug("Delivering queued events", "event_type", d.handler.Type.String(), "count", len(d.events))
+		for _, event := range d.events {
+			cmds = append(cmds, unwrapCmd(d.handler.Handle(event)))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func callEventHandlers(handlers []MessageHandler, event any) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, handler := range handlers {
+		cmds = append(cmds, unwrapCmd(handler.Handle(event)))
+	}
+	return tea.Batch(cmds...)
+}
+
+// sameController compares controllers without panicking on a controller
+// that is not comparable, owners are checked by Subscribe. A Loader is the
+// controller it wraps
+func sameController(a, b Controller) bool {
+	a, b = unwrapController(a), unwrapController(b)
+	if a == nil || b == nil || reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	return isComparable(a) && a == b
+}
+
+// isComparable reports whether == works on ctlr. A comparable type can still
+// hold a slice or map in an interface field, which only panics when
+// compared
+func isComparable(ctlr Controller) (ok bool) {
+	if !reflect.TypeOf(ctlr).Comparable() {
+		return false
+	}
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	return ctlr == ctlr
+}
+
+// Events returns the event bus of the application
+func (a *Application[M]) Events() *EventBus {
+	return a.events
+}
+
+func (a *Application[M]) handlePublish(msg PublishMsg) (tea.Model, tea.Cmd) {
+	if msg.Event == nil {
+		return a, nil
+	}
+	return a, a.events.publish(msg.Event, a.router.Current())
+}
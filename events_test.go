@@ -0,0 +1,137 @@
+package mvct
+
+import (
+	"testing"
+)
+
+type messageSent struct {
+	Text string
+}
+
+// InboxController records the events it receives
+type InboxController struct {
+	MockController
+	received []string
+}
+
+func (c *InboxController) onMessageSent(event messageSent) Cmd {
+	c.received = append(c.received, event.Text)
+	return nil
+}
+
+func newEventApp() (*Application[string], *InboxController) {
+	app := NewApplication(Config{DefaultRoute: "/compose"}, "model")
+	inbox := &InboxController{}
+	app.RegisterController("/compose", &MockController{name: "compose"})
+	app.RegisterController("/inbox", inbox)
+	app.Init()
+	return app, inbox
+}
+
+func TestEventBus_QueuedUntilActivation(t *testing.T) {
+	app, inbox := newEventApp()
+	app.Events().Subscribe(inbox, DeliverQueued, HandleMsg(inbox.onMessageSent))
+
+	app.Update(PublishMsg{Event: messageSent{Text: "one"}})
+	app.Update(PublishMsg{Event: messageSent{Text: "two"}})
+	if len(inbox.received) != 0 {
+		t.Fatalf("inactive subscriber should not receive events yet, got %v", inbox.received)
+	}
+
+	app.Update(NavigateMsg{Route: "/inbox"})
+	if len(inbox.received) != 2 || inbox.received[0] != "one" || inbox.received[1] != "two" {
+		t.Errorf("expected queued events in order on activation, got %v", inbox.received)
+	}
+
+	app.Update(PublishMsg{Event: messageSent{Text: "three"}})
+	if len(inbox.received) != 3 {
+		t.Errorf("expected immediate delivery to the active controller, got %v", inbox.received)
+	}
+
+	app.Update(NavigateMsg{Route: "/compose"})
+	app.Update(NavigateMsg{Route: "/inbox"})
+	if len(inbox.received) != 3 {
+		t.Errorf("events should be delivered once, got %v", inbox.received)
+	}
+}
+
+func TestEventBus_Delivery(t *testing.T) {
+	app, inbox := newEventApp()
+	app.Events().Subscribe(inbox, DeliverLatest, HandleMsg(inbox.onMessageSent))
+
+	var overlay []string
+	unsubscribe, _ := app.Events().Subscribe(nil, DeliverQueued, HandleMsg(func(event messageSent) Cmd {
+		overlay = append(overlay, event.Text)
+		return Notify(NotifyInfo, event.Text)
+	}))
+
+	_, cmd := app.Update(PublishMsg{Event: messageSent{Text: "one"}})
+	app.Update(PublishMsg{Event: messageSent{Text: "two"}})
+	if len(overlay) != 2 {
+		t.Errorf("expected subscribers without owner to receive every event, got %v", overlay)
+	}
+	if msgs := collectMsgs(cmd); len(msgs) != 1 {
+		t.Errorf("expected the handler command to be returned, got %v", msgs)
+	}
+
+	app.Update(NavigateMsg{Route: "/inbox"})
+	if len(inbox.received) != 1 || inbox.received[0] != "two" {
+		t.Errorf("expected only the latest event to be replayed, got %v", inbox.received)
+	}
+
+	unsubscribe()
+	app.Update(PublishMsg{Event: messageSent{Text: "three"}})
+	if len(overlay) != 2 {
+		t.Errorf("unsubscribed handler should not be called, got %v", overlay)
+	}
+}
+
+func TestEventBus_ResubscribeReplaces(t *testing.T) {
+	app, inbox := newEventApp()
+	app.Events().Subscribe(inbox, DeliverImmediately, HandleMsg(inbox.onMessageSent))
+	app.Events().Subscribe(inbox, DeliverImmediately, HandleMsg(inbox.onMessageSent))
+
+	app.Update(PublishMsg{Event: messageSent{Text: "one"}})
+	if len(inbox.received) != 1 {
+		t.Errorf("expected a single delivery to an inactive immediate subscriber, got %v", inbox.received)
+	}
+}
+
+// tagsController is a value controller that is not comparable
+type tagsController struct {
+	tags []string
+}
+
+func (c tagsController) Init(handlers KeyHandlers) Cmd { return nil }
+func (c tagsController) View() string                  { return "tags" }
+
+// boxController is comparable by type but holds a slice in an interface
+type boxController struct {
+	value any
+}
+
+func (c boxController) Init(handlers KeyHandlers) Cmd { return nil }
+func (c boxController) View() string                  { return "box" }
+
+func TestEventBus_NotComparableOwner(t *testing.T) {
+	app, inbox := newEventApp()
+	handler := HandleMsg(func(event messageSent) Cmd { return nil })
+	if _, err := app.Events().Subscribe(tagsController{}, DeliverQueued, handler); err == nil {
+		t.Error("expected a controller that is not comparable to be rejected")
+	}
+	if _, err := app.Events().Subscribe(boxController{value: []string{"a"}}, DeliverQueued, handler); err == nil {
+		t.Error("expected a controller holding a slice to be rejected")
+	}
+
+	// an active controller holding a slice is never the owner
+	if _, err := app.Events().Subscribe(inbox, DeliverQueued, HandleMsg(inbox.onMessageSent)); err != nil {
+		t.Fatal(err)
+	}
+	app.RegisterController("/box", boxController{value: []string{"a"}})
+	app.Update(NavigateMsg{Route: "/box"})
+	app.Update(PublishMsg{Event: messageSent{Text: "queued"}})
+	app.Update(NavigateMsg{Route: "/inbox"})
+	if len(inbox.received) != 1 {
+		t.Errorf("expected the event queued for the inbox, got %v", inbox.received)
+	}
+}
@@ -25,7 +25,7 @@ func (l *Layout) Render() string {
 
 	// Header with title
 	if l.Title != "" {
-		header := TitleStyle.Render(l.Title)
+		header := TitleStyle().Render(l.Title)
 		b.WriteString(header)
 		b.WriteString("\n\n")
 	}
@@ -33,7 +33,7 @@ func (l *Layout) Render() string {
 	// Main content
 	if l.Content != "" {
 		if l.ShowBorder {
-			content := BoxStyle.Width(l.Width - 6).Render(l.Content)
+			content := BoxStyle().Width(l.Width - 6).Render(l.Content)
 			b.WriteString(content)
 		} else {
 			b.WriteString(l.Content)
@@ -43,12 +43,12 @@ func (l *Layout) Render() string {
 
 	// Footer
 	if l.Footer != "" {
-		footer := FooterStyle.Width(l.Width - 4).Render(l.Footer)
+		footer := FooterStyle().Width(l.Width - 4).Render(l.Footer)
 		b.WriteString(footer)
 	}
 
 	// Apply base style and dimensions
-	finalStyle := BaseStyle
+	finalStyle := BaseStyle()
 	if l.Width > 0 {
 		finalStyle = finalStyle.Width(l.Width)
 	}
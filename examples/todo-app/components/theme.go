@@ -2,83 +2,93 @@ package components
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/michael-duren/mvct"
 )
 
-var (
-	// Colors
-	PrimaryColor   = lipgloss.Color("#7C3AED") // Purple
-	SecondaryColor = lipgloss.Color("#EC4899") // Pink
-	AccentColor    = lipgloss.Color("#10B981") // Green
-	TextColor      = lipgloss.Color("#F3F4F6")
-	MutedColor     = lipgloss.Color("#9CA3AF")
-	BgColor        = lipgloss.Color("#1F2937")
-	BorderColor    = lipgloss.Color("#374151")
+// The styles are built from the current mvct theme on every call so they
+// follow runtime theme switches
 
-	// Base styles
-	BaseStyle = lipgloss.NewStyle().
-			Foreground(TextColor).
-			Background(BgColor)
+// Base styles
+func BaseStyle() lipgloss.Style {
+	theme := mvct.CurrentTheme()
+	return lipgloss.NewStyle().
+		Foreground(theme.Text.Color()).
+		Background(theme.Background.Color())
+}
 
-	// Header style
-	HeaderStyle = lipgloss.NewStyle().
-			Foreground(PrimaryColor).
-			Bold(true).
-			Padding(0, 1).
-			MarginBottom(1)
+// Header style
+func HeaderStyle() lipgloss.Style {
+	theme := mvct.CurrentTheme()
+	return theme.Style(theme.Primary).
+		Bold(true).
+		Padding(0, 1).
+		MarginBottom(1)
+}
 
-	// Title style
-	TitleStyle = lipgloss.NewStyle().
-			Foreground(PrimaryColor).
-			Bold(true).
-			Padding(1, 2).
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(PrimaryColor)
+// Title style
+func TitleStyle() lipgloss.Style {
+	theme := mvct.CurrentTheme()
+	return theme.Style(theme.Primary).
+		Bold(true).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Primary.Color())
+}
 
-	// List item styles
-	SelectedItemStyle = lipgloss.NewStyle().
-				Foreground(AccentColor).
-				Bold(true).
-				PaddingLeft(1)
+// List item styles
+func SelectedItemStyle() lipgloss.Style {
+	theme := mvct.CurrentTheme()
+	return theme.Style(theme.Selected).
+		Bold(true).
+		PaddingLeft(1)
+}
 
-	UnselectedItemStyle = lipgloss.NewStyle().
-				Foreground(TextColor).
-				PaddingLeft(1)
+func UnselectedItemStyle() lipgloss.Style {
+	theme := mvct.CurrentTheme()
+	return theme.Style(theme.Text).
+		PaddingLeft(1)
+}
 
-	// Input style
-	InputStyle = lipgloss.NewStyle().
-			Foreground(SecondaryColor).
-			Bold(true)
+// Help style
+func HelpStyle() lipgloss.Style {
+	theme := mvct.CurrentTheme()
+	return theme.Style(theme.Muted).
+		Italic(true).
+		MarginTop(1).
+		Padding(0, 1)
+}
 
-	// Help style
-	HelpStyle = lipgloss.NewStyle().
-			Foreground(MutedColor).
-			Italic(true).
-			MarginTop(1).
-			Padding(0, 1)
+// Footer style
+func FooterStyle() lipgloss.Style {
+	theme := mvct.CurrentTheme()
+	return theme.Style(theme.Muted).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderTop(true).
+		BorderForeground(theme.Border.Color()).
+		MarginTop(1).
+		Padding(1, 2)
+}
 
-	// Footer style
-	FooterStyle = lipgloss.NewStyle().
-			Foreground(MutedColor).
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderTop(true).
-			BorderForeground(BorderColor).
-			MarginTop(1).
-			Padding(1, 2)
+// Container/Box style
+func BoxStyle() lipgloss.Style {
+	theme := mvct.CurrentTheme()
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Border.Color()).
+		Padding(1, 2).
+		MarginTop(1)
+}
 
-	// Container/Box style
-	BoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(BorderColor).
-			Padding(1, 2).
-			MarginTop(1)
+// Error style
+func ErrorStyle() lipgloss.Style {
+	theme := mvct.CurrentTheme()
+	return theme.Style(theme.Error).
+		Bold(true)
+}
 
-	// Error style
-	ErrorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")).
-			Bold(true)
-
-	// Success style
-	SuccessStyle = lipgloss.NewStyle().
-			Foreground(AccentColor).
-			Bold(true)
-)
+// Success style
+func SuccessStyle() lipgloss.Style {
+	theme := mvct.CurrentTheme()
+	return theme.Style(theme.Success).
+		Bold(true)
+}
@@ -1,5 +1,7 @@
 package controllers
 
+//go:generate mvct gen
+
 var R = struct {
 	Home string
 	Exit string
@@ -3,25 +3,34 @@ package controllers
 import (
 	"example-todo/components"
 	"fmt"
-	"maps"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/michael-duren/mvct"
+	"github.com/michael-duren/mvct/forms"
 )
 
 type TodoModel struct {
 	todos  []string
 	cursor int
-	input  string
 	adding bool
 }
 
+// todoAddedMsg is sent by the add form when it is submitted
+type todoAddedMsg struct {
+	title string
+}
+
+// addCancelledMsg is sent by the add form when esc is pressed
+type addCancelledMsg struct{}
+
 type TodoController struct {
 	model TodoModel
+	form  *forms.Form
 }
 
 func NewTodoController() *TodoController {
-	return &TodoController{
+	c := &TodoController{
 		model: TodoModel{
 			todos: []string{
 				"Buy groceries",
@@ -30,44 +39,55 @@ func NewTodoController() *TodoController {
 			},
 		},
 	}
+
+	c.form = forms.New("add-todo",
+		forms.Text("title", "Title", forms.Required(), forms.Placeholder("What needs doing?")),
+	).OnSubmit(func(v forms.Values) tea.Msg {
+		return todoAddedMsg{title: v.String("title")}
+	}).OnCancel(func() tea.Msg {
+		return addCancelledMsg{}
+	})
+	c.form.Blur()
+
+	return c
 }
 
 func (c *TodoController) Init(handlers mvct.KeyHandlers) mvct.Cmd {
 	c.RegisterKeyHandlers(handlers)
-	return nil
+	return c.form.Init(handlers)
 }
 
 func (c *TodoController) View() string {
 	var b strings.Builder
 
 	if c.model.adding {
-		b.WriteString(components.HeaderStyle.Render("Add New Todo"))
+		b.WriteString(components.HeaderStyle().Render("Add New Todo"))
 		b.WriteString("\n\n")
-		b.WriteString(components.InputStyle.Render(c.model.input + "█"))
+		b.WriteString(c.form.View())
 		b.WriteString("\n\n")
-		b.WriteString(components.HelpStyle.Render("enter: save • esc: cancel"))
+		b.WriteString(components.HelpStyle().Render("enter: save • esc: cancel"))
 		return b.String()
 	}
 
 	// Todo list
-	b.WriteString(components.HeaderStyle.Render(fmt.Sprintf("Tasks (%d)", len(c.model.todos))))
+	b.WriteString(components.HeaderStyle().Render(fmt.Sprintf("Tasks (%d)", len(c.model.todos))))
 	b.WriteString("\n\n")
 
 	if len(c.model.todos) == 0 {
-		b.WriteString(components.UnselectedItemStyle.Render("  No todos yet. Press 'a' to add one!"))
+		b.WriteString(components.UnselectedItemStyle().Render("  No todos yet. Press 'a' to add one!"))
 	} else {
 		for i, todo := range c.model.todos {
 			if c.model.cursor == i {
-				b.WriteString(components.SelectedItemStyle.Render("▶ " + todo))
+				b.WriteString(components.SelectedItemStyle().Render("▶ " + todo))
 			} else {
-				b.WriteString(components.UnselectedItemStyle.Render("  " + todo))
+				b.WriteString(components.UnselectedItemStyle().Render("  " + todo))
 			}
 			b.WriteString("\n")
 		}
 	}
 
 	b.WriteString("\n")
-	b.WriteString(components.HelpStyle.Render("↑/k: up • ↓/j: down • a: add • d: delete • q: quit"))
+	b.WriteString(components.HelpStyle().Render("↑/k: up • ↓/j: down • a: add • d: delete • q: quit"))
 
 	return b.String()
 }
@@ -76,16 +96,16 @@ func (c *TodoController) GetModel() any {
 	return c.model
 }
 
+// RegisterKeyHandlers registers the list keys, they are inactive while the
+// add form is open so typing doesn't trigger them
 func (c *TodoController) RegisterKeyHandlers(handlers mvct.KeyHandlers) {
-	maps.Copy(handlers, mvct.Keys(
+	mvct.Bind(handlers, func() bool { return !c.model.adding },
 		mvct.Key("q").To(c.onQuit),
 		mvct.Key("j", "down").To(c.onDown),
 		mvct.Key("k", "up").To(c.onUp),
 		mvct.Key("a").To(c.onAdd),
 		mvct.Key("d").To(c.onDelete),
-		mvct.Key("enter").To(c.onEnter),
-		mvct.Key("esc").To(c.onEscape),
-	))
+	)
 }
 
 func (c *TodoController) onQuit(msg mvct.KeyMsg) mvct.Cmd {
@@ -107,10 +127,9 @@ func (c *TodoController) onUp(msg mvct.KeyMsg) mvct.Cmd {
 }
 
 func (c *TodoController) onAdd(msg mvct.KeyMsg) mvct.Cmd {
-	if !c.model.adding {
-		c.model.adding = true
-		c.model.input = ""
-	}
+	c.model.adding = true
+	c.form.Reset()
+	c.form.Focus()
 	return nil
 }
 
@@ -127,33 +146,23 @@ func (c *TodoController) onDelete(msg mvct.KeyMsg) mvct.Cmd {
 	return nil
 }
 
-func (c *TodoController) onEnter(msg mvct.KeyMsg) mvct.Cmd {
-	if c.model.adding && c.model.input != "" {
-		c.model.todos = append(c.model.todos, c.model.input)
-		c.model.adding = false
-		c.model.input = ""
-	}
+func (c *TodoController) OnTodoAddedMsg(msg todoAddedMsg) mvct.Cmd {
+	c.model.todos = append(c.model.todos, msg.title)
+	c.closeForm()
 	return nil
 }
 
-func (c *TodoController) onEscape(msg mvct.KeyMsg) mvct.Cmd {
-	if c.model.adding {
-		c.model.adding = false
-		c.model.input = ""
-	}
+func (c *TodoController) OnAddCancelledMsg(msg addCancelledMsg) mvct.Cmd {
+	c.closeForm()
 	return nil
 }
 
+// OnKeyMsg forwards typed characters to the add form
 func (c *TodoController) OnKeyMsg(msg mvct.KeyMsg) mvct.Cmd {
-	if c.model.adding {
-		// Handle typing
-		if len(msg.Runes) > 0 {
-			c.model.input += string(msg.Runes)
-		}
-		// Handle backspace
-		if msg.Type == mvct.KeyBackspace && len(c.model.input) > 0 {
-			c.model.input = c.model.input[:len(c.model.input)-1]
-		}
-	}
-	return nil
+	return c.form.OnKeyMsg(msg)
+}
+
+func (c *TodoController) closeForm() {
+	c.model.adding = false
+	c.form.Blur()
 }
@@ -20,13 +20,18 @@ func main() {
 	app.SetLayout(func(content string, width, height int) string {
 		layout := components.NewLayout("📝 TODO APP")
 		layout.Content = content
-		layout.Footer = "Press ? for help"
+		layout.Footer = "Press ? for help • ctrl+t: switch theme"
 		layout.Width = width
 		layout.Height = height
 		return layout.Render()
 	})
 
+	if err := app.UseTheme(mvct.ThemeConfig{}); err != nil {
+		log.Error("failed to load theme", "error", err)
+	}
+
 	app.UseGlobalHandler(mvct.QuitHandler("ctrl+c"))
+	app.UseGlobalHandler(mvct.ThemeSwitchHandler("ctrl+t"))
 	app.UseGlobalHandler(mvct.QuitHandler("q"))
 
 	app.UseLogger(mvct.LoggerConfig{
@@ -0,0 +1,213 @@
+package forms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/michael-duren/mvct"
+	"github.com/michael-duren/mvct/components"
+)
+
+// Field is a single input of a form. Fields are created with Text,
+// Password, Select, MultiSelect, Checkbox, Number and Date
+type Field interface {
+	components.Focusable
+
+	// Name is the key of the field in the submitted Values
+	Name() string
+	// Label is shown above the field
+	Label() string
+	// Value returns the typed value of the field
+	Value() any
+	// Reset clears the value of the field
+	Reset()
+	// OnKeyMsg receives the key messages the form forwards
+	OnKeyMsg(msg mvct.KeyMsg) mvct.Cmd
+
+	base() *fieldBase
+	// parse converts the input of the field to its value type
+	parse() (any, error)
+}
+
+// Validator checks the value of a field, the returned error is shown
+// under the field
+type Validator func(value any) error
+
+// AsyncValidator checks the value of a field in a command, for checks such
+// as asking a server whether a username is taken
+type AsyncValidator func(ctx context.Context, value any) error
+
+// Option configures a field
+type Option func(f *fieldBase)
+
+// Required fails validation when the field is empty
+func Required() Option {
+	return func(f *fieldBase) {
+		f.validators = append([]Validator{requiredValidator}, f.validators...)
+	}
+}
+
+// Validate adds synchronous validators to the field
+func Validate(validators ...Validator) Option {
+	return func(f *fieldBase) {
+		f.validators = append(f.validators, validators...)
+	}
+}
+
+// ValidateAsync adds asynchronous validators to the field. They run when
+// the field loses focus and before the form is submitted, once the
+// synchronous validators pass
+func ValidateAsync(validators ...AsyncValidator) Option {
+	return func(f *fieldBase) {
+		f.asyncValidators = append(f.asyncValidators, validators...)
+	}
+}
+
+// Description sets a hint shown next to the label
+func Description(text string) Option {
+	return func(f *fieldBase) {
+		f.description = text
+	}
+}
+
+// Placeholder sets the text shown in an empty text, number or date field
+func Placeholder(text string) Option {
+	return func(f *fieldBase) {
+		f.placeholder = text
+	}
+}
+
+// Default sets the initial value of the field
+func Default(value any) Option {
+	return func(f *fieldBase) {
+		f.initial = value
+	}
+}
+
+// ErrRequired is returned by the Required validator
+var ErrRequired = errors.New("required")
+
+func requiredValidator(value any) error {
+	switch v := value.(type) {
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return ErrRequired
+		}
+	case []string:
+		if len(v) == 0 {
+			return ErrRequired
+		}
+	case bool:
+		if !v {
+			return ErrRequired
+		}
+	case time.Time:
+		if v.IsZero() {
+			return ErrRequired
+		}
+	case nil:
+		return ErrRequired
+	}
+	return nil
+}
+
+// MinLength fails when a string value is shorter than n characters
+func MinLength(n int) Validator {
+	return func(value any) error {
+		if s, ok := value.(string); ok && len([]rune(s)) < n {
+			return fmt.Errorf("must be at least %d characters", n)
+		}
+		return nil
+	}
+}
+
+// MaxLength fails when a string value is longer than n characters
+func MaxLength(n int) Validator {
+	return func(value any) error {
+		if s, ok := value.(string); ok && len([]rune(s)) > n {
+			return fmt.Errorf("must be at most %d characters", n)
+		}
+		return nil
+	}
+}
+
+// Matches fails when a string value doesn't match the pattern
+func Matches(pattern *regexp.Regexp, message string) Validator {
+	return func(value any) error {
+		if s, ok := value.(string); ok && s != "" && !pattern.MatchString(s) {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// Range fails when a number value is outside of min and max
+func Range(min, max float64) Validator {
+	return func(value any) error {
+		if n, ok := value.(float64); ok && (n < min || n > max) {
+			return fmt.Errorf("must be between %g and %g", min, max)
+		}
+		return nil
+	}
+}
+
+// fieldBase holds the state shared by every field type
+type fieldBase struct {
+	name        string
+	label       string
+	description string
+	placeholder string
+	initial     any
+
+	validators      []Validator
+	asyncValidators []AsyncValidator
+
+	err        error
+	validating bool
+	// seq identifies the latest async validation so stale results from
+	// earlier values can be dropped
+	seq int
+	// asyncValid is the last value that passed the async validators
+	asyncValid any
+	asyncDone  bool
+}
+
+func newFieldBase(name, label string, opts []Option) fieldBase {
+	f := fieldBase{name: name, label: label}
+	for _, opt := range opts {
+		opt(&f)
+	}
+	return f
+}
+
+func (f *fieldBase) Name() string {
+	return f.name
+}
+
+func (f *fieldBase) Label() string {
+	return f.label
+}
+
+func (f *fieldBase) base() *fieldBase {
+	return f
+}
+
+// validate runs the synchronous validators, parseErr is the error of
+// fields whose text could not be converted to their value type
+func (f *fieldBase) validate(value any, parseErr error) error {
+	f.err = parseErr
+	if f.err != nil {
+		return f.err
+	}
+	for _, validator := range f.validators {
+		if err := validator(value); err != nil {
+			f.err = err
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,414 @@
+package forms
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/michael-duren/mvct"
+	"github.com/michael-duren/mvct/components"
+)
+
+// choiceState is the focus state of the fields that are not backed by a
+// text input
+type choiceState struct {
+	focused bool
+}
+
+func (c *choiceState) Focus() {
+	c.focused = true
+}
+
+func (c *choiceState) Blur() {
+	c.focused = false
+}
+
+func (c *choiceState) Focused() bool {
+	return c.focused
+}
+
+// TextField is a single line text field, its value is a string
+type TextField struct {
+	fieldBase
+	input *components.TextInput
+}
+
+// Text creates a text field
+func Text(name, label string, opts ...Option) *TextField {
+	f := &TextField{
+		fieldBase: newFieldBase(name, label, opts),
+		input:     components.NewTextInput(),
+	}
+	f.input.Placeholder = f.placeholder
+	f.Reset()
+	return f
+}
+
+// Password creates a text field that masks its value
+func Password(name, label string, opts ...Option) *TextField {
+	f := Text(name, label, opts...)
+	f.input.Mask = '•'
+	return f
+}
+
+// Input returns the text input backing the field, e.g. to set a CharLimit
+func (f *TextField) Input() *components.TextInput {
+	return f.input
+}
+
+func (f *TextField) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	return f.input.Init(handlers)
+}
+
+func (f *TextField) View() string                      { return f.input.View() }
+func (f *TextField) Bindings() []mvct.KeyBinding       { return f.input.Bindings() }
+func (f *TextField) Focus()                            { f.input.Focus() }
+func (f *TextField) Blur()                             { f.input.Blur() }
+func (f *TextField) Focused() bool                     { return f.input.Focused() }
+func (f *TextField) OnKeyMsg(msg mvct.KeyMsg) mvct.Cmd { return f.input.OnKeyMsg(msg) }
+func (f *TextField) Value() any                        { return f.input.Value() }
+
+func (f *TextField) Reset() {
+	initial, _ := f.initial.(string)
+	f.input.SetValue(initial)
+}
+
+func (f *TextField) parse() (any, error) {
+	return f.input.Value(), nil
+}
+
+// NumberField is a text field that only accepts numbers, its value is a
+// float64 or nil when empty
+type NumberField struct {
+	TextField
+}
+
+// Number creates a number field
+func Number(name, label string, opts ...Option) *NumberField {
+	f := &NumberField{TextField: *Text(name, label, opts...)}
+	if initial, ok := f.initial.(float64); ok {
+		f.input.SetValue(strconv.FormatFloat(initial, 'f', -1, 64))
+	}
+	return f
+}
+
+// OnKeyMsg only lets characters through that can be part of a number
+func (f *NumberField) OnKeyMsg(msg mvct.KeyMsg) mvct.Cmd {
+	for _, r := range msg.Runes {
+		if !strings.ContainsRune("0123456789.-+eE", r) {
+			return mvct.Handled()
+		}
+	}
+	return f.input.OnKeyMsg(msg)
+}
+
+func (f *NumberField) Value() any {
+	value, _ := f.parse()
+	return value
+}
+
+func (f *NumberField) Reset() {
+	f.input.Reset()
+	if initial, ok := f.initial.(float64); ok {
+		f.input.SetValue(strconv.FormatFloat(initial, 'f', -1, 64))
+	}
+}
+
+func (f *NumberField) parse() (any, error) {
+	text := strings.TrimSpace(f.input.Value())
+	if text == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a number", text)
+	}
+	return n, nil
+}
+
+// DateField is a text field for dates, its value is a time.Time which is
+// zero when the field is empty
+type DateField struct {
+	TextField
+	// Layout is the format dates are typed in, see time.Parse
+	Layout string
+}
+
+// Date creates a date field using the YYYY-MM-DD layout
+func Date(name, label string, opts ...Option) *DateField {
+	f := &DateField{TextField: *Text(name, label, opts...), Layout: time.DateOnly}
+	if f.input.Placeholder == "" {
+		f.input.Placeholder = "YYYY-MM-DD"
+	}
+	f.Reset()
+	return f
+}
+
+func (f *DateField) Value() any {
+	value, _ := f.parse()
+	return value
+}
+
+func (f *DateField) Reset() {
+	f.input.Reset()
+	if initial, ok := f.initial.(time.Time); ok {
+		f.input.SetValue(initial.Format(f.Layout))
+	}
+}
+
+func (f *DateField) parse() (any, error) {
+	text := strings.TrimSpace(f.input.Value())
+	if text == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(f.Layout, text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a date like %s", time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC).Format(f.Layout))
+	}
+	return t, nil
+}
+
+// SelectField picks one of several options, its value is the selected
+// option
+type SelectField struct {
+	fieldBase
+	choiceState
+
+	options  []string
+	selected int
+}
+
+// Select creates a select field, the first option is selected by default
+func Select(name, label string, options []string, opts ...Option) *SelectField {
+	f := &SelectField{
+		fieldBase: newFieldBase(name, label, opts),
+		options:   options,
+	}
+	f.Reset()
+	return f
+}
+
+func (f *SelectField) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	mvct.Bind(handlers, f.Focused, f.Bindings()...)
+	return nil
+}
+
+func (f *SelectField) Bindings() []mvct.KeyBinding {
+	return []mvct.KeyBinding{
+		mvct.Key("left", "h").To(f.onPrev).Help("previous option"),
+		mvct.Key("right", "l").To(f.onNext).Help("next option"),
+	}
+}
+
+func (f *SelectField) View() string {
+	styles := components.DefaultStyles()
+	rendered := make([]string, len(f.options))
+	for i, option := range f.options {
+		switch {
+		case i == f.selected && f.focused:
+			rendered[i] = styles.Selected.Render("(•) " + option)
+		case i == f.selected:
+			rendered[i] = styles.Normal.Render("(•) " + option)
+		default:
+			rendered[i] = styles.Muted.Render("( ) " + option)
+		}
+	}
+	return strings.Join(rendered, "  ")
+}
+
+func (f *SelectField) OnKeyMsg(msg mvct.KeyMsg) mvct.Cmd {
+	return nil
+}
+
+func (f *SelectField) Value() any {
+	if len(f.options) == 0 {
+		return ""
+	}
+	return f.options[f.selected]
+}
+
+func (f *SelectField) Reset() {
+	f.selected = 0
+	if initial, ok := f.initial.(string); ok {
+		f.selected = max(slices.Index(f.options, initial), 0)
+	}
+}
+
+func (f *SelectField) parse() (any, error) {
+	return f.Value(), nil
+}
+
+func (f *SelectField) onPrev(msg mvct.KeyMsg) mvct.Cmd {
+	if len(f.options) > 0 {
+		f.selected = (f.selected - 1 + len(f.options)) % len(f.options)
+	}
+	return nil
+}
+
+func (f *SelectField) onNext(msg mvct.KeyMsg) mvct.Cmd {
+	if len(f.options) > 0 {
+		f.selected = (f.selected + 1) % len(f.options)
+	}
+	return nil
+}
+
+// MultiSelectField picks any number of options, its value is a []string of
+// the chosen options in the order they were declared
+type MultiSelectField struct {
+	fieldBase
+	choiceState
+
+	options []string
+	chosen  map[int]bool
+	cursor  int
+}
+
+// MultiSelect creates a multi select field
+func MultiSelect(name, label string, options []string, opts ...Option) *MultiSelectField {
+	f := &MultiSelectField{
+		fieldBase: newFieldBase(name, label, opts),
+		options:   options,
+	}
+	f.Reset()
+	return f
+}
+
+func (f *MultiSelectField) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	mvct.Bind(handlers, f.Focused, f.Bindings()...)
+	return nil
+}
+
+func (f *MultiSelectField) Bindings() []mvct.KeyBinding {
+	return []mvct.KeyBinding{
+		mvct.Key("left", "h").To(f.onPrev).Help("previous option"),
+		mvct.Key("right", "l").To(f.onNext).Help("next option"),
+		mvct.Key(" ", "x").To(f.onToggle).Help("toggle"),
+	}
+}
+
+func (f *MultiSelectField) View() string {
+	styles := components.DefaultStyles()
+	rendered := make([]string, len(f.options))
+	for i, option := range f.options {
+		box := "[ ] "
+		if f.chosen[i] {
+			box = "[x] "
+		}
+		switch {
+		case i == f.cursor && f.focused:
+			rendered[i] = styles.Selected.Render(box + option)
+		case f.chosen[i]:
+			rendered[i] = styles.Normal.Render(box + option)
+		default:
+			rendered[i] = styles.Muted.Render(box + option)
+		}
+	}
+	return strings.Join(rendered, "  ")
+}
+
+func (f *MultiSelectField) OnKeyMsg(msg mvct.KeyMsg) mvct.Cmd {
+	return nil
+}
+
+func (f *MultiSelectField) Value() any {
+	values := []string{}
+	for i, option := range f.options {
+		if f.chosen[i] {
+			values = append(values, option)
+		}
+	}
+	return values
+}
+
+func (f *MultiSelectField) Reset() {
+	f.cursor = 0
+	f.chosen = map[int]bool{}
+	initial, _ := f.initial.([]string)
+	for i, option := range f.options {
+		f.chosen[i] = slices.Contains(initial, option)
+	}
+}
+
+func (f *MultiSelectField) parse() (any, error) {
+	return f.Value(), nil
+}
+
+func (f *MultiSelectField) onPrev(msg mvct.KeyMsg) mvct.Cmd {
+	f.cursor = max(f.cursor-1, 0)
+	return nil
+}
+
+func (f *MultiSelectField) onNext(msg mvct.KeyMsg) mvct.Cmd {
+	f.cursor = min(f.cursor+1, max(len(f.options)-1, 0))
+	return nil
+}
+
+func (f *MultiSelectField) onToggle(msg mvct.KeyMsg) mvct.Cmd {
+	if len(f.options) > 0 {
+		f.chosen[f.cursor] = !f.chosen[f.cursor]
+	}
+	return nil
+}
+
+// CheckboxField is a yes/no toggle, its value is a bool
+type CheckboxField struct {
+	fieldBase
+	choiceState
+
+	checked bool
+}
+
+// Checkbox creates a checkbox field
+func Checkbox(name, label string, opts ...Option) *CheckboxField {
+	f := &CheckboxField{
+		fieldBase: newFieldBase(name, label, opts),
+	}
+	f.Reset()
+	return f
+}
+
+func (f *CheckboxField) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	mvct.Bind(handlers, f.Focused, f.Bindings()...)
+	return nil
+}
+
+func (f *CheckboxField) Bindings() []mvct.KeyBinding {
+	return []mvct.KeyBinding{
+		mvct.Key(" ", "x").To(f.onToggle).Help("toggle"),
+	}
+}
+
+func (f *CheckboxField) View() string {
+	styles := components.DefaultStyles()
+	box := "[ ]"
+	if f.checked {
+		box = "[x]"
+	}
+	if f.focused {
+		return styles.Selected.Render(box)
+	}
+	return styles.Normal.Render(box)
+}
+
+func (f *CheckboxField) OnKeyMsg(msg mvct.KeyMsg) mvct.Cmd {
+	return nil
+}
+
+func (f *CheckboxField) Value() any {
+	return f.checked
+}
+
+func (f *CheckboxField) Reset() {
+	f.checked, _ = f.initial.(bool)
+}
+
+func (f *CheckboxField) parse() (any, error) {
+	return f.checked, nil
+}
+
+func (f *CheckboxField) onToggle(msg mvct.KeyMsg) mvct.Cmd {
+	f.checked = !f.checked
+	return nil
+}
@@ -0,0 +1,440 @@
+// Package forms builds keyboard driven forms out of declarative field
+// definitions:
+//
+//	form := forms.New("signup",
+//		forms.Text("name", "Name", forms.Required()),
+//		forms.Password("password", "Password", forms.Validate(forms.MinLength(8))),
+//		forms.Select("plan", "Plan", []string{"free", "pro"}),
+//	).OnSubmit(func(v forms.Values) tea.Msg {
+//		return SignupMsg{Name: v.String("name"), Plan: v.String("plan")}
+//	})
+//
+// A form is a components.Focusable. The owning controller registers it in
+// Init and forwards OnKeyMsg and OnValidationMsg to it; the message
+// returned from OnSubmit is then dispatched to the controller's own On*
+// handler like any other message.
+package forms
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-duren/mvct"
+	"github.com/michael-duren/mvct/components"
+)
+
+// SubmitMsg is sent when a form without an OnSubmit function is submitted
+type SubmitMsg struct {
+	Form   string
+	Values Values
+}
+
+// CancelMsg is sent when esc is pressed in a form without an OnCancel
+// function
+type CancelMsg struct {
+	Form string
+}
+
+// ValidationMsg carries the result of a field's async validators. The
+// owning controller forwards it to Form.OnValidationMsg
+type ValidationMsg struct {
+	Form  string
+	Field string
+	Seq   int
+	Err   error
+}
+
+// Form is a list of fields with focus handling, validation and submission
+type Form struct {
+	Title string
+	// Styles pins custom styles, nil follows the current theme
+	Styles *components.Styles
+	// AsyncTimeout bounds the context passed to async validators
+	AsyncTimeout time.Duration
+
+	id         string
+	fields     []Field
+	group      *components.FocusGroup
+	onSubmit   func(Values) tea.Msg
+	onCancel   func() tea.Msg
+	submitting bool
+}
+
+// New creates a form, id identifies it in the messages it sends
+func New(id string, fields ...Field) *Form {
+	items := make([]components.Focusable, len(fields))
+	for i, field := range fields {
+		items[i] = field
+	}
+
+	return &Form{
+		AsyncTimeout: 10 * time.Second,
+		id:           id,
+		fields:       fields,
+		group:        components.NewFocusGroup(items...),
+	}
+}
+
+// OnSubmit sets the function that turns the submitted values into the
+// message sent to the owning controller
+func (f *Form) OnSubmit(fn func(Values) tea.Msg) *Form {
+	f.onSubmit = fn
+	return f
+}
+
+// OnCancel sets the function that creates the message sent when esc is
+// pressed
+func (f *Form) OnCancel(fn func() tea.Msg) *Form {
+	f.onCancel = fn
+	return f
+}
+
+// ID returns the id of the form
+func (f *Form) ID() string {
+	return f.id
+}
+
+// Init registers the key bindings of every field and of the form
+func (f *Form) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	cmds := make([]mvct.Cmd, 0, len(f.fields))
+	keys := make(map[string]bool)
+	for _, field := range f.fields {
+		cmds = append(cmds, field.Init(handlers))
+		for _, binding := range field.Bindings() {
+			for _, key := range binding.Keys() {
+				keys[key] = true
+			}
+		}
+	}
+	// editing keys like backspace are bound by the fields, watch them for
+	// changes like typed characters
+	for key := range keys {
+		handler := handlers[key]
+		handlers[key] = func(msg mvct.KeyMsg) mvct.Cmd {
+			field := f.current()
+			if field == nil {
+				return handler(msg)
+			}
+			before := field.Value()
+			cmd := handler(msg)
+			f.edited(field, before)
+			return cmd
+		}
+	}
+	mvct.Bind(handlers, f.Focused, f.Bindings()...)
+	return mvct.Batch(cmds...)
+}
+
+// Bindings returns the form level key bindings
+func (f *Form) Bindings() []mvct.KeyBinding {
+	return []mvct.KeyBinding{
+		mvct.Key(mvct.KeyTab.String()).To(f.onNext).Help("next field"),
+		mvct.Key(mvct.KeyShiftTab.String()).To(f.onPrev).Help("previous field"),
+		mvct.Key("enter").To(f.onEnter).Help("next/submit"),
+		mvct.Key("ctrl+s").To(f.onSubmitKey).Help("submit"),
+		mvct.Key("esc").To(f.onEscape).Help("cancel"),
+	}
+}
+
+// HelpBindings returns the bindings of the focused field followed by the
+// form bindings, for use with components.Help
+func (f *Form) HelpBindings() []mvct.KeyBinding {
+	var bindings []mvct.KeyBinding
+	if current := f.group.Current(); current != nil {
+		bindings = append(bindings, current.Bindings()...)
+	}
+	return append(bindings, f.Bindings()...)
+}
+
+func (f *Form) Focus()        { f.group.Focus() }
+func (f *Form) Blur()         { f.group.Blur() }
+func (f *Form) Focused() bool { return f.group.Focused() }
+
+// OnKeyMsg forwards typed characters to the focused field
+func (f *Form) OnKeyMsg(msg mvct.KeyMsg) mvct.Cmd {
+	if !f.Focused() {
+		return nil
+	}
+	field := f.current()
+	if field == nil {
+		return nil
+	}
+
+	before := field.Value()
+	cmd := field.OnKeyMsg(msg)
+	f.edited(field, before)
+	return cmd
+}
+
+// edited clears the error of a field whose value changed and drops its
+// async validation in flight, which checked the old value
+func (f *Form) edited(field Field, before any) {
+	if reflect.DeepEqual(before, field.Value()) {
+		return
+	}
+	b := field.base()
+	b.err = nil
+	b.seq++
+	b.validating = false
+	f.submitting = false
+}
+
+// OnValidationMsg records the result of async validation and finishes a
+// pending submission once every field is validated
+func (f *Form) OnValidationMsg(msg ValidationMsg) mvct.Cmd {
+	if msg.Form != f.id {
+		return nil
+	}
+	field := f.Field(msg.Field)
+	if field == nil || field.base().seq != msg.Seq {
+		slog.Debug("Dropping stale validation result", "form", f.id, "field", msg.Field)
+		return nil
+	}
+
+	b := field.base()
+	b.validating = false
+	b.err = msg.Err
+	b.asyncDone = msg.Err == nil
+
+	if !f.submitting || f.pending() {
+		return nil
+	}
+	f.submitting = false
+	if f.focusFirstInvalid() {
+		return nil
+	}
+	return f.submit()
+}
+
+func (f *Form) View() string {
+	styles := f.Styles.Resolve()
+	var b strings.Builder
+
+	if f.Title != "" {
+		b.WriteString(styles.Header.Render(f.Title))
+		b.WriteString("\n\n")
+	}
+
+	for i, field := range f.fields {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+
+		label := styles.Normal.Render(field.Label())
+		if field.Focused() {
+			label = styles.Header.Render(field.Label())
+		}
+		b.WriteString(label)
+		if description := field.base().description; description != "" {
+			b.WriteString(" " + styles.Muted.Render(description))
+		}
+		b.WriteString("\n")
+		b.WriteString(field.View())
+
+		base := field.base()
+		switch {
+		case base.err != nil:
+			b.WriteString("\n" + styles.Error.Render("✗ "+base.err.Error()))
+		case base.validating:
+			b.WriteString("\n" + styles.Muted.Render("validating…"))
+		}
+	}
+
+	if f.submitting {
+		b.WriteString("\n\n" + styles.Muted.Render("submitting…"))
+	}
+
+	return b.String()
+}
+
+// Field returns the field with the given name
+func (f *Form) Field(name string) Field {
+	for _, field := range f.fields {
+		if field.Name() == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// Values returns the current value of every field
+func (f *Form) Values() Values {
+	values := make(Values, len(f.fields))
+	for _, field := range f.fields {
+		values[field.Name()] = field.Value()
+	}
+	return values
+}
+
+// Errors returns the validation errors by field name
+func (f *Form) Errors() map[string]error {
+	errs := map[string]error{}
+	for _, field := range f.fields {
+		if err := field.base().err; err != nil {
+			errs[field.Name()] = err
+		}
+	}
+	return errs
+}
+
+// Submitting reports whether the form is waiting on async validators
+// before it submits
+func (f *Form) Submitting() bool {
+	return f.submitting
+}
+
+// Submit validates every field and sends the submit message once they are
+// all valid. When async validators are involved the message is sent after
+// their results arrive through OnValidationMsg
+func (f *Form) Submit() mvct.Cmd {
+	cmds := make([]mvct.Cmd, 0, len(f.fields))
+	for _, field := range f.fields {
+		cmds = append(cmds, f.validate(field))
+	}
+
+	if f.focusFirstInvalid() {
+		f.submitting = false
+		return mvct.Batch(cmds...)
+	}
+	if f.pending() {
+		f.submitting = true
+		return mvct.Batch(cmds...)
+	}
+	return f.submit()
+}
+
+// Reset clears every field and error and focuses the first field
+func (f *Form) Reset() {
+	for _, field := range f.fields {
+		field.Reset()
+		b := field.base()
+		b.err = nil
+		b.validating = false
+		b.asyncDone = false
+		b.seq++
+	}
+	f.submitting = false
+	f.group.FocusIndex(0)
+}
+
+func (f *Form) current() Field {
+	if current := f.group.Current(); current != nil {
+		return current.(Field)
+	}
+	return nil
+}
+
+// validate runs the sync validators of a field and returns the command
+// running its async validators when they need to run
+func (f *Form) validate(field Field) mvct.Cmd {
+	b := field.base()
+	value, parseErr := field.parse()
+
+	if err := b.validate(value, parseErr); err != nil {
+		// invalidate any async validation still in flight
+		b.seq++
+		b.validating = false
+		return nil
+	}
+	if len(b.asyncValidators) == 0 {
+		return nil
+	}
+	if b.asyncDone && reflect.DeepEqual(b.asyncValid, value) {
+		return nil
+	}
+
+	b.seq++
+	b.validating = true
+	b.asyncDone = false
+	b.asyncValid = value
+
+	id, name, seq := f.id, field.Name(), b.seq
+	validators, timeout := b.asyncValidators, f.AsyncTimeout
+	return func() mvct.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		var err error
+		for _, validator := range validators {
+			if err = validator(ctx, value); err != nil {
+				break
+			}
+		}
+		return mvct.Msg{
+			Inner:   ValidationMsg{Form: id, Field: name, Seq: seq, Err: err},
+			Context: context.Background(),
+		}
+	}
+}
+
+func (f *Form) pending() bool {
+	for _, field := range f.fields {
+		if field.base().validating {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Form) focusFirstInvalid() bool {
+	for i, field := range f.fields {
+		if field.base().err != nil {
+			f.group.FocusIndex(i)
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Form) submit() mvct.Cmd {
+	values, id, fn := f.Values(), f.id, f.onSubmit
+	return func() mvct.Msg {
+		if fn != nil {
+			return mvct.Msg{Inner: fn(values)}
+		}
+		return mvct.Msg{Inner: SubmitMsg{Form: id, Values: values}}
+	}
+}
+
+func (f *Form) onNext(msg mvct.KeyMsg) mvct.Cmd {
+	var cmd mvct.Cmd
+	if field := f.current(); field != nil {
+		cmd = f.validate(field)
+	}
+	f.group.Next()
+	return cmd
+}
+
+func (f *Form) onPrev(msg mvct.KeyMsg) mvct.Cmd {
+	var cmd mvct.Cmd
+	if field := f.current(); field != nil {
+		cmd = f.validate(field)
+	}
+	f.group.Prev()
+	return cmd
+}
+
+func (f *Form) onEnter(msg mvct.KeyMsg) mvct.Cmd {
+	if f.group.Index() == len(f.fields)-1 {
+		return f.Submit()
+	}
+	return f.onNext(msg)
+}
+
+func (f *Form) onSubmitKey(msg mvct.KeyMsg) mvct.Cmd {
+	return f.Submit()
+}
+
+func (f *Form) onEscape(msg mvct.KeyMsg) mvct.Cmd {
+	id, fn := f.id, f.onCancel
+	return func() mvct.Msg {
+		if fn != nil {
+			return mvct.Msg{Inner: fn()}
+		}
+		return mvct.Msg{Inner: CancelMsg{Form: id}}
+	}
+}
@@ -0,0 +1,264 @@
+package forms
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-duren/mvct"
+)
+
+type signupMsg struct {
+	Name string
+	Plan string
+}
+
+func press(handlers mvct.KeyHandlers, keyType tea.KeyType) mvct.Cmd {
+	msg := mvct.KeyMsg{Type: keyType}
+	if handler, ok := handlers[msg.String()]; ok {
+		return handler(msg)
+	}
+	return nil
+}
+
+func pressRune(form *Form, handlers mvct.KeyHandlers, r rune) {
+	msg := mvct.KeyMsg{Type: mvct.KeyRunes, Runes: []rune{r}}
+	if handler, ok := handlers[msg.String()]; ok {
+		handler(msg)
+	}
+	form.OnKeyMsg(msg)
+}
+
+func typeText(form *Form, s string) {
+	for _, r := range s {
+		form.OnKeyMsg(mvct.KeyMsg{Type: mvct.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestFormSubmit(t *testing.T) {
+	form := New("signup",
+		Text("name", "Name", Required()),
+		Select("plan", "Plan", []string{"free", "pro"}),
+	).OnSubmit(func(v Values) tea.Msg {
+		return signupMsg{Name: v.String("name"), Plan: v.String("plan")}
+	})
+	handlers := mvct.KeyHandlers{}
+	form.Init(handlers)
+
+	typeText(form, "Ada")
+	if cmd := press(handlers, mvct.KeyEnter); cmd != nil {
+		t.Error("enter on the first field should move focus, not submit")
+	}
+	if form.Field("plan").Focused() != true {
+		t.Fatal("expected the plan field to be focused")
+	}
+
+	press(handlers, mvct.KeyRight)
+	cmd := press(handlers, mvct.KeyEnter)
+	if cmd == nil {
+		t.Fatal("enter on the last field should submit")
+	}
+
+	msg, ok := cmd().Inner.(signupMsg)
+	if !ok {
+		t.Fatalf("expected signupMsg, got %T", cmd().Inner)
+	}
+	if msg.Name != "Ada" || msg.Plan != "pro" {
+		t.Errorf("unexpected submitted values %+v", msg)
+	}
+}
+
+func TestFormRequired(t *testing.T) {
+	form := New("todo",
+		Text("title", "Title", Required()),
+		Text("notes", "Notes"),
+	)
+	handlers := mvct.KeyHandlers{}
+	form.Init(handlers)
+	press(handlers, mvct.KeyTab)
+
+	if cmd := form.Submit(); cmd != nil {
+		t.Error("invalid form should not submit")
+	}
+	if !errors.Is(form.Errors()["title"], ErrRequired) {
+		t.Errorf("expected title to be required, got %v", form.Errors())
+	}
+	if !form.Field("title").Focused() {
+		t.Error("the first invalid field should be focused")
+	}
+	if !strings.Contains(form.View(), "required") {
+		t.Error("expected the error to be shown inline")
+	}
+
+	typeText(form, "x")
+	if len(form.Errors()) != 0 {
+		t.Error("typing should clear the error of the field")
+	}
+
+	msg, ok := form.Submit()().Inner.(SubmitMsg)
+	if !ok || msg.Form != "todo" || msg.Values.String("title") != "x" {
+		t.Errorf("expected SubmitMsg for todo, got %+v", msg)
+	}
+}
+
+func TestFormFocusOrder(t *testing.T) {
+	form := New("order", Text("a", "A"), Text("b", "B"), Text("c", "C"))
+	handlers := mvct.KeyHandlers{}
+	form.Init(handlers)
+
+	press(handlers, mvct.KeyShiftTab)
+	pressRune(form, handlers, 'c')
+	press(handlers, mvct.KeyTab)
+	pressRune(form, handlers, 'a')
+
+	values := form.Values()
+	if values.String("a") != "a" || values.String("c") != "c" || values.String("b") != "" {
+		t.Errorf("unexpected values %v", values)
+	}
+
+	form.Blur()
+	pressRune(form, handlers, 'z')
+	if form.Values().String("a") != "a" {
+		t.Error("blurred form should ignore input")
+	}
+}
+
+func TestFormFieldTypes(t *testing.T) {
+	due := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	form := New("types",
+		Number("estimate", "Estimate", Validate(Range(0, 10))),
+		Date("due", "Due", Default(due)),
+		MultiSelect("tags", "Tags", []string{"work", "home", "urgent"}),
+		Checkbox("done", "Done"),
+		Password("secret", "Secret", Default("hunter2")),
+	)
+	handlers := mvct.KeyHandlers{}
+	form.Init(handlers)
+
+	typeText(form, "1x2")
+	press(handlers, mvct.KeyTab)
+	press(handlers, mvct.KeyTab)
+	pressRune(form, handlers, ' ')
+	press(handlers, mvct.KeyRight)
+	press(handlers, mvct.KeyRight)
+	pressRune(form, handlers, 'x')
+	press(handlers, mvct.KeyTab)
+	pressRune(form, handlers, ' ')
+
+	values := form.Values()
+	if values.Float("estimate") != 12 {
+		t.Errorf("expected estimate 12, got %v", values["estimate"])
+	}
+	if !values.Time("due").Equal(due) {
+		t.Errorf("expected due %v, got %v", due, values["due"])
+	}
+	if tags := values.Strings("tags"); len(tags) != 2 || tags[0] != "work" || tags[1] != "urgent" {
+		t.Errorf("expected [work urgent], got %v", tags)
+	}
+	if !values.Bool("done") {
+		t.Error("expected done to be checked")
+	}
+	if values.String("secret") != "hunter2" || strings.Contains(form.View(), "hunter2") {
+		t.Error("password value should be kept but masked in the view")
+	}
+
+	form.Field("estimate").(*NumberField).Input().SetValue("42")
+	form.Field("due").(*DateField).Input().SetValue("tomorrow")
+	form.Submit()
+	errs := form.Errors()
+	if errs["estimate"] == nil || errs["due"] == nil {
+		t.Errorf("expected range and date errors, got %v", errs)
+	}
+}
+
+func TestFormAsyncValidation(t *testing.T) {
+	taken := errors.New("username is taken")
+	form := New("signup",
+		Text("username", "Username", Required(), ValidateAsync(func(ctx context.Context, value any) error {
+			if value == "admin" {
+				return taken
+			}
+			return nil
+		})),
+	)
+	form.Init(mvct.KeyHandlers{})
+
+	typeText(form, "admin")
+	cmd := form.Submit()
+	if cmd == nil || !form.Submitting() {
+		t.Fatal("expected async validation to run before submitting")
+	}
+	if !strings.Contains(form.View(), "validating") {
+		t.Error("expected pending validation to be shown")
+	}
+
+	result := cmd().Inner.(ValidationMsg)
+	if submit := form.OnValidationMsg(result); submit != nil {
+		t.Error("form should not submit when async validation fails")
+	}
+	if form.Errors()["username"] != taken {
+		t.Errorf("expected async error, got %v", form.Errors())
+	}
+
+	form.Field("username").(*TextField).Input().SetValue("ada")
+	cmd = form.Submit()
+	stale := result
+	if form.OnValidationMsg(stale) != nil {
+		t.Error("stale validation results should be dropped")
+	}
+
+	submit := form.OnValidationMsg(cmd().Inner.(ValidationMsg))
+	if submit == nil {
+		t.Fatal("form should submit once async validation passes")
+	}
+	if msg := submit().Inner.(SubmitMsg); msg.Values.String("username") != "ada" {
+		t.Errorf("unexpected submitted values %v", msg.Values)
+	}
+}
+
+func TestFormCancel(t *testing.T) {
+	form := New("todo", Text("title", "Title")).OnCancel(func() tea.Msg { return "cancelled" })
+	handlers := mvct.KeyHandlers{}
+	form.Init(handlers)
+
+	cmd := press(handlers, mvct.KeyEsc)
+	if cmd == nil || cmd().Inner != "cancelled" {
+		t.Error("esc should send the cancel message")
+	}
+}
+
+func TestFormEditClearsErrorAndValidation(t *testing.T) {
+	form := New("signup",
+		Text("username", "Username", Required(), ValidateAsync(func(ctx context.Context, value any) error {
+			return errors.New("username is taken")
+		})),
+	)
+	handlers := mvct.KeyHandlers{}
+	form.Init(handlers)
+
+	typeText(form, "ada")
+	cmd := form.Submit()
+	if cmd == nil {
+		t.Fatal("expected async validation to run")
+	}
+	stale := cmd().Inner.(ValidationMsg)
+
+	// backspace is bound by the field, not sent through OnKeyMsg
+	press(handlers, mvct.KeyBackspace)
+	if form.Submitting() {
+		t.Error("expected an edit to cancel the pending submission")
+	}
+	form.OnValidationMsg(stale)
+	if err := form.Errors()["username"]; err != nil {
+		t.Errorf("expected the result for the old value to be dropped, got %v", err)
+	}
+
+	form.Field("username").base().err = errors.New("too short")
+	press(handlers, mvct.KeyBackspace)
+	if err := form.Errors()["username"]; err != nil {
+		t.Errorf("expected backspace to clear the error, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package forms
+
+import "time"
+
+// Values maps field names to their values. The getters return the zero
+// value when a field is missing or has a different type
+type Values map[string]any
+
+// String returns the value of a text, password or select field
+func (v Values) String(name string) string {
+	s, _ := v[name].(string)
+	return s
+}
+
+// Strings returns the value of a multi select field
+func (v Values) Strings(name string) []string {
+	s, _ := v[name].([]string)
+	return s
+}
+
+// Bool returns the value of a checkbox field
+func (v Values) Bool(name string) bool {
+	b, _ := v[name].(bool)
+	return b
+}
+
+// Float returns the value of a number field
+func (v Values) Float(name string) float64 {
+	f, _ := v[name].(float64)
+	return f
+}
+
+// Int returns the value of a number field truncated to an int
+func (v Values) Int(name string) int {
+	return int(v.Float(name))
+}
+
+// Time returns the value of a date field
+func (v Values) Time(name string) time.Time {
+	t, _ := v[name].(time.Time)
+	return t
+}
@@ -0,0 +1,297 @@
+// Package dev rebuilds and restarts an mvct app when its source changes
+package dev
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/michael-duren/mvct"
+)
+
+const (
+	// defaultDelay is how long changes are collected before a rebuild, an
+	// editor saving several files causes one build
+	defaultDelay = 200 * time.Millisecond
+	// exitTimeout is how long the app has to save its session and exit
+	// before it is killed
+	exitTimeout = 5 * time.Second
+)
+
+// Config configures a dev session
+type Config struct {
+	// Dir is the root of the project, the current directory when empty
+	Dir string
+	// Package is the main package, found in Dir or cmd/ when empty
+	Package string
+	// Args are passed to the app
+	Args []string
+	// Extensions of files besides .go, go.mod and go.sum that trigger a
+	// rebuild, like .tmpl for embedded templates
+	Extensions []string
+	// Delay overrides defaultDelay
+	Delay time.Duration
+	// Output receives the build errors and status lines while the app is
+	// not running, os.Stderr when nil
+	Output io.Writer
+	// Stdin and Stdout of the app, os.Stdin and os.Stdout when nil
+	Stdin  io.Reader
+	Stdout io.Writer
+}
+
+func (c Config) withDefaults() Config {
+	if c.Dir == "" {
+		c.Dir = "."
+	}
+	if c.Delay == 0 {
+		c.Delay = defaultDelay
+	}
+	if c.Output == nil {
+		c.Output = os.Stderr
+	}
+	if c.Stdin == nil {
+		c.Stdin = os.Stdin
+	}
+	if c.Stdout == nil {
+		c.Stdout = os.Stdout
+	}
+	return c
+}
+
+// MainPackage returns the main package of the project in dir, "." when dir
+// has a main.go or the only package under cmd/
+func MainPackage(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, "main.go")); err == nil {
+		return ".", nil
+	}
+	mains, _ := filepath.Glob(filepath.Join(dir, "cmd", "*", "main.go"))
+	switch len(mains) {
+	case 1:
+		return "./" + filepath.ToSlash(filepath.Join("cmd", filepath.Base(filepath.Dir(mains[0])))), nil
+	case 0:
+		return "", fmt.Errorf("no main.go in %s or cmd/, pass the main package", dir)
+	}
+	return "", fmt.Errorf("%d main packages under cmd/, pass the one to run", len(mains))
+}
+
+// relevant reports whether a change of path affects the build
+func (c Config) relevant(path string) bool {
+	name := filepath.Base(path)
+	switch {
+	case name == "go.mod", name == "go.sum":
+		return true
+	case strings.HasSuffix(name, "_test.go"), strings.HasPrefix(name, "."):
+		return false
+	}
+	ext := filepath.Ext(name)
+	return ext == ".go" || slices.Contains(c.Extensions, ext)
+}
+
+// skipDir reports whether the directory at path is left out of the watch
+func skipDir(name string) bool {
+	return name != "." && (strings.HasPrefix(name, ".") || name == "testdata" || name == "vendor" || name == "node_modules")
+}
+
+// watch adds dir and the directories below it to watcher
+func watch(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		if path != dir && skipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// session is a running dev session
+type session struct {
+	config Config
+	// dir is shared with the app through mvct.DevEnv
+	dir    string
+	binary string
+
+	app *exec.Cmd
+	// exited receives the result of the running app
+	exited chan error
+}
+
+// Run builds the project, runs it and rebuilds it when its source changes.
+// A new build replaces the app once it saved its session, a failed build
+// is shown by the running app. Run returns when the app exits on its own
+// or ctx is done
+func Run(ctx context.Context, config Config) error {
+	config = config.withDefaults()
+	if config.Package == "" {
+		pkg, err := MainPackage(config.Dir)
+		if err != nil {
+			return err
+		}
+		config.Package = pkg
+	}
+
+	dir, err := os.MkdirTemp("", "mvct-dev-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+	if err := watch(watcher, config.Dir); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", config.Dir, err)
+	}
+
+	binary := filepath.Join(dir, "app")
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+	s := &session{config: config, dir: dir, binary: binary, exited: make(chan error, 1)}
+	defer s.stop()
+
+	if err := s.rebuild(); err == nil {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+
+	var changed <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() && !skipDir(info.Name()) {
+					watch(watcher, event.Name)
+				}
+			}
+			if config.relevant(event.Name) {
+				changed = time.After(config.Delay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("file watcher failed: %w", err)
+		case <-changed:
+			changed = nil
+			if err := s.rebuild(); err != nil {
+				continue
+			}
+			if err := s.restart(); err != nil {
+				return err
+			}
+		case err := <-s.exited:
+			s.app = nil
+			if err == nil {
+				return nil
+			}
+			fmt.Fprintf(config.Output, "mvct dev: the app exited: %v, waiting for changes\n", err)
+		}
+	}
+}
+
+// rebuild builds the project next to the running binary. The errors go to
+// the running app, or to Output when no app runs
+func (s *session) rebuild() error {
+	s.status(mvct.DevStatus{Building: true})
+	output, err := s.build()
+	if err == nil {
+		s.status(mvct.DevStatus{})
+		return nil
+	}
+	if s.app != nil {
+		s.status(mvct.DevStatus{Errors: output})
+	} else {
+		fmt.Fprintf(s.config.Output, "mvct dev: build failed, waiting for changes\n%s", output)
+	}
+	return err
+}
+
+// build runs go build and returns its output when it fails
+func (s *session) build() (string, error) {
+	var out bytes.Buffer
+	cmd := exec.Command("go", "build", "-o", s.binary+".next", s.config.Package)
+	cmd.Dir = s.config.Dir
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Run(); err != nil {
+		if out.Len() == 0 {
+			return err.Error() + "\n", err
+		}
+		return out.String(), err
+	}
+	return "", nil
+}
+
+// restart asks the running app to exit, waits for it and starts the new
+// build
+func (s *session) restart() error {
+	if s.app != nil {
+		s.status(mvct.DevStatus{Restart: true})
+		select {
+		case <-s.exited:
+		case <-time.After(exitTimeout):
+			fmt.Fprintln(s.config.Output, "mvct dev: the app did not exit, killing it")
+			s.app.Process.Kill()
+			<-s.exited
+		}
+		s.app = nil
+	}
+	return s.start()
+}
+
+// start runs the last successful build
+func (s *session) start() error {
+	if err := os.Rename(s.binary+".next", s.binary); err != nil {
+		return err
+	}
+	// a new app must not read the restart of the previous one
+	s.status(mvct.DevStatus{})
+
+	cmd := exec.Command(s.binary, s.config.Args...)
+	cmd.Dir = s.config.Dir
+	cmd.Env = append(os.Environ(), mvct.DevEnv+"="+s.dir)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = s.config.Stdin, s.config.Stdout, os.Stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start the app: %w", err)
+	}
+	s.app = cmd
+	go func() {
+		s.exited <- cmd.Wait()
+	}()
+	return nil
+}
+
+// stop kills the running app
+func (s *session) stop() {
+	if s.app == nil {
+		return
+	}
+	s.app.Process.Kill()
+	<-s.exited
+	s.app = nil
+}
+
+func (s *session) status(status mvct.DevStatus) {
+	if err := mvct.WriteDevStatus(s.dir, status); err != nil {
+		fmt.Fprintf(s.config.Output, "mvct dev: failed to write the status: %v\n", err)
+	}
+}
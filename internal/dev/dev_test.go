@@ -0,0 +1,147 @@
+package dev
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMainPackage(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := MainPackage(dir); err == nil {
+		t.Error("expected an error without a main package")
+	}
+
+	os.MkdirAll(filepath.Join(dir, "cmd", "cli"), 0755)
+	os.WriteFile(filepath.Join(dir, "cmd", "cli", "main.go"), []byte("package main\n"), 0644)
+	if pkg, err := MainPackage(dir); err != nil || pkg != "./cmd/cli" {
+		t.Errorf("expected ./cmd/cli, got %q, %v", pkg, err)
+	}
+
+	os.MkdirAll(filepath.Join(dir, "cmd", "server"), 0755)
+	os.WriteFile(filepath.Join(dir, "cmd", "server", "main.go"), []byte("package main\n"), 0644)
+	if _, err := MainPackage(dir); err == nil {
+		t.Error("expected an error with two main packages")
+	}
+
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644)
+	if pkg, err := MainPackage(dir); err != nil || pkg != "." {
+		t.Errorf("expected ., got %q, %v", pkg, err)
+	}
+}
+
+func TestRelevant(t *testing.T) {
+	config := Config{Extensions: []string{".tmpl"}}
+	for path, want := range map[string]bool{
+		"controllers/home.go":      true,
+		"go.mod":                   true,
+		"go.sum":                   true,
+		"templates/page.tmpl":      true,
+		"controllers/home_test.go": false,
+		"controllers/.home.go.swp": false,
+		"app.db":                   false,
+		"README.md":                false,
+	} {
+		if got := config.relevant(path); got != want {
+			t.Errorf("relevant(%q): expected %v, got %v", path, want, got)
+		}
+	}
+}
+
+// app stands in for an mvct app, it logs its version when it starts and
+// when the status has errors, and exits when asked to restart
+const app = `package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const version = %q
+
+func log(line string) {
+	f, _ := os.OpenFile("runs.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	fmt.Fprintln(f, line)
+	f.Close()
+}
+
+func main() {
+	log("start " + version)
+	errors := false
+	for {
+		data, _ := os.ReadFile(filepath.Join(os.Getenv("MVCT_DEV"), "status.json"))
+		if strings.Contains(string(data), "restart") {
+			return
+		}
+		if strings.Contains(string(data), "errors") && !errors {
+			errors = true
+			log("errors " + version)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+`
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a program")
+	}
+
+	dir := t.TempDir()
+	write := func(name, source string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example\n\ngo 1.21\n")
+	write("main.go", strings.Replace(app, "%q", `"1"`, 1))
+
+	waitFor := func(line string) {
+		t.Helper()
+		deadline := time.Now().Add(30 * time.Second)
+		for time.Now().Before(deadline) {
+			data, _ := os.ReadFile(filepath.Join(dir, "runs.log"))
+			if strings.Contains(string(data), line+"\n") {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		data, _ := os.ReadFile(filepath.Join(dir, "runs.log"))
+		t.Fatalf("expected %q in the runs, got:\n%s", line, data)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var output strings.Builder
+	var wg sync.WaitGroup
+	var err error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err = Run(ctx, Config{Dir: dir, Output: &output, Stdin: strings.NewReader(""), Stdout: &strings.Builder{}})
+	}()
+
+	waitFor("start 1")
+	write("main.go", strings.Replace(app, "%q", `"2"`, 1))
+	waitFor("start 2")
+
+	// a failed build is shown by the running app
+	write("broken.go", "package main\n\nfunc broken() { x }\n")
+	waitFor("errors 2")
+
+	cancel()
+	wg.Wait()
+	if err != nil {
+		t.Errorf("expected Run to stop with ctx, got %v", err)
+	}
+	if output.Len() > 0 {
+		t.Errorf("expected no output while the app runs, got:\n%s", output.String())
+	}
+}
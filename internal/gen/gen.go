@@ -0,0 +1,289 @@
+// Package gen generates typed dispatch tables for mvct controllers. The
+// generated MessageHandlers method lists every On* handler through
+// mvct.HandleMsg, so the application can skip reflection and a handler
+// with the wrong signature fails to compile
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// DefaultOutput is the name of the generated file in each package
+const DefaultOutput = "mvct_handlers_gen.go"
+
+const mvctPath = "github.com/michael-duren/mvct"
+
+// File is the generated dispatch table of a package
+type File struct {
+	// Path is where the file is written
+	Path string
+	// Source is nil when the package has no controllers, an existing file
+	// at Path is then stale
+	Source      []byte
+	Controllers []Controller
+	// Problems lists handlers with an invalid signature. They are still
+	// added to the table so the build reports them
+	Problems []string
+	// Skipped lists On* methods left out of the table because they set a
+	// callback, like a fluent OnSelect(fn) *Table
+	Skipped []string
+}
+
+// Controller is a controller found in a package
+type Controller struct {
+	Name     string
+	Pointer  bool
+	Handlers []string
+}
+
+// Generate loads the packages matching patterns from dir and builds the
+// dispatch table of each, output is the file name used in every package
+func Generate(dir, output string, patterns ...string) ([]File, error) {
+	if output == "" {
+		output = DefaultOutput
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	overlay, err := staleOverlay(dir, output, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &packages.Config{
+		Mode:    packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedImports,
+		Dir:     dir,
+		Overlay: overlay,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	var files []File
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("failed to load %s: %v", pkg.PkgPath, pkg.Errors[0])
+		}
+		if len(pkg.GoFiles) == 0 {
+			continue
+		}
+
+		file, err := generatePackage(pkg, filepath.Join(filepath.Dir(pkg.GoFiles[0]), output))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// Write writes the generated files and removes stale ones
+func Write(files []File) error {
+	for _, file := range files {
+		if file.Source == nil {
+			if err := os.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove stale %s: %w", file.Path, err)
+			}
+			continue
+		}
+		if err := os.WriteFile(file.Path, file.Source, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+	}
+	return nil
+}
+
+// staleOverlay hides previously generated files while loading, a table
+// that no longer type checks must not stop it from being regenerated
+func staleOverlay(dir, output string, patterns []string) (map[string][]byte, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedFiles, Dir: dir}, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages: %w", err)
+	}
+
+	overlay := map[string][]byte{}
+	for _, pkg := range pkgs {
+		for _, path := range pkg.GoFiles {
+			if filepath.Base(path) != output {
+				continue
+			}
+			f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			overlay[path] = []byte("package " + f.Name.Name + "\n")
+		}
+	}
+	return overlay, nil
+}
+
+func generatePackage(pkg *packages.Package, path string) (File, error) {
+	file := File{Path: path}
+
+	mvct := lookupMvct(pkg.Types)
+	if mvct == nil {
+		return file, nil
+	}
+	controllerType := mvct.Scope().Lookup("Controller").Type().Underlying().(*types.Interface)
+	cmdType := mvct.Scope().Lookup("Cmd").Type()
+
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 || types.IsInterface(named) {
+			continue
+		}
+
+		var recv types.Type
+		switch {
+		case types.Implements(named, controllerType):
+			recv = named
+		case types.Implements(types.NewPointer(named), controllerType):
+			recv = types.NewPointer(named)
+		default:
+			continue
+		}
+
+		if handWritten(recv, pkg.Types) {
+			continue
+		}
+
+		controller := Controller{Name: name, Pointer: recv != named}
+		methods := types.NewMethodSet(recv)
+		for i := range methods.Len() {
+			fn := methods.At(i).Obj().(*types.Func)
+			if !fn.Exported() || !strings.HasPrefix(fn.Name(), "On") {
+				continue
+			}
+			sig := fn.Type().(*types.Signature)
+			if callbackSetter(sig) {
+				file.Skipped = append(file.Skipped, fmt.Sprintf("%s: %s.%s sets a callback, it is not a handler",
+					pkg.Fset.Position(fn.Pos()), name, fn.Name()))
+				continue
+			}
+			controller.Handlers = append(controller.Handlers, fn.Name())
+
+			if sig.Params().Len() != 1 || sig.Results().Len() != 1 || !types.Identical(sig.Results().At(0).Type(), cmdType) {
+				file.Problems = append(file.Problems, fmt.Sprintf("%s: %s.%s has signature %s, expected func(msg T) mvct.Cmd",
+					pkg.Fset.Position(fn.Pos()), name, fn.Name(), types.TypeString(sig, shortQualifier(pkg.Types))))
+			}
+		}
+		file.Controllers = append(file.Controllers, controller)
+	}
+
+	if len(file.Controllers) == 0 {
+		return file, nil
+	}
+
+	source, err := render(pkg.Name, pkg.PkgPath == mvctPath, file.Controllers)
+	if err != nil {
+		return file, fmt.Errorf("failed to generate %s: %w", path, err)
+	}
+	file.Source = source
+	return file, nil
+}
+
+// callbackSetter reports whether an On* method registers a callback
+// instead of handling a message, the same rule as mvct lint: it takes a
+// func or returns its own receiver
+func callbackSetter(sig *types.Signature) bool {
+	for i := range sig.Params().Len() {
+		if _, ok := sig.Params().At(i).Type().Underlying().(*types.Signature); ok {
+			return true
+		}
+	}
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	for i := range sig.Results().Len() {
+		result := sig.Results().At(i).Type()
+		if ptr, ok := result.(*types.Pointer); ok {
+			result = ptr.Elem()
+		}
+		if types.Identical(result, recv) {
+			return true
+		}
+	}
+	return false
+}
+
+func lookupMvct(pkg *types.Package) *types.Package {
+	if pkg.Path() == mvctPath {
+		return pkg
+	}
+	for _, imported := range pkg.Imports() {
+		if imported.Path() == mvctPath {
+			return imported
+		}
+	}
+	return nil
+}
+
+// handWritten reports whether the controller declares MessageHandlers
+// itself. The overlay hides the generated file so any declaration found is
+// hand written, a table promoted from an embedded type is replaced
+func handWritten(recv types.Type, pkg *types.Package) bool {
+	obj, index, _ := types.LookupFieldOrMethod(recv, true, pkg, "MessageHandlers")
+	return obj != nil && len(index) == 1
+}
+
+func render(pkgName string, inMvct bool, controllers []Controller) ([]byte, error) {
+	qualifier := "mvct."
+	if inMvct {
+		qualifier = ""
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by mvct gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkgName)
+	if !inMvct {
+		fmt.Fprintf(&b, "import %q\n\n", mvctPath)
+	}
+
+	for _, controller := range controllers {
+		recv := controller.Name
+		if controller.Pointer {
+			recv = "*" + recv
+		}
+		fmt.Fprintf(&b, "// MessageHandlers returns the dispatch table of %s\n", controller.Name)
+		fmt.Fprintf(&b, "func (c %s) MessageHandlers() []%sMessageHandler {\n", recv, qualifier)
+		if len(controller.Handlers) == 0 {
+			b.WriteString("\treturn nil\n}\n\n")
+			continue
+		}
+		fmt.Fprintf(&b, "\treturn []%sMessageHandler{\n", qualifier)
+		for _, handler := range controller.Handlers {
+			fmt.Fprintf(&b, "\t\t%sHandleMsg(c.%s),\n", qualifier, handler)
+		}
+		b.WriteString("\t}\n}\n\n")
+	}
+
+	return format.Source(b.Bytes())
+}
+
+// shortQualifier prints types of other packages with their package name
+func shortQualifier(pkg *types.Package) types.Qualifier {
+	return func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+}
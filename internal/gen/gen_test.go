@@ -0,0 +1,91 @@
+package gen
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join("testdata", "controllers")
+	files, err := Generate(dir, "")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(files))
+	}
+	file := files[0]
+
+	if filepath.Base(file.Path) != DefaultOutput {
+		t.Errorf("expected %s, got %s", DefaultOutput, file.Path)
+	}
+
+	var names []string
+	for _, controller := range file.Controllers {
+		names = append(names, controller.Name)
+	}
+	if strings.Join(names, ",") != "BrokenController,HomeController,TableController" {
+		t.Errorf("expected the controllers without a hand written table, got %v", names)
+	}
+
+	source := string(file.Source)
+	for _, want := range []string{
+		"// Code generated by mvct gen. DO NOT EDIT.",
+		"func (c *HomeController) MessageHandlers() []mvct.MessageHandler {",
+		"mvct.HandleMsg(c.OnSavedMsg),",
+		"mvct.HandleMsg(c.OnKeyMsg),",
+		"mvct.HandleMsg(c.OnTwo),",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("expected generated source to contain %q, got:\n%s", want, source)
+		}
+	}
+	if strings.Count(source, "mvct.HandleMsg(c.OnSavedMsg)") != 2 {
+		t.Error("expected promoted handlers in the table of BrokenController")
+	}
+
+	if len(file.Problems) != 1 || !strings.Contains(file.Problems[0], "BrokenController.OnTwo") {
+		t.Errorf("expected a problem for OnTwo, got %v", file.Problems)
+	}
+	if strings.Contains(source, "c.OnSelect") {
+		t.Errorf("expected the OnSelect builder to be left out, got:\n%s", source)
+	}
+	if len(file.Skipped) != 1 || !strings.Contains(file.Skipped[0], "TableController.OnSelect") {
+		t.Errorf("expected a warning for OnSelect, got %v", file.Skipped)
+	}
+}
+
+func TestGenerateCompileError(t *testing.T) {
+	dir := filepath.Join("testdata", "controllers")
+	files, err := Generate(dir, "")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	path, err := filepath.Abs(files[0].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &packages.Config{
+		Mode:    packages.NeedTypes | packages.NeedSyntax,
+		Dir:     dir,
+		Overlay: map[string][]byte{path: files[0].Source},
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := pkgs[0].Errors
+	if len(errs) == 0 {
+		t.Fatal("expected the mis-typed handler to fail to compile")
+	}
+	for _, e := range errs {
+		if !strings.Contains(e.Msg, "OnTwo") {
+			t.Errorf("expected only OnTwo to fail, got %v", e)
+		}
+	}
+}
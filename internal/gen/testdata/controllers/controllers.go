@@ -0,0 +1,43 @@
+package controllers
+
+import "github.com/michael-duren/mvct"
+
+type savedMsg struct{}
+
+type HomeController struct{}
+
+func (c *HomeController) Init(handlers mvct.KeyHandlers) mvct.Cmd { return nil }
+func (c *HomeController) View() string                            { return "home" }
+func (c *HomeController) OnSavedMsg(msg savedMsg) mvct.Cmd        { return nil }
+func (c *HomeController) OnKeyMsg(msg mvct.KeyMsg) mvct.Cmd       { return nil }
+
+// BrokenController inherits the handlers of HomeController
+type BrokenController struct {
+	HomeController
+}
+
+func (c *BrokenController) OnTwo(a, b int) mvct.Cmd { return nil }
+
+// TableController has a fluent OnSelect like components.Table, which is
+// not a handler
+type TableController struct {
+	onSelect func(row int) mvct.Cmd
+}
+
+func (c *TableController) Init(handlers mvct.KeyHandlers) mvct.Cmd { return nil }
+func (c *TableController) View() string                            { return "table" }
+
+func (c *TableController) OnSelect(fn func(row int) mvct.Cmd) *TableController {
+	c.onSelect = fn
+	return c
+}
+
+type ManualController struct{}
+
+func (c ManualController) Init(handlers mvct.KeyHandlers) mvct.Cmd { return nil }
+func (c ManualController) View() string                            { return "manual" }
+func (c ManualController) MessageHandlers() []mvct.MessageHandler  { return nil }
+
+type notAController struct{}
+
+func (c *notAController) OnSavedMsg(msg savedMsg) mvct.Cmd { return nil }
@@ -0,0 +1,170 @@
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around a change
+const diffContext = 3
+
+// lineEdit is a line of a diff, op is ' ', '-' or '+'
+type lineEdit struct {
+	op   byte
+	line string
+}
+
+// lines splits source into lines keeping their newlines
+func lines(source []byte) []string {
+	if len(source) == 0 {
+		return nil
+	}
+	split := strings.SplitAfter(string(source), "\n")
+	if split[len(split)-1] == "" {
+		split = split[:len(split)-1]
+	}
+	return split
+}
+
+// diffLines returns the edits turning a into b from their longest common
+// subsequence, removals come before additions
+func diffLines(a, b []string) []lineEdit {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var edits []lineEdit
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, lineEdit{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, lineEdit{'-', a[i]})
+			i++
+		default:
+			edits = append(edits, lineEdit{'+', b[j]})
+			j++
+		}
+	}
+	return edits
+}
+
+// unifiedDiff returns the changes from old to new in unified format, empty
+// when they are equal
+func unifiedDiff(name string, old, new []byte) string {
+	edits := diffLines(lines(old), lines(new))
+
+	// line numbers in old and new before each edit
+	oldLine, newLine := make([]int, len(edits)+1), make([]int, len(edits)+1)
+	for k, e := range edits {
+		oldLine[k+1], newLine[k+1] = oldLine[k], newLine[k]
+		if e.op != '+' {
+			oldLine[k+1]++
+		}
+		if e.op != '-' {
+			newLine[k+1]++
+		}
+	}
+
+	var b strings.Builder
+	for start := 0; start < len(edits); {
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+		// changes closer than twice the context share a hunk
+		end := start
+		for k := start; k < len(edits); k++ {
+			if edits[k].op != ' ' {
+				end = k + 1
+			} else if k-end >= 2*diffContext {
+				break
+			}
+		}
+		first, last := max(start-diffContext, 0), min(end+diffContext, len(edits))
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", name, name)
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n",
+			hunkRange(oldLine[first], oldLine[last]-oldLine[first]),
+			hunkRange(newLine[first], newLine[last]-newLine[first]))
+		for _, e := range edits[first:last] {
+			b.WriteByte(e.op)
+			writeLine(&b, e.line)
+		}
+		start = last
+	}
+	return b.String()
+}
+
+// hunkRange formats the lines of a hunk, an empty range names the line
+// before it
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprint(before + 1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// merge keeps the lines old and new share and marks the lines they differ
+// in like git does, reporting whether there were any
+func merge(old, new []byte) ([]byte, bool) {
+	edits := diffLines(lines(old), lines(new))
+
+	var b bytes.Buffer
+	conflict := false
+	for k := 0; k < len(edits); {
+		if edits[k].op == ' ' {
+			b.WriteString(edits[k].line)
+			k++
+			continue
+		}
+		var existing, template []string
+		for ; k < len(edits) && edits[k].op != ' '; k++ {
+			if edits[k].op == '-' {
+				existing = append(existing, edits[k].line)
+			} else {
+				template = append(template, edits[k].line)
+			}
+		}
+		conflict = true
+		b.WriteString("<<<<<<< existing\n")
+		for _, line := range existing {
+			writeLine(&b, line)
+		}
+		b.WriteString("=======\n")
+		for _, line := range template {
+			writeLine(&b, line)
+		}
+		b.WriteString(">>>>>>> template\n")
+	}
+	return b.Bytes(), conflict
+}
+
+func writeLine(w io.StringWriter, line string) {
+	w.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		w.WriteString("\n")
+	}
+}
@@ -0,0 +1,43 @@
+package scaffold
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	old := []byte("a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n")
+	new := []byte("a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n")
+	want := `--- a/f.txt
++++ b/f.txt
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -11,3 +11,4 @@
+ k
+ l
+ m
++n
+`
+	if got := unifiedDiff("f.txt", old, new); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+	if got := unifiedDiff("f.txt", old, old); got != "" {
+		t.Errorf("expected no diff of equal files, got\n%s", got)
+	}
+	if got := unifiedDiff("f.txt", nil, []byte("a")); got != "--- a/f.txt\n+++ b/f.txt\n@@ -0,0 +1 @@\n+a\n" {
+		t.Errorf("expected a new file to be added, got\n%s", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	merged, conflict := merge([]byte("a\nb\nc\n"), []byte("a\nB\nc\n"))
+	want := "a\n<<<<<<< existing\nb\n=======\nB\n>>>>>>> template\nc\n"
+	if !conflict || string(merged) != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, merged)
+	}
+	if merged, conflict := merge([]byte("a\n"), []byte("a\n")); conflict || string(merged) != "a\n" {
+		t.Errorf("expected equal files to merge cleanly, got %q", merged)
+	}
+}
@@ -0,0 +1,603 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/michael-duren/mvct/internal/templates"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// File is a file created or changed by a generator
+type File struct {
+	Path   string
+	Source []byte
+	// New is set when the file does not exist yet
+	New bool
+}
+
+// Project is an existing mvct project generators add files to
+type Project struct {
+	// Dir is the root of the project, where its go.mod is
+	Dir string
+	// Main is the file calling RegisterController, default cmd/cli/main.go
+	// or main.go
+	Main string
+}
+
+// names are the spellings of a generated name
+type names struct {
+	// Type is the exported Go name, like UserProfileController
+	Type string
+	// Field is the Go name without the kind suffix, like UserProfile
+	Field string
+	// File is the file name, like user_profile.go
+	File string
+	// Route is the route path, like /user-profile
+	Route string
+	// Title is the name for humans, like User profile
+	Title string
+	// Back is the route field controllers navigate back to
+	Back string
+}
+
+// newNames splits name at dashes, underscores, spaces and case changes and
+// drops a kind suffix like Controller
+func newNames(name, suffix string) (names, error) {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = nil
+		}
+	}
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == '_' || unicode.IsSpace(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+	if len(words) > 1 && words[len(words)-1] == strings.ToLower(suffix) {
+		words = words[:len(words)-1]
+	}
+
+	var field strings.Builder
+	for _, w := range words {
+		field.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	n := names{
+		Field: field.String(),
+		File:  strings.Join(words, "_") + ".go",
+		Route: "/" + strings.Join(words, "-"),
+	}
+	if !token.IsIdentifier(n.Field) || !token.IsExported(n.Field) {
+		return names{}, fmt.Errorf("%q is not a valid name, use letters and digits like user-profile", name)
+	}
+	n.Type = n.Field + suffix
+	n.Title = strings.ToUpper(words[0][:1]) + words[0][1:]
+	if len(words) > 1 {
+		n.Title += " " + strings.Join(words[1:], " ")
+	}
+	return n, nil
+}
+
+// GenerateController creates a controller, adds its route to the R struct
+// in controllers/routing.go and registers it in main
+func GenerateController(project Project, name string) ([]File, error) {
+	n, err := newNames(name, "Controller")
+	if err != nil {
+		return nil, err
+	}
+	module, err := project.module()
+	if err != nil {
+		return nil, err
+	}
+
+	routingPath := filepath.Join(project.Dir, "controllers", "routing.go")
+	routing, back, err := addRoute(routingPath, n.Field, n.Route)
+	if err != nil {
+		return nil, err
+	}
+	n.Back = back
+
+	controller, err := project.newFile(filepath.Join("controllers", n.File), templates.ControllerTemplate, n)
+	if err != nil {
+		return nil, err
+	}
+
+	mainPath, err := project.mainPath()
+	if err != nil {
+		return nil, err
+	}
+	main, err := editMain(mainPath, module+"/controllers", func(m *mainEdit) error {
+		register := func(b builder, app string, route ast.Expr) ast.Stmt {
+			return &ast.ExprStmt{X: b.call(b.sel(b.ident(app), "RegisterController"), route, b.call(b.sel(b.ident(m.pkg), "New"+n.Type)))}
+		}
+		last, app := m.lastCall("RegisterController")
+		if last < 0 {
+			return m.beforeRun(func(b builder, app string) ast.Stmt {
+				return register(b, app, b.sel(b.sel(b.ident(m.pkg), "R"), n.Field))
+			})
+		}
+		// register the route the way the previous registration does, like
+		// R.Home or controllers.R.Home
+		previous := m.call(last).Args[0]
+		m.insert(last+1, func(b builder) ast.Stmt {
+			route := b.sel(b.sel(b.ident(m.pkg), "R"), n.Field)
+			if sel, ok := previous.(*ast.SelectorExpr); ok {
+				if x := b.expr(sel.X); x != nil {
+					route = b.sel(x, n.Field)
+				}
+			}
+			return register(b, app, route)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []File{controller, routing, main}, nil
+}
+
+// GenerateMiddleware creates a middleware in the middleware package and
+// adds it to the application in main
+func GenerateMiddleware(project Project, name string) ([]File, error) {
+	n, err := newNames(name, "Middleware")
+	if err != nil {
+		return nil, err
+	}
+	module, err := project.module()
+	if err != nil {
+		return nil, err
+	}
+
+	middleware, err := project.newFile(filepath.Join("middleware", n.File), templates.MiddlewareTemplate, n)
+	if err != nil {
+		return nil, err
+	}
+
+	mainPath, err := project.mainPath()
+	if err != nil {
+		return nil, err
+	}
+	main, err := editMain(mainPath, module+"/middleware", func(m *mainEdit) error {
+		use := func(b builder, app string) ast.Stmt {
+			return &ast.ExprStmt{X: b.call(b.sel(b.ident(app), "Use"), b.call(b.sel(b.ident(m.pkg), n.Type)))}
+		}
+		// middleware goes after the last Use, or before the first route is
+		// registered
+		if last, app := m.lastCall("Use"); last >= 0 {
+			m.insert(last+1, func(b builder) ast.Stmt { return use(b, app) })
+			return nil
+		}
+		if first, app := m.firstCall("RegisterController"); first >= 0 {
+			m.insert(first, func(b builder) ast.Stmt { return use(b, app) })
+			return nil
+		}
+		return m.beforeRun(use)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []File{middleware, main}, nil
+}
+
+// GenerateComponent creates a component in the components package
+func GenerateComponent(project Project, name string) ([]File, error) {
+	n, err := newNames(name, "")
+	if err != nil {
+		return nil, err
+	}
+	component, err := project.newFile(filepath.Join("components", n.File), templates.ComponentTemplate, n)
+	if err != nil {
+		return nil, err
+	}
+	return []File{component}, nil
+}
+
+// Write writes generated files, creating their directories. When a file
+// cannot be written the files written before it are put back
+func Write(files []File) error {
+	var r rollback
+	for _, file := range files {
+		if err := r.write(file); err != nil {
+			return errors.Join(err, r.undo())
+		}
+	}
+	return nil
+}
+
+func (p Project) module() (string, error) {
+	data, err := os.ReadFile(filepath.Join(p.Dir, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("not in an mvct project, run this next to go.mod: %w", err)
+	}
+	module := modfile.ModulePath(data)
+	if module == "" {
+		return "", errors.New("go.mod has no module path")
+	}
+	return module, nil
+}
+
+func (p Project) mainPath() (string, error) {
+	if p.Main != "" {
+		return filepath.Join(p.Dir, p.Main), nil
+	}
+	for _, candidate := range []string{filepath.Join("cmd", "cli", "main.go"), "main.go"} {
+		if _, err := os.Stat(filepath.Join(p.Dir, candidate)); err == nil {
+			return filepath.Join(p.Dir, candidate), nil
+		}
+	}
+	return "", errors.New("main.go not found, pass its path with --main")
+}
+
+// newFile renders a template to a file that must not exist yet
+func (p Project) newFile(name, tmpl string, data any) (File, error) {
+	path := filepath.Join(p.Dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return File{}, fmt.Errorf("%s already exists", path)
+	}
+
+	src, err := render(name, tmpl, data)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: path, Source: src, New: true}, nil
+}
+
+// addRoute adds a field to the struct of the R variable and its value to
+// the literal, returning the first route as the one to go back to
+func addRoute(path, field, route string) (File, string, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, "", err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return File{}, "", err
+	}
+
+	lit := routesLit(file)
+	if lit == nil {
+		return File{}, "", fmt.Errorf("%s: no var R = struct{...}{...} to add the route to", path)
+	}
+
+	fields := lit.Type.(*ast.StructType).Fields
+	var back string
+	for _, f := range fields.List {
+		for _, name := range f.Names {
+			if name.Name == field {
+				return File{}, "", fmt.Errorf("%s: route %s already exists", path, field)
+			}
+			if back == "" {
+				back = name.Name
+			}
+		}
+	}
+	if back == "" {
+		back = field
+	}
+
+	newField := func(b builder) *ast.Field {
+		return &ast.Field{Names: []*ast.Ident{b.ident(field)}, Type: b.ident("string")}
+	}
+	newValue := func(b builder) *ast.KeyValueExpr {
+		return &ast.KeyValueExpr{Key: b.ident(field), Colon: token.Pos(b), Value: b.str(route)}
+	}
+	// the field and value go on lines of their own below the last ones,
+	// so comments around them stay where they are
+	fieldAbove, valueAbove := fields.Opening, lit.Lbrace
+	if n := len(fields.List); n > 0 {
+		fieldAbove = fields.List[n-1].End()
+	}
+	if n := len(lit.Elts); n > 0 {
+		valueAbove = lit.Elts[n-1].End()
+	}
+	room := max(width(newField(0)), width(newValue(0)))
+	fset, file, at, err := parseWithRoom(path, src, fset, room, fieldAbove, valueAbove)
+	if err != nil {
+		return File{}, "", err
+	}
+	lit = routesLit(file)
+	fields = lit.Type.(*ast.StructType).Fields
+	fields.List = append(fields.List, newField(at[0]))
+	lit.Elts = append(lit.Elts, newValue(at[1]))
+
+	var b bytes.Buffer
+	if err := format.Node(&b, fset, file); err != nil {
+		return File{}, "", fmt.Errorf("%s: %w", path, err)
+	}
+	return File{Path: path, Source: b.Bytes()}, back, nil
+}
+
+// routesLit returns the literal of var R = struct{...}{...}
+func routesLit(file *ast.File) *ast.CompositeLit {
+	var lit *ast.CompositeLit
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok || len(spec.Names) != 1 || spec.Names[0].Name != "R" || len(spec.Values) != 1 {
+			return lit == nil
+		}
+		if l, ok := spec.Values[0].(*ast.CompositeLit); ok {
+			if _, ok := l.Type.(*ast.StructType); ok {
+				lit = l
+			}
+		}
+		return false
+	})
+	return lit
+}
+
+// mainEdit is a main file a generator adds a statement to
+type mainEdit struct {
+	main *ast.FuncDecl
+	// pkg is the name the generated package is imported as
+	pkg string
+
+	// the statement built by stmt goes at index at of main
+	at   int
+	stmt func(b builder) ast.Stmt
+}
+
+// editMain lets edit pick the statement to add to the main function and
+// adds the import of the generated package
+func editMain(path, importPath string, edit func(m *mainEdit) error) (File, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return File{}, err
+	}
+	m := &mainEdit{main: mainFunc(file), pkg: importName(file, importPath)}
+	if m.main == nil {
+		return File{}, fmt.Errorf("%s: no main function", path)
+	}
+	if m.pkg == "" {
+		m.pkg = pathpkg.Base(importPath)
+	}
+	if err := edit(m); err != nil {
+		return File{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	// the statement goes on a line of its own below the one before it
+	body := m.main.Body
+	above := body.Lbrace
+	if m.at > 0 {
+		above = body.List[m.at-1].End()
+	}
+	fset, file, at, err := parseWithRoom(path, src, fset, width(m.stmt(0)), above)
+	if err != nil {
+		return File{}, err
+	}
+	body = mainFunc(file).Body
+	body.List = slices.Insert(body.List, m.at, m.stmt(at[0]))
+	astutil.AddImport(fset, file, importPath)
+
+	var b bytes.Buffer
+	if err := format.Node(&b, fset, file); err != nil {
+		return File{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return File{Path: path, Source: b.Bytes()}, nil
+}
+
+// insert adds the statement built by stmt at index i of main
+func (m *mainEdit) insert(i int, stmt func(b builder) ast.Stmt) {
+	m.at, m.stmt = i, stmt
+}
+
+// beforeRun inserts a statement before the call to Run when main
+// registers nothing yet, stmt gets the name of the application
+func (m *mainEdit) beforeRun(stmt func(b builder, app string) ast.Stmt) error {
+	for i, s := range m.main.Body.List {
+		var found *ast.CallExpr
+		ast.Inspect(s, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return found == nil
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Run" && len(call.Args) == 0 {
+				if _, ok := sel.X.(*ast.Ident); ok {
+					found = call
+				}
+			}
+			return found == nil
+		})
+		if found != nil {
+			app := found.Fun.(*ast.SelectorExpr).X.(*ast.Ident).Name
+			m.insert(i, func(b builder) ast.Stmt { return stmt(b, app) })
+			return nil
+		}
+	}
+	return errors.New("no app.Run() call to register before")
+}
+
+// lastCall returns the index of the last <app>.<method>(...) statement of
+// main and the name of its receiver, or -1
+func (m *mainEdit) lastCall(method string) (int, string) {
+	calls := m.calls(method)
+	if len(calls) == 0 {
+		return -1, ""
+	}
+	return m.receiver(calls[len(calls)-1])
+}
+
+// firstCall is lastCall for the first call
+func (m *mainEdit) firstCall(method string) (int, string) {
+	calls := m.calls(method)
+	if len(calls) == 0 {
+		return -1, ""
+	}
+	return m.receiver(calls[0])
+}
+
+func (m *mainEdit) receiver(i int) (int, string) {
+	return i, m.call(i).Fun.(*ast.SelectorExpr).X.(*ast.Ident).Name
+}
+
+// call returns the call of the statement at index i
+func (m *mainEdit) call(i int) *ast.CallExpr {
+	return m.main.Body.List[i].(*ast.ExprStmt).X.(*ast.CallExpr)
+}
+
+func (m *mainEdit) calls(method string) []int {
+	var calls []int
+	for i, stmt := range m.main.Body.List {
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		call, ok := expr.X.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != method {
+			continue
+		}
+		if _, ok := sel.X.(*ast.Ident); ok && (method != "RegisterController" || len(call.Args) == 2) {
+			calls = append(calls, i)
+		}
+	}
+	return calls
+}
+
+func mainFunc(file *ast.File) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "main" && fn.Recv == nil {
+			return fn
+		}
+	}
+	return nil
+}
+
+// parseWithRoom parses src again with an empty line of room spaces below
+// the line of each position in fset. Nodes placed at the returned builders
+// print on a line of their own, after the comments on the line above and
+// before the ones below, which the printer only keeps in place when room
+// is at least the width of the nodes
+func parseWithRoom(path string, src []byte, fset *token.FileSet, room int, above ...token.Pos) (*token.FileSet, *ast.File, []builder, error) {
+	tf := fset.File(above[0])
+	ends := make([]int, len(above))
+	for i, pos := range above {
+		ends[i] = tf.Size()
+		if line := tf.Line(pos); line < tf.LineCount() {
+			ends[i] = tf.Offset(tf.LineStart(line+1)) - 1
+		}
+	}
+
+	line := "\n" + strings.Repeat(" ", room)
+	order := make([]int, len(ends))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return ends[a] - ends[b] })
+	var out []byte
+	offsets := make([]int, len(ends))
+	last := 0
+	for _, i := range order {
+		out = append(out, src[last:ends[i]]...)
+		offsets[i] = len(out) + 1
+		out = append(out, line...)
+		last = ends[i]
+	}
+	out = append(out, src[last:]...)
+
+	fset = token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, out, parser.ParseComments)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	at := make([]builder, len(offsets))
+	for i, offset := range offsets {
+		at[i] = builder(file.FileStart + token.Pos(offset))
+	}
+	return fset, file, at, nil
+}
+
+// width bounds the length of node printed on one line
+func width(node ast.Node) int {
+	n := 0
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Ident:
+			n += len(node.Name) + 2
+		case *ast.BasicLit:
+			n += len(node.Value) + 2
+		}
+		return true
+	})
+	return n
+}
+
+// builder creates nodes at its position
+type builder token.Pos
+
+func (b builder) ident(name string) *ast.Ident {
+	return &ast.Ident{NamePos: token.Pos(b), Name: name}
+}
+
+func (b builder) sel(x ast.Expr, name string) *ast.SelectorExpr {
+	return &ast.SelectorExpr{X: x, Sel: b.ident(name)}
+}
+
+func (b builder) str(value string) *ast.BasicLit {
+	return &ast.BasicLit{ValuePos: token.Pos(b), Kind: token.STRING, Value: strconv.Quote(value)}
+}
+
+func (b builder) call(fun ast.Expr, args ...ast.Expr) *ast.CallExpr {
+	return &ast.CallExpr{Fun: fun, Lparen: token.Pos(b), Args: args, Rparen: token.Pos(b)}
+}
+
+// expr copies an identifier or a selector of identifiers like
+// controllers.R, it returns nil for other expressions
+func (b builder) expr(x ast.Expr) ast.Expr {
+	switch x := x.(type) {
+	case *ast.Ident:
+		return b.ident(x.Name)
+	case *ast.SelectorExpr:
+		if inner := b.expr(x.X); inner != nil {
+			return b.sel(inner, x.Sel.Name)
+		}
+	}
+	return nil
+}
+
+// importName returns the name a file imports a package under
+func importName(file *ast.File, importPath string) string {
+	for _, spec := range file.Imports {
+		if p, _ := strconv.Unquote(spec.Path.Value); p == importPath {
+			if spec.Name != nil {
+				return spec.Name.Name
+			}
+			return pathpkg.Base(importPath)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,266 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testMain = `package main
+
+import (
+	"example/controllers"
+
+	"github.com/michael-duren/mvct"
+)
+
+func main() {
+	R := controllers.R
+	app := mvct.NewApplication(mvct.Config{DefaultRoute: R.Home}, struct{}{})
+
+	// routes
+	app.RegisterController(R.Home, controllers.NewHomeController())
+
+	app.Run()
+}
+`
+
+const testRouting = `package controllers
+
+//go:generate mvct gen
+
+var R = struct {
+	Home string
+}{
+	Home: "/home",
+}
+`
+
+func newTestProject(t *testing.T) Project {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":                 "module example\n\ngo 1.25.1\n",
+		"main.go":                testMain,
+		"controllers/routing.go": testRouting,
+	} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return Project{Dir: dir}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestNewNames(t *testing.T) {
+	for name, want := range map[string]names{
+		"settings":              {Type: "SettingsController", Field: "Settings", File: "settings.go", Route: "/settings", Title: "Settings"},
+		"user-profile":          {Type: "UserProfileController", Field: "UserProfile", File: "user_profile.go", Route: "/user-profile", Title: "User profile"},
+		"UserProfileController": {Type: "UserProfileController", Field: "UserProfile", File: "user_profile.go", Route: "/user-profile", Title: "User profile"},
+		"HTTPLog":               {Type: "HttpLogController", Field: "HttpLog", File: "http_log.go", Route: "/http-log", Title: "Http log"},
+	} {
+		got, err := newNames(name, "Controller")
+		if err != nil || got != want {
+			t.Errorf("%s: expected %+v, got %+v (%v)", name, want, got, err)
+		}
+	}
+	if _, err := newNames("42", "Controller"); err == nil {
+		t.Error("expected a name that is not an identifier to fail")
+	}
+}
+
+func TestGenerateController(t *testing.T) {
+	project := newTestProject(t)
+	files, err := GenerateController(project, "user-profile")
+	if err != nil {
+		t.Fatalf("GenerateController failed: %v", err)
+	}
+	if err := Write(files); err != nil {
+		t.Fatal(err)
+	}
+
+	controller := readFile(t, filepath.Join(project.Dir, "controllers", "user_profile.go"))
+	for _, want := range []string{
+		"type UserProfileController struct{}",
+		"func (c *UserProfileController) RegisterKeyHandlers(handlers mvct.KeyHandlers)",
+		"mvct.Navigate(R.Home)",
+	} {
+		if !strings.Contains(controller, want) {
+			t.Errorf("expected the controller to contain %q, got:\n%s", want, controller)
+		}
+	}
+
+	routing := readFile(t, filepath.Join(project.Dir, "controllers", "routing.go"))
+	if !strings.Contains(routing, "\tHome        string\n\tUserProfile string\n") || !strings.Contains(routing, `UserProfile: "/user-profile",`) {
+		t.Errorf("expected the route in R, got:\n%s", routing)
+	}
+	if !strings.Contains(routing, "//go:generate mvct gen") {
+		t.Error("expected comments to be kept")
+	}
+
+	main := readFile(t, filepath.Join(project.Dir, "main.go"))
+	want := "\t// routes\n\tapp.RegisterController(R.Home, controllers.NewHomeController())\n\tapp.RegisterController(R.UserProfile, controllers.NewUserProfileController())\n"
+	if !strings.Contains(main, want) {
+		t.Errorf("expected the controller to be registered after Home, got:\n%s", main)
+	}
+
+	if _, err := GenerateController(project, "UserProfile"); err == nil {
+		t.Error("expected generating an existing controller to fail")
+	}
+}
+
+func TestGenerateMiddleware(t *testing.T) {
+	project := newTestProject(t)
+	files, err := GenerateMiddleware(project, "audit")
+	if err != nil {
+		t.Fatalf("GenerateMiddleware failed: %v", err)
+	}
+	Write(files)
+
+	if middleware := readFile(t, filepath.Join(project.Dir, "middleware", "audit.go")); !strings.Contains(middleware, "func AuditMiddleware() mvct.Middleware {") {
+		t.Errorf("expected the middleware, got:\n%s", middleware)
+	}
+	main := readFile(t, filepath.Join(project.Dir, "main.go"))
+	if !strings.Contains(main, "\t\"example/middleware\"\n") {
+		t.Errorf("expected the middleware package to be imported, got:\n%s", main)
+	}
+	if !strings.Contains(main, "app.Use(middleware.AuditMiddleware())\n\n\t// routes\n\tapp.RegisterController") {
+		t.Errorf("expected the middleware before the first registration, got:\n%s", main)
+	}
+
+	files, _ = GenerateMiddleware(project, "Session")
+	Write(files)
+	main = readFile(t, filepath.Join(project.Dir, "main.go"))
+	if !strings.Contains(main, "app.Use(middleware.AuditMiddleware())\n\tapp.Use(middleware.SessionMiddleware())\n") {
+		t.Errorf("expected the middleware after the last Use, got:\n%s", main)
+	}
+}
+
+func TestGenerateComponent(t *testing.T) {
+	project := newTestProject(t)
+	files, err := GenerateComponent(project, "status-bar")
+	if err != nil {
+		t.Fatalf("GenerateComponent failed: %v", err)
+	}
+	if len(files) != 1 || !files[0].New || filepath.Base(files[0].Path) != "status_bar.go" {
+		t.Fatalf("expected components/status_bar.go, got %+v", files)
+	}
+	if source := string(files[0].Source); !strings.Contains(source, "func NewStatusBar() *StatusBar {") {
+		t.Errorf("expected the component, got:\n%s", source)
+	}
+}
+
+func TestGenerateKeepsComments(t *testing.T) {
+	project := newTestProject(t)
+	for name, content := range map[string]string{
+		"main.go": `package main
+
+import "github.com/michael-duren/mvct"
+
+func main() {
+	app := mvct.NewApplication(mvct.Config{}, struct{}{})
+	// start the app
+	if err := app.Run(); err != nil { panic(err) }
+}
+`,
+		"controllers/routing.go": `package controllers
+
+var R = struct {
+	Home string // the start page
+	// Admin string
+}{
+	Home: "/home", /* first */
+	// Admin: "/admin",
+}
+`,
+	} {
+		if err := os.WriteFile(filepath.Join(project.Dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := GenerateController(project, "about")
+	if err != nil {
+		t.Fatalf("GenerateController failed: %v", err)
+	}
+	Write(files)
+	files, err = GenerateController(project, "settings")
+	if err != nil {
+		t.Fatalf("GenerateController failed: %v", err)
+	}
+	Write(files)
+
+	routing := readFile(t, filepath.Join(project.Dir, "controllers", "routing.go"))
+	for _, want := range []string{
+		"\tHome     string // the start page\n\tAbout    string\n\tSettings string\n\t// Admin string\n}",
+		"\tHome:     \"/home\", /* first */\n\tAbout:    \"/about\",\n\tSettings: \"/settings\",\n\t// Admin: \"/admin\",\n}",
+	} {
+		if !strings.Contains(routing, want) {
+			t.Errorf("expected the routes between the comments, got:\n%s", routing)
+		}
+	}
+
+	main := readFile(t, filepath.Join(project.Dir, "main.go"))
+	want := "\tapp := mvct.NewApplication(mvct.Config{}, struct{}{})\n" +
+		"\tapp.RegisterController(controllers.R.About, controllers.NewAboutController())\n" +
+		"\tapp.RegisterController(controllers.R.Settings, controllers.NewSettingsController())\n" +
+		"\t// start the app\n\tif err := app.Run(); err != nil {\n"
+	if !strings.Contains(main, want) {
+		t.Errorf("expected the registrations above the comment of Run, got:\n%s", main)
+	}
+
+	if err := os.WriteFile(filepath.Join(project.Dir, "main.go"), []byte(`package main
+
+import (
+	"example/controllers"
+
+	"github.com/michael-duren/mvct"
+)
+
+func main() {
+	app := mvct.NewApplication(mvct.Config{}, struct{}{})
+	app.RegisterController(controllers.R.Home, controllers.NewHomeController()) // home
+	app.RegisterController(
+		controllers.R.About,
+		controllers.NewAboutController(),
+	) /* about */
+	// more routes go here
+
+	app.Run()
+}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files, err = GenerateMiddleware(project, "audit")
+	if err != nil {
+		t.Fatalf("GenerateMiddleware failed: %v", err)
+	}
+	Write(files)
+	files, err = GenerateController(project, "profile")
+	if err != nil {
+		t.Fatalf("GenerateController failed: %v", err)
+	}
+	Write(files)
+
+	main = readFile(t, filepath.Join(project.Dir, "main.go"))
+	want = "\tapp := mvct.NewApplication(mvct.Config{}, struct{}{})\n" +
+		"\tapp.Use(middleware.AuditMiddleware())\n" +
+		"\tapp.RegisterController(controllers.R.Home, controllers.NewHomeController()) // home\n" +
+		"\tapp.RegisterController(\n\t\tcontrollers.R.About,\n\t\tcontrollers.NewAboutController(),\n\t) /* about */\n" +
+		"\tapp.RegisterController(controllers.R.Profile, controllers.NewProfileController())\n" +
+		"\t// more routes go here\n\n\tapp.Run()\n"
+	if !strings.Contains(main, want) {
+		t.Errorf("expected the additions next to the comments, got:\n%s", main)
+	}
+}
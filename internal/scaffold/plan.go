@@ -0,0 +1,205 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Conflict is what scaffolding does with an existing file that differs from
+// the rendered one
+type Conflict string
+
+const (
+	// ConflictFail stops before anything is written, the default
+	ConflictFail Conflict = ""
+	// ConflictForce overwrites the file
+	ConflictForce Conflict = "force"
+	// ConflictSkip keeps the file
+	ConflictSkip Conflict = "skip"
+	// ConflictMerge keeps the lines both share and marks the others with
+	// conflict markers
+	ConflictMerge Conflict = "merge"
+)
+
+// Action is what scaffolding does with a file
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionOverwrite Action = "overwrite"
+	ActionMerge     Action = "merge"
+	ActionSkip      Action = "skip"
+	ActionUnchanged Action = "unchanged"
+	// ActionConflict is a file that differs under ConflictFail
+	ActionConflict Action = "conflict"
+)
+
+// Change is a file of a project with what scaffolding does with it
+type Change struct {
+	File
+	Action Action
+	// Existing is the content on disk of an existing file
+	Existing []byte
+}
+
+// Diff returns the changes to an existing file in unified format
+func (c Change) Diff() string {
+	switch c.Action {
+	case ActionOverwrite, ActionMerge, ActionConflict:
+		return unifiedDiff(filepath.ToSlash(c.Path), c.Existing, c.Source)
+	}
+	return ""
+}
+
+// ConflictError lists the existing files a project would change
+type ConflictError struct {
+	Paths []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%d existing files differ from the template: %s, use --force, --skip or --merge",
+		len(e.Paths), strings.Join(e.Paths, ", "))
+}
+
+// Plan renders a project and compares its files with the ones on disk
+func Plan(config ProjectConfiguration) ([]Change, error) {
+	files, err := Render(config)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]Change, 0, len(files))
+	for _, file := range files {
+		change := Change{File: file}
+		existing, err := os.ReadFile(file.Path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			change.Action = ActionCreate
+			changes = append(changes, change)
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		change.New, change.Existing = false, existing
+		switch {
+		case bytes.Equal(existing, file.Source), filepath.Base(file.Path) == "go.mod" && tidied(existing, file.Source):
+			change.Action = ActionUnchanged
+		case config.Conflict == ConflictForce:
+			change.Action = ActionOverwrite
+		case config.Conflict == ConflictSkip:
+			change.Action = ActionSkip
+		case config.Conflict == ConflictMerge:
+			change.Action = ActionMerge
+			change.Source, _ = merge(existing, file.Source)
+		case config.Conflict == ConflictFail:
+			change.Action = ActionConflict
+		default:
+			return nil, fmt.Errorf("unknown conflict mode %q", config.Conflict)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// tidied reports whether the go.mod on disk is the rendered one after go
+// mod tidy added requirements to it, so scaffolding again leaves it alone
+func tidied(existing, rendered []byte) bool {
+	have, err := modfile.Parse("go.mod", existing, nil)
+	if err != nil || have.Module == nil {
+		return false
+	}
+	want, err := modfile.Parse("go.mod", rendered, nil)
+	if err != nil || want.Module == nil || have.Module.Mod.Path != want.Module.Mod.Path {
+		return false
+	}
+	for _, require := range want.Require {
+		if !slices.ContainsFunc(have.Require, func(r *modfile.Require) bool { return r.Mod == require.Mod }) {
+			return false
+		}
+	}
+	for _, replace := range want.Replace {
+		if !slices.ContainsFunc(have.Replace, func(r *modfile.Replace) bool { return r.Old == replace.Old && r.New == replace.New }) {
+			return false
+		}
+	}
+	return true
+}
+
+// rollback records the files and directories scaffolding touches so a
+// failed run can put them back
+type rollback struct {
+	// created are removed in reverse order
+	created []string
+	// saved is the previous content of changed files
+	saved map[string][]byte
+}
+
+// touch records path before it is written to
+func (r *rollback) touch(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		r.created = append(r.created, path)
+		return nil
+	}
+	if err != nil || info.IsDir() {
+		return err
+	}
+	if _, ok := r.saved[path]; ok {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if r.saved == nil {
+		r.saved = map[string][]byte{}
+	}
+	r.saved[path] = content
+	return nil
+}
+
+// mkdirAll creates dir and records the directories it creates
+func (r *rollback) mkdirAll(dir string) error {
+	var missing []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil || d == filepath.Dir(d) {
+			break
+		}
+		missing = append(missing, d)
+	}
+	for i := len(missing) - 1; i >= 0; i-- {
+		r.created = append(r.created, missing[i])
+	}
+	return os.MkdirAll(dir, 0755)
+}
+
+func (r *rollback) write(file File) error {
+	if err := r.mkdirAll(filepath.Dir(file.Path)); err != nil {
+		return err
+	}
+	if err := r.touch(file.Path); err != nil {
+		return err
+	}
+	return os.WriteFile(file.Path, file.Source, 0644)
+}
+
+// undo restores the changed files and removes the created ones
+func (r *rollback) undo() error {
+	var errs []error
+	for path, content := range r.saved {
+		errs = append(errs, os.WriteFile(path, content, 0644))
+	}
+	for i := len(r.created) - 1; i >= 0; i-- {
+		errs = append(errs, os.RemoveAll(r.created[i]))
+	}
+	return errors.Join(errs...)
+}
@@ -1,104 +1,464 @@
 package scaffold
 
 import (
+	"bytes"
 	"fmt"
+	"go/format"
+	"io"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
 	"text/template"
 
+	"github.com/michael-duren/mvct"
 	"github.com/michael-duren/mvct/internal/templates"
 )
 
+// mvctModule is the module path of the framework
+const mvctModule = "github.com/michael-duren/mvct"
+
 type ProjectConfiguration struct {
-	Name   string
-	Path   string
+	Name string
+	Path string
+	// Module is the module path of the project, default Name
+	Module string
 	WithDB bool
+	// Version of mvct to require, default the version of this binary. When
+	// it is unknown go mod tidy resolves the latest release
+	Version string
+	// Replace points the mvct module at a local checkout
+	Replace string
+	// GoVersion is the go directive, default templates.GoVersion
+	GoVersion string
+	// Template is the name of a built-in template or the path of a local
+	// template directory, default templates.DefaultTemplate
+	Template string
+	// Values answers the prompts of the template by name
+	Values map[string]string
+	// Prompt asks for the prompts without a value, when nil they take
+	// their default
+	Prompt func(prompt templates.Prompt) (string, error)
+	// Logging is the level main logs at, one of LogLevels. Default debug
+	Logging string
+	// Theme is the theme main starts with, "auto" picks light or dark from
+	// the terminal. Empty leaves themes out
+	Theme string
+	// Conflict is what happens to existing files that differ from the
+	// rendered ones
+	Conflict Conflict
+	// DryRun prints the files and the diffs of existing ones without
+	// writing anything
+	DryRun bool
+	// NoGit skips git init, NoTidy skips go mod tidy
+	NoGit  bool
+	NoTidy bool
+	// Output is where progress is printed, default os.Stdout
+	Output io.Writer
 }
 
-func ScaffoldProject(config ProjectConfiguration) {
-	fmt.Printf("Scaffolding project %s at %s (DB: %v)\n", config.Name, config.Path, config.WithDB)
+// LogLevels are the choices for ProjectConfiguration.Logging, off leaves
+// the logger out
+var LogLevels = []string{"debug", "info", "warn", "error", "off"}
+
+// todosRoute is added to projects with a database
+var todosRoute = templates.Route{Name: "Todos", Path: "/todos", Controller: "NewTodoController(model.Todos)"}
+
+// projectData is what the templates are rendered with
+type projectData struct {
+	ProjectConfiguration
+	Values map[string]any
+	Routes []templates.Route
+	Setup  []string
+	// LogLevel is the charmbracelet/log level, like DebugLevel
+	LogLevel string
+}
 
-	// Create directories
-	dirs := []string{
-		config.Path,
-		filepath.Join(config.Path, "cmd", "cli"),
-		filepath.Join(config.Path, "internal"),
-		filepath.Join(config.Path, "components"),
-		filepath.Join(config.Path, "controllers"),
+// ScaffoldProject writes a new project, or the missing files of an
+// existing one. Files that are already up to date are left alone, and when
+// writing or one of the commands fails everything it did is rolled back
+func ScaffoldProject(config ProjectConfiguration) error {
+	config, err := config.withDefaults()
+	if err != nil {
+		return err
 	}
+	out := config.Output
 
+	changes, err := Plan(config)
+	if err != nil {
+		return err
+	}
+	var conflicts []string
+	for _, change := range changes {
+		if change.Action == ActionConflict {
+			conflicts = append(conflicts, change.Path)
+		}
+	}
+
+	if config.DryRun {
+		fmt.Fprintf(out, "Dry run of project %s at %s (template: %s, DB: %v)\n", config.Name, config.Path, config.Template, config.WithDB)
+		for _, change := range changes {
+			fmt.Fprintf(out, "%-9s %s\n", change.Action, change.Path)
+			if diff := change.Diff(); diff != "" {
+				fmt.Fprint(out, diff)
+			}
+		}
+		if len(conflicts) > 0 {
+			return &ConflictError{Paths: conflicts}
+		}
+		if !config.NoGit {
+			fmt.Fprintln(out, "run       git init")
+		}
+		if !config.NoTidy {
+			fmt.Fprintln(out, "run       go mod tidy")
+		}
+		return nil
+	}
+	if len(conflicts) > 0 {
+		return &ConflictError{Paths: conflicts}
+	}
+
+	fmt.Fprintf(out, "Scaffolding project %s at %s (template: %s, DB: %v)\n", config.Name, config.Path, config.Template, config.WithDB)
+	var r rollback
+	merged, err := writeChanges(&r, config, changes)
+	if err != nil {
+		if undoErr := r.undo(); undoErr != nil {
+			return fmt.Errorf("%w, rolling back failed: %w", err, undoErr)
+		}
+		return fmt.Errorf("%w, the project was rolled back", err)
+	}
+
+	fmt.Fprintln(out, "Project scaffolded successfully!")
+	if len(merged) > 0 {
+		fmt.Fprintf(out, "Resolve the conflict markers in %s\n", strings.Join(merged, ", "))
+		if !config.NoTidy {
+			fmt.Fprintln(out, "then run go mod tidy")
+		}
+	}
 	if config.WithDB {
-		dirs = append(dirs, filepath.Join(config.Path, "db"))
+		fmt.Fprintln(out, "Open the todo list with ctrl+o, run make sqlc after changing db/queries.sql")
+	}
+	fmt.Fprintf(out, "Run your app: cd %s && go run ./cmd/cli\n", config.Path)
+	return nil
+}
+
+// writeChanges writes the changes and runs git init and go mod tidy, recording
+// what they touch. It returns the files left with conflict markers
+func writeChanges(r *rollback, config ProjectConfiguration, changes []Change) ([]string, error) {
+	out := config.Output
+	if err := r.mkdirAll(config.Path); err != nil {
+		return nil, err
 	}
 
-	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			fmt.Printf("Error creating directory %s: %v\n", dir, err)
-			return
+	var merged []string
+	for _, change := range changes {
+		switch change.Action {
+		case ActionSkip, ActionUnchanged:
+			fmt.Fprintf(out, "%-9s %s\n", change.Action, change.Path)
+			continue
+		case ActionMerge:
+			if bytes.Contains(change.Source, []byte("<<<<<<< existing\n")) {
+				merged = append(merged, change.Path)
+			}
+		}
+		if err := r.write(change.File); err != nil {
+			return nil, err
 		}
+		fmt.Fprintf(out, "%-9s %s\n", change.Action, change.Path)
 	}
 
-	// Generate go.mod
-	createFile(filepath.Join(config.Path, "go.mod"), templates.GoModTemplate, config)
+	if !config.NoGit {
+		git := filepath.Join(config.Path, ".git")
+		if _, err := os.Stat(git); err == nil {
+			fmt.Fprintln(out, "git repository exists, skipping git init")
+		} else {
+			if err := r.touch(git); err != nil {
+				return nil, err
+			}
+			if err := runCmd(out, config.Path, "git", "init"); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	// Generate main.go
-	createFile(filepath.Join(config.Path, "cmd", "cli", "main.go"), templates.MainGoTemplate, config)
+	// a project with conflict markers does not parse, tidy it once they
+	// are resolved
+	if !config.NoTidy && len(merged) == 0 {
+		for _, name := range []string{"go.mod", "go.sum"} {
+			if err := r.touch(filepath.Join(config.Path, name)); err != nil {
+				return nil, err
+			}
+		}
+		if err := runCmd(out, config.Path, "go", "mod", "tidy"); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
 
-	// Generate components
-	createFile(filepath.Join(config.Path, "components", "layout.go"), templates.LayoutTemplate, config)
+// mvctVersion returns the released version of mvct this binary was built
+// from, or "" for development builds
+func mvctVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
 
-	// Generate controllers
-	createFile(filepath.Join(config.Path, "controllers", "home.go"), templates.HomeControllerTemplate, config)
-	createFile(filepath.Join(config.Path, "controllers", "routing.go"), templates.RoutingTemplate, config)
+	version := info.Main.Version
+	if info.Main.Path != mvctModule {
+		version = ""
+		for _, dep := range info.Deps {
+			if dep.Path == mvctModule {
+				version = dep.Version
+			}
+		}
+	}
+	// (devel) and versions stamped from a modified checkout cannot be
+	// downloaded
+	if !strings.HasPrefix(version, "v") || strings.Contains(version, "+") {
+		return ""
+	}
+	return version
+}
 
-	// DB specific files
+func (c ProjectConfiguration) withDefaults() (ProjectConfiguration, error) {
+	if c.Module == "" {
+		c.Module = c.Name
+	}
+	if c.Template == "" {
+		c.Template = templates.DefaultTemplate
+	}
+	if c.Logging == "" {
+		c.Logging = "debug"
+	}
+	if c.Output == nil {
+		c.Output = os.Stdout
+	}
+	if c.Version == "" {
+		c.Version = mvctVersion()
+	}
+	if c.GoVersion == "" {
+		c.GoVersion = templates.GoVersion
+	}
+	if c.Replace != "" {
+		replace, err := filepath.Abs(c.Replace)
+		if err != nil {
+			return c, fmt.Errorf("failed to resolve %s: %w", c.Replace, err)
+		}
+		c.Replace = filepath.ToSlash(replace)
+		// a replaced module still needs a requirement, any version will do
+		if c.Version == "" {
+			c.Version = "v0.0.0"
+		}
+	}
+	return c, nil
+}
+
+// Render returns the files of a new project without writing them. The base
+// files are rendered first, files of the template replace them
+func Render(config ProjectConfiguration) ([]File, error) {
+	config, err := config.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := templates.Load(config.Template)
+	if err != nil {
+		return nil, err
+	}
+	values, err := promptValues(tmpl.Prompts, config)
+	if err != nil {
+		return nil, err
+	}
+
+	data := projectData{ProjectConfiguration: config, Values: values, Setup: tmpl.Setup}
+	switch {
+	case config.Logging == "off":
+	case slices.Contains(LogLevels, config.Logging):
+		data.LogLevel = strings.ToUpper(config.Logging[:1]) + config.Logging[1:] + "Level"
+	default:
+		return nil, fmt.Errorf("unknown log level %q, use one of %s", config.Logging, strings.Join(LogLevels, ", "))
+	}
+	if config.Theme != "" && config.Theme != "auto" && !slices.Contains(mvct.ThemeNames(), config.Theme) {
+		return nil, fmt.Errorf("unknown theme %q, use auto or one of %s", config.Theme, strings.Join(mvct.ThemeNames(), ", "))
+	}
+	for _, route := range tmpl.Routes {
+		ok, err := condition(route.If, data)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", route.Name, err)
+		}
+		if ok {
+			data.Routes = append(data.Routes, route)
+		}
+	}
+	if len(data.Routes) == 0 {
+		return nil, fmt.Errorf("template %s has no route for these answers", tmpl.Name)
+	}
 	if config.WithDB {
-		createFile(filepath.Join(config.Path, "sqlc.yaml"), templates.SqlcYamlTemplate, config)
-		createFile(filepath.Join(config.Path, "db", "schema.sql"), templates.SchemaSqlTemplate, config)
-		createFile(filepath.Join(config.Path, "db", "queries.sql"), templates.QueriesSqlTemplate, config)
-		createFile(filepath.Join(config.Path, "Makefile"), templates.MakefileTemplate, config)
+		if slices.ContainsFunc(data.Routes, func(route templates.Route) bool { return route.Name == todosRoute.Name }) {
+			return nil, fmt.Errorf("template %s has a %s route, it can't be used with --db", tmpl.Name, todosRoute.Name)
+		}
+		data.Routes = append(data.Routes, todosRoute)
 	}
 
-	// Initialize git
-	runCmd(config.Path, "git", "init")
+	var files []File
+	add := func(name string, source []byte) {
+		path := filepath.Join(config.Path, filepath.FromSlash(name))
+		for i := range files {
+			if files[i].Path == path {
+				files[i].Source = source
+				return
+			}
+		}
+		files = append(files, File{Path: path, Source: source, New: true})
+	}
 
-	// Tidy go mod
-	// We need to run this inside the project directory, ensuring the go.mod exists first
-	runCmd(config.Path, "go", "mod", "tidy")
+	base := []struct{ name, tmpl string }{
+		{"go.mod", templates.GoModTemplate},
+		{"cmd/cli/main.go", templates.MainGoTemplate},
+		{"components/layout.go", templates.LayoutTemplate},
+		{"controllers/routing.go", templates.RoutingTemplate},
+	}
+	for _, file := range base {
+		source, err := render(file.name, file.tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		add(file.name, source)
+	}
 
-	fmt.Println("Project scaffolded successfully!")
 	if config.WithDB {
-		fmt.Println("To generate sqlc code, run: make sqlc")
+		if err := renderFS(templates.Database(), nil, data, add); err != nil {
+			return nil, fmt.Errorf("database files: %w", err)
+		}
 	}
-	fmt.Printf("Run your app: cd %s && go run cmd/cli/main.go\n", config.Path)
+
+	rules := map[string]string{}
+	for _, rule := range tmpl.Files {
+		rules[rule.Path] = rule.If
+	}
+	if err := renderFS(tmpl.FS, rules, data, add); err != nil {
+		return nil, fmt.Errorf("template %s: %w", tmpl.Name, err)
+	}
+	return files, nil
 }
 
-func createFile(path string, tmpl string, data any) {
-	t, err := template.New("file").Parse(tmpl)
-	if err != nil {
-		fmt.Printf("Error parsing template for %s: %v\n", path, err)
-		return
+// renderFS adds the files of fsys whose condition in rules holds. Files
+// ending in .tmpl are rendered without the suffix, others are copied
+func renderFS(fsys fs.FS, rules map[string]string, data projectData, add func(name string, source []byte)) error {
+	return fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || name == templates.ManifestFile {
+			return err
+		}
+		ok, err := condition(rules[name], data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if !ok {
+			return nil
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		if !strings.HasSuffix(name, ".tmpl") {
+			add(name, content)
+			return nil
+		}
+		name = strings.TrimSuffix(name, ".tmpl")
+		source, err := render(name, string(content), data)
+		if err != nil {
+			return err
+		}
+		add(name, source)
+		return nil
+	})
+}
+
+// promptValues resolves the prompts of a template from the given values,
+// by asking, or from their defaults
+func promptValues(prompts []templates.Prompt, config ProjectConfiguration) (map[string]any, error) {
+	values := map[string]any{}
+	for _, prompt := range prompts {
+		value, ok := config.Values[prompt.Name]
+		if !ok && config.Prompt != nil {
+			answer, err := config.Prompt(prompt)
+			if err != nil {
+				return nil, err
+			}
+			value = answer
+		}
+		if value == "" {
+			value = prompt.Default
+		}
+
+		if prompt.Type != "bool" {
+			values[prompt.Name] = value
+			continue
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false, got %q", prompt.Name, value)
+		}
+		values[prompt.Name] = b
 	}
 
-	f, err := os.Create(path)
+	for name := range config.Values {
+		if _, ok := values[name]; !ok {
+			return nil, fmt.Errorf("template %s has no prompt %s", config.Template, name)
+		}
+	}
+	return values, nil
+}
+
+// condition evaluates a template condition like .Values.settings, an empty
+// condition holds
+func condition(expr string, data any) (bool, error) {
+	if expr == "" {
+		return true, nil
+	}
+	t, err := template.New("if").Parse("{{if " + expr + "}}true{{end}}")
 	if err != nil {
-		fmt.Printf("Error creating file %s: %v\n", path, err)
-		return
+		return false, err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return false, err
 	}
-	defer f.Close()
+	return b.String() == "true", nil
+}
 
-	if err := t.Execute(f, data); err != nil {
-		fmt.Printf("Error executing template for %s: %v\n", path, err)
+// render executes a template, Go files are formatted
+func render(name, tmpl string, data any) ([]byte, error) {
+	t, err := template.New(name).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template for %s: %w", name, err)
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	if filepath.Ext(name) != ".go" {
+		return b.Bytes(), nil
+	}
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", name, err)
 	}
+	return source, nil
 }
 
-func runCmd(dir string, name string, args ...string) {
+func runCmd(out io.Writer, dir string, name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	cmd.Dir = dir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = out
+	cmd.Stderr = out
 	if err := cmd.Run(); err != nil {
-		fmt.Printf("Error running command %s %v: %v\n", name, args, err)
+		return fmt.Errorf("%s %s failed: %w", name, strings.Join(args, " "), err)
 	}
+	return nil
 }
@@ -0,0 +1,312 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/michael-duren/mvct/internal/templates"
+)
+
+func TestScaffoldProjectBuilds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a project")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configs := []ProjectConfiguration{
+		{Template: templates.DefaultTemplate, Logging: "off", Theme: "auto"},
+		{Template: "dashboard", WithDB: true},
+	}
+	for _, tmpl := range templates.Builtin() {
+		configs = append(configs, ProjectConfiguration{Template: tmpl.Name})
+	}
+
+	for _, config := range configs {
+		name := config.Template + "/" + config.Logging + config.Theme
+		if config.WithDB {
+			name += "db"
+		}
+		t.Run(name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "myapp")
+			config.Name, config.Path, config.Replace, config.Output = "myapp", dir, root, io.Discard
+			if err := ScaffoldProject(config); err != nil {
+				t.Fatalf("ScaffoldProject failed: %v", err)
+			}
+			// go mod tidy changed go.mod, scaffolding again still has nothing to do
+			config.NoGit = true
+			if err := ScaffoldProject(config); err != nil {
+				t.Fatalf("expected scaffolding again to succeed: %v", err)
+			}
+
+			goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+			if err != nil {
+				t.Fatalf("expected a go.mod: %v", err)
+			}
+			if !strings.Contains(string(goMod), "replace github.com/michael-duren/mvct => "+filepath.ToSlash(root)) {
+				t.Errorf("expected a replace of the local checkout, got:\n%s", goMod)
+			}
+
+			// generated code has to build in a scaffolded project too
+			project := Project{Dir: dir}
+			for _, generate := range []func(Project, string) ([]File, error){GenerateController, GenerateMiddleware, GenerateComponent} {
+				files, err := generate(project, "extra")
+				if err != nil {
+					t.Fatalf("generator failed: %v", err)
+				}
+				if err := Write(files); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// the DB scaffold tests its repository against SQLite
+			for _, args := range [][]string{{"build", "./..."}, {"vet", "./..."}, {"test", "./..."}} {
+				cmd := exec.Command("go", args...)
+				cmd.Dir = dir
+				if out, err := cmd.CombinedOutput(); err != nil {
+					t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, out)
+				}
+			}
+		})
+	}
+}
+
+func TestScaffoldProjectDryRun(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "myapp")
+	var out bytes.Buffer
+	err := ScaffoldProject(ProjectConfiguration{Name: "myapp", Path: dir, DryRun: true, Output: &out})
+	if err != nil {
+		t.Fatalf("ScaffoldProject failed: %v", err)
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected a dry run to write nothing")
+	}
+	for _, want := range []string{"create    " + filepath.Join(dir, "go.mod"), "run       git init", "run       go mod tidy"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestScaffoldProjectExisting(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "myapp")
+	config := ProjectConfiguration{Name: "myapp", Path: dir, NoGit: true, NoTidy: true, Output: io.Discard}
+	if err := ScaffoldProject(config); err != nil {
+		t.Fatalf("ScaffoldProject failed: %v", err)
+	}
+	if err := ScaffoldProject(config); err != nil {
+		t.Fatalf("expected scaffolding an up to date project to succeed: %v", err)
+	}
+
+	layout := filepath.Join(dir, "components", "layout.go")
+	rendered := readFile(t, layout)
+	edited := strings.Replace(rendered, "package components\n", "package components\n// edited\n", 1)
+	os.WriteFile(layout, []byte(edited), 0644)
+	os.Remove(filepath.Join(dir, "go.mod"))
+
+	var out bytes.Buffer
+	config.DryRun, config.Output = true, &out
+	var conflict *ConflictError
+	if err := ScaffoldProject(config); !errors.As(err, &conflict) || len(conflict.Paths) != 1 || conflict.Paths[0] != layout {
+		t.Fatalf("expected a conflict on layout.go, got %v", err)
+	}
+	if !strings.Contains(out.String(), "+++ b/"+filepath.ToSlash(layout)+"\n") || !strings.Contains(out.String(), "\n-// edited\n") {
+		t.Errorf("expected the diff of layout.go, got:\n%s", out.String())
+	}
+	config.DryRun, config.Output = false, io.Discard
+	if err := ScaffoldProject(config); err == nil {
+		t.Fatal("expected a conflict to fail")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		t.Error("expected nothing to be written when a file conflicts")
+	}
+
+	for _, tt := range []struct {
+		conflict Conflict
+		want     string
+	}{
+		{ConflictSkip, edited},
+		{ConflictMerge, strings.Replace(rendered, "package components\n", "package components\n<<<<<<< existing\n// edited\n=======\n>>>>>>> template\n", 1)},
+		{ConflictForce, rendered},
+	} {
+		config.Conflict = tt.conflict
+		if err := ScaffoldProject(config); err != nil {
+			t.Fatalf("%s: ScaffoldProject failed: %v", tt.conflict, err)
+		}
+		if got := readFile(t, layout); got != tt.want {
+			t.Errorf("%s: expected layout.go\n%s\ngot\n%s", tt.conflict, tt.want, got)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+			t.Errorf("%s: expected the missing go.mod to be written", tt.conflict)
+		}
+		os.WriteFile(layout, []byte(edited), 0644)
+	}
+}
+
+func TestScaffoldProjectRollback(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+	// go mod tidy fails on a replace without a module
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	dir := filepath.Join(t.TempDir(), "myapp")
+	config := ProjectConfiguration{Name: "myapp", Path: dir, Replace: missing, NoGit: true, Output: io.Discard}
+	if err := ScaffoldProject(config); err == nil {
+		t.Fatal("expected go mod tidy to fail")
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected the new project directory to be removed")
+	}
+
+	existing := t.TempDir()
+	os.WriteFile(filepath.Join(existing, "notes.txt"), []byte("keep"), 0644)
+	os.WriteFile(filepath.Join(existing, "go.mod"), []byte("module other\n"), 0644)
+	config.Path, config.Conflict = existing, ConflictForce
+	if err := ScaffoldProject(config); err == nil {
+		t.Fatal("expected go mod tidy to fail")
+	}
+	entries, _ := os.ReadDir(existing)
+	if len(entries) != 2 || readFile(t, filepath.Join(existing, "go.mod")) != "module other\n" {
+		t.Errorf("expected the directory as it was, got %v", entries)
+	}
+}
+
+func TestScaffoldProjectGitError(t *testing.T) {
+	dir := t.TempDir()
+	// stat fails on a symlink loop with an error other than not exist
+	if err := os.Symlink(".git", filepath.Join(dir, ".git")); err != nil {
+		t.Skip("symlinks are not supported")
+	}
+	config := ProjectConfiguration{Name: "myapp", Path: dir, NoTidy: true, Output: io.Discard}
+	if err := ScaffoldProject(config); err == nil || !strings.Contains(err.Error(), ".git") {
+		t.Fatalf("expected the .git error, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("expected the project to be rolled back, got %v", entries)
+	}
+}
+
+func TestRenderLocalTemplate(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"template.json": `{
+			"prompts": [
+				{"name": "greeting", "message": "Greeting", "default": "Hello"},
+				{"name": "about", "message": "Add an about page", "default": "false", "type": "bool"}
+			],
+			"routes": [
+				{"name": "Home", "path": "/home", "controller": "NewHomeController()"},
+				{"name": "About", "path": "/about", "controller": "NewAboutController()", "if": ".Values.about"}
+			],
+			"files": [{"path": "controllers/about.go.tmpl", "if": ".Values.about"}]
+		}`,
+		"controllers/home.go.tmpl":  "package controllers\n\nconst greeting = \"{{.Values.greeting}}, {{.Name}}\"\n",
+		"controllers/about.go.tmpl": "package controllers\n",
+		"components/layout.go":      "package components\n\n// custom layout\n",
+		"README.md":                 "{{.Name}} stays as it is\n",
+	} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+
+	config := ProjectConfiguration{Name: "myapp", Module: "myapp", Path: "out", Template: dir, Values: map[string]string{"greeting": "Hi"}}
+	files, err := Render(config)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	byName := map[string]string{}
+	for _, file := range files {
+		rel, _ := filepath.Rel("out", file.Path)
+		byName[filepath.ToSlash(rel)] = string(file.Source)
+	}
+
+	if !strings.Contains(byName["controllers/home.go"], `const greeting = "Hi, myapp"`) {
+		t.Errorf("expected the prompt value in home.go, got %q", byName["controllers/home.go"])
+	}
+	if _, ok := byName["controllers/about.go"]; ok {
+		t.Error("expected the conditional file to be skipped")
+	}
+	if strings.Contains(byName["controllers/routing.go"], "About") || strings.Contains(byName["cmd/cli/main.go"], "About") {
+		t.Error("expected the conditional route to be skipped")
+	}
+	if byName["components/layout.go"] != "package components\n\n// custom layout\n" {
+		t.Errorf("expected the template to replace the base layout, got %q", byName["components/layout.go"])
+	}
+	if byName["README.md"] != "{{.Name}} stays as it is\n" {
+		t.Errorf("expected files without .tmpl to be copied, got %q", byName["README.md"])
+	}
+
+	config.Values = map[string]string{"about": "yes"}
+	if _, err := Render(config); err == nil {
+		t.Error("expected an invalid bool to fail")
+	}
+	config.Values = map[string]string{"about": "true"}
+	files, _ = Render(config)
+	var about bool
+	for _, file := range files {
+		about = about || filepath.Base(file.Path) == "about.go"
+	}
+	if !about {
+		t.Error("expected the conditional file when its prompt is true")
+	}
+	config.Values = map[string]string{"colour": "red"}
+	if _, err := Render(config); err == nil {
+		t.Error("expected a value for an unknown prompt to fail")
+	}
+}
+
+func TestRenderOptions(t *testing.T) {
+	main := func(config ProjectConfiguration) string {
+		t.Helper()
+		config.Name, config.Path = "myapp", "out"
+		files, err := Render(config)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		return string(files[1].Source)
+	}
+
+	if source := main(ProjectConfiguration{}); !strings.Contains(source, "log.DebugLevel") || strings.Contains(source, "UseTheme") {
+		t.Errorf("expected debug logging and no theme by default, got:\n%s", source)
+	}
+	source := main(ProjectConfiguration{Logging: "warn", Theme: "dark"})
+	if !strings.Contains(source, "log.WarnLevel") || !strings.Contains(source, `Name: "dark",`) || !strings.Contains(source, "ThemeSwitchHandler") {
+		t.Errorf("expected warn logging and the dark theme, got:\n%s", source)
+	}
+	if source := main(ProjectConfiguration{Logging: "off", Theme: "auto"}); strings.Contains(source, "UseLogger") || strings.Contains(source, "Name:") {
+		t.Errorf("expected no logger and an automatic theme, got:\n%s", source)
+	}
+
+	for _, config := range []ProjectConfiguration{{Logging: "verbose"}, {Theme: "neon"}} {
+		config.Name = "myapp"
+		if _, err := Render(config); err == nil {
+			t.Errorf("expected %+v to fail", config)
+		}
+	}
+}
+
+func TestGoVersionMatchesModule(t *testing.T) {
+	goMod, err := os.ReadFile(filepath.Join("..", "..", "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	match := regexp.MustCompile(`(?m)^go (\S+)$`).FindSubmatch(goMod)
+	if match == nil || string(match[1]) != templates.GoVersion {
+		t.Errorf("expected templates.GoVersion to match the go directive of mvct, got %s and %s", templates.GoVersion, match)
+	}
+}
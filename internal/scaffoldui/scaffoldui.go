@@ -0,0 +1,384 @@
+// Package scaffoldui is the interactive mvct scaffold, a wizard built with
+// mvct itself that asks for the project configuration, previews the files
+// and confirms before anything is written
+package scaffoldui
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-duren/mvct"
+	"github.com/michael-duren/mvct/components"
+	"github.com/michael-duren/mvct/forms"
+	"github.com/michael-duren/mvct/internal/scaffold"
+	"github.com/michael-duren/mvct/internal/templates"
+)
+
+const (
+	wizardRoute  = "/new"
+	previewRoute = "/preview"
+	// promptPrefix marks the answers to template prompts in the wizard state
+	promptPrefix = "prompt."
+	// noTheme is the theme choice leaving themes out
+	noTheme = "none"
+	// stepKey holds the index of the last step shown in the wizard state
+	stepKey = "step"
+)
+
+// Run asks for the configuration of a new project, starting from config.
+// ok is false when the user quits without confirming
+func Run(config scaffold.ProjectConfiguration) (result scaffold.ProjectConfiguration, ok bool, err error) {
+	// the app logs to stderr unless a logger is configured, which would
+	// draw over the form
+	logger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer slog.SetDefault(logger)
+
+	app := newApp(config, func(c scaffold.ProjectConfiguration) {
+		result, ok = c, true
+	})
+	if err := app.Run(); err != nil {
+		return result, false, err
+	}
+	return result, ok, nil
+}
+
+// newApp builds the scaffold wizard, confirm is called with the
+// configuration when the user confirms the preview
+func newApp(config scaffold.ProjectConfiguration, confirm func(scaffold.ProjectConfiguration)) *mvct.Application[struct{}] {
+	setup := mvct.NewWizard(wizardRoute)
+	setup.Step(mvct.WizardStep{
+		Name:       "project",
+		Title:      "Project",
+		Controller: newFormStep(setup, 0, projectFields(config)),
+	}).Step(mvct.WizardStep{
+		Name:       "template",
+		Title:      "Template",
+		Controller: newFormStep(setup, 1, templateFields(config)),
+	}).Step(mvct.WizardStep{
+		Name:       "prompts",
+		Title:      "Template options",
+		Controller: newFormStep(setup, 2, promptFields(config)),
+		Skippable:  true,
+	}).Step(mvct.WizardStep{
+		Name:       "options",
+		Title:      "Logging and theme",
+		Controller: newFormStep(setup, 3, optionFields(config)),
+	}).OnComplete(previewRoute)
+
+	app := mvct.NewApplication(mvct.Config{DefaultRoute: setup.StepRoute(0)}, struct{}{})
+	app.UseGlobalHandler(mvct.QuitHandler("ctrl+c"))
+	app.RegisterWizard(setup)
+	app.RegisterController(previewRoute, &previewController{
+		wizard:  setup,
+		base:    config,
+		confirm: confirm,
+	})
+	return app
+}
+
+// answer returns the value of a field from the wizard state, or the
+// fallback before the step was answered
+func answer(state *mvct.WizardState, name string, fallback any) any {
+	if value, ok := state.Get(name); ok {
+		return value
+	}
+	return fallback
+}
+
+func projectFields(config scaffold.ProjectConfiguration) func(*mvct.WizardState) []forms.Field {
+	return func(state *mvct.WizardState) []forms.Field {
+		return []forms.Field{
+			forms.Text("name", "Project directory", forms.Required(),
+				forms.Default(answer(state, "name", config.Name)), forms.Placeholder("my-app")),
+			forms.Text("module", "Module path",
+				forms.Default(answer(state, "module", config.Module)),
+				forms.Description("Leave empty to use the directory name")),
+		}
+	}
+}
+
+func templateFields(config scaffold.ProjectConfiguration) func(*mvct.WizardState) []forms.Field {
+	var names []string
+	for _, t := range templates.Builtin() {
+		names = append(names, t.Name)
+	}
+	// a local template directory given with --template is offered first
+	if config.Template != "" && !slices.Contains(names, config.Template) {
+		names = append([]string{config.Template}, names...)
+	}
+	initial := config.Template
+	if initial == "" {
+		initial = templates.DefaultTemplate
+	}
+
+	return func(state *mvct.WizardState) []forms.Field {
+		return []forms.Field{
+			forms.Select("template", "Template", names, forms.Default(answer(state, "template", initial))),
+			forms.Checkbox("db", "SQLite database", forms.Default(answer(state, "db", config.WithDB))),
+		}
+	}
+}
+
+// promptFields asks the prompts of the chosen template, the step is
+// skipped when it has none
+func promptFields(config scaffold.ProjectConfiguration) func(*mvct.WizardState) []forms.Field {
+	return func(state *mvct.WizardState) []forms.Field {
+		name, _ := state.Get("template")
+		tmpl, err := templates.Load(fmt.Sprint(name))
+		if err != nil {
+			return nil
+		}
+
+		var fields []forms.Field
+		for _, prompt := range tmpl.Prompts {
+			key := promptPrefix + prompt.Name
+			initial := prompt.Default
+			if value, ok := config.Values[prompt.Name]; ok {
+				initial = value
+			}
+			if prompt.Type == "bool" {
+				fields = append(fields, forms.Checkbox(key, prompt.Message, forms.Default(answer(state, key, initial == "true"))))
+			} else {
+				fields = append(fields, forms.Text(key, prompt.Message, forms.Default(answer(state, key, initial))))
+			}
+		}
+		return fields
+	}
+}
+
+func optionFields(config scaffold.ProjectConfiguration) func(*mvct.WizardState) []forms.Field {
+	logging := config.Logging
+	if logging == "" {
+		logging = scaffold.LogLevels[0]
+	}
+	themes := append([]string{noTheme, "auto"}, mvct.ThemeNames()...)
+	theme := config.Theme
+	if theme == "" {
+		theme = noTheme
+	}
+
+	return func(state *mvct.WizardState) []forms.Field {
+		return []forms.Field{
+			forms.Select("logging", "Log level", scaffold.LogLevels, forms.Default(answer(state, "logging", logging)),
+				forms.Description("off leaves the logger out")),
+			forms.Select("theme", "Theme", themes, forms.Default(answer(state, "theme", theme)),
+				forms.Description("auto picks light or dark from the terminal")),
+		}
+	}
+}
+
+// configFrom builds the project configuration from the wizard answers
+func configFrom(base scaffold.ProjectConfiguration, answers map[string]any) scaffold.ProjectConfiguration {
+	config := base
+	config.Name, _ = answers["name"].(string)
+	config.Path = config.Name
+	config.Module, _ = answers["module"].(string)
+	config.Template, _ = answers["template"].(string)
+	config.WithDB, _ = answers["db"].(bool)
+	config.Logging, _ = answers["logging"].(string)
+	config.Theme, _ = answers["theme"].(string)
+	if config.Theme == noTheme {
+		config.Theme = ""
+	}
+
+	// answers to the prompts of a template chosen before are dropped
+	config.Values = map[string]string{}
+	if tmpl, err := templates.Load(config.Template); err == nil {
+		for _, prompt := range tmpl.Prompts {
+			if value, ok := answers[promptPrefix+prompt.Name]; ok {
+				config.Values[prompt.Name] = fmt.Sprint(value)
+			}
+		}
+	}
+	// the answers are final, nothing is asked on the terminal
+	config.Prompt = nil
+	return config
+}
+
+// stepSubmittedMsg is sent by the form of a step when it is submitted
+type stepSubmittedMsg struct {
+	values forms.Values
+}
+
+// stepCancelledMsg is sent by the form of a step when esc is pressed
+type stepCancelledMsg struct{}
+
+// formStep is a wizard step asking the fields of a form. The form is built
+// on every activation, so it can depend on earlier answers
+type formStep struct {
+	wizard *mvct.Wizard
+	index  int
+	fields func(state *mvct.WizardState) []forms.Field
+	form   *forms.Form
+}
+
+func newFormStep(wizard *mvct.Wizard, index int, fields func(*mvct.WizardState) []forms.Field) *formStep {
+	return &formStep{wizard: wizard, index: index, fields: fields}
+}
+
+func (s *formStep) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	state := s.wizard.State()
+	last, _ := state.Get(stepKey)
+	state.Set(stepKey, s.index)
+
+	fields := s.fields(state)
+	if len(fields) == 0 {
+		// pass an empty step in the direction the user is going
+		s.form = nil
+		if last, ok := last.(int); ok && last > s.index {
+			return mvct.WizardBack()
+		}
+		return mvct.WizardSkip()
+	}
+
+	s.form = forms.New("step", fields...).OnSubmit(func(v forms.Values) tea.Msg {
+		return stepSubmittedMsg{values: v}
+	}).OnCancel(func() tea.Msg {
+		return stepCancelledMsg{}
+	})
+	return s.form.Init(handlers)
+}
+
+func (s *formStep) View() string {
+	if s.form == nil {
+		return ""
+	}
+	back := "esc: back"
+	if s.index == 0 {
+		back = "esc: quit"
+	}
+	return "New mvct project\n\n" + s.wizard.RenderProgress() + "\n\n" + s.form.View() +
+		"\n\ntab: next field • enter: continue • " + back
+}
+
+func (s *formStep) OnKeyMsg(msg mvct.KeyMsg) mvct.Cmd {
+	if s.form == nil {
+		return nil
+	}
+	return s.form.OnKeyMsg(msg)
+}
+
+func (s *formStep) OnValidationMsg(msg forms.ValidationMsg) mvct.Cmd {
+	if s.form == nil {
+		return nil
+	}
+	return s.form.OnValidationMsg(msg)
+}
+
+func (s *formStep) OnStepSubmittedMsg(msg stepSubmittedMsg) mvct.Cmd {
+	for name, value := range msg.values {
+		s.wizard.State().Set(name, value)
+	}
+	return mvct.WizardNext()
+}
+
+func (s *formStep) OnStepCancelledMsg(msg stepCancelledMsg) mvct.Cmd {
+	if s.index == 0 {
+		return mvct.Quit()
+	}
+	return mvct.WizardBack()
+}
+
+// previewLines is the number of lines shown of the selected file
+const previewLines = 14
+
+// previewController lists the files of the project and shows the selected
+// one, or its diff when it exists, y writes them
+type previewController struct {
+	wizard  *mvct.Wizard
+	base    scaffold.ProjectConfiguration
+	confirm func(scaffold.ProjectConfiguration)
+
+	answers map[string]any
+	config  scaffold.ProjectConfiguration
+	files   *components.List[scaffold.Change]
+	err     error
+}
+
+func (c *previewController) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	handlers["y"] = c.onConfirm
+	handlers["b"] = c.onBack
+	handlers["esc"] = c.onBack
+	handlers["q"] = func(msg mvct.KeyMsg) mvct.Cmd { return mvct.Quit() }
+	c.files = components.NewList[scaffold.Change](nil, func(change scaffold.Change) string {
+		rel, err := filepath.Rel(c.config.Path, change.Path)
+		if err != nil {
+			rel = change.Path
+		}
+		return fmt.Sprintf("%-9s %s", change.Action, filepath.ToSlash(rel))
+	})
+	c.files.Height = 10
+	return c.files.Init(handlers)
+}
+
+// OnWizardCompletedMsg renders the project from the answers
+func (c *previewController) OnWizardCompletedMsg(msg mvct.WizardCompletedMsg) mvct.Cmd {
+	c.answers = msg.State
+	c.config = configFrom(c.base, msg.State)
+	changes, err := scaffold.Plan(c.config)
+	c.err = err
+	c.files.SetItems(changes)
+	return nil
+}
+
+func (c *previewController) View() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("New mvct project %s (template %s)\n\n", c.config.Name, c.config.Template))
+
+	if c.err != nil {
+		b.WriteString(fmt.Sprintf("The project can't be rendered: %v\n\nb: back • q: quit", c.err))
+		return b.String()
+	}
+	if conflicts := c.conflicts(); conflicts > 0 {
+		b.WriteString(fmt.Sprintf("Warning: %d existing files differ, scaffolding stops unless --force, --skip or --merge is passed\n\n", conflicts))
+	}
+
+	b.WriteString(fmt.Sprintf("%d files\n", len(c.files.Items())))
+	b.WriteString(c.files.View())
+	if change, ok := c.files.Selected(); ok {
+		content := string(change.Source)
+		if diff := change.Diff(); diff != "" {
+			content = diff
+		}
+		lines := strings.Split(content, "\n")
+		if len(lines) > previewLines {
+			lines = append(lines[:previewLines], "…")
+		}
+		b.WriteString("\n\n" + strings.Join(lines, "\n"))
+	}
+	b.WriteString("\n\n↑/↓: files • y: create project • b: back • q: quit")
+	return b.String()
+}
+
+func (c *previewController) conflicts() int {
+	n := 0
+	for _, change := range c.files.Items() {
+		if change.Action == scaffold.ActionConflict {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *previewController) onConfirm(msg mvct.KeyMsg) mvct.Cmd {
+	if c.err != nil {
+		return nil
+	}
+	c.confirm(c.config)
+	return mvct.Quit()
+}
+
+// onBack returns to the first step with the answers filled in, the wizard
+// cleared them when it completed
+func (c *previewController) onBack(msg mvct.KeyMsg) mvct.Cmd {
+	for name, value := range c.answers {
+		c.wizard.State().Set(name, value)
+	}
+	return mvct.Navigate(c.wizard.StepRoute(0))
+}
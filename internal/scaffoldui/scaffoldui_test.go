@@ -0,0 +1,122 @@
+package scaffoldui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-duren/mvct"
+	"github.com/michael-duren/mvct/forms"
+	"github.com/michael-duren/mvct/internal/scaffold"
+)
+
+// driver runs the commands of an app like the bubbletea runtime would
+type driver struct {
+	t    *testing.T
+	app  *mvct.Application[struct{}]
+	quit bool
+}
+
+func (d *driver) run(cmd tea.Cmd) {
+	d.t.Helper()
+	if cmd == nil {
+		return
+	}
+	switch msg := cmd().(type) {
+	case nil:
+	case tea.QuitMsg:
+		d.quit = true
+	case tea.BatchMsg:
+		for _, c := range msg {
+			d.run(c)
+		}
+	default:
+		_, next := d.app.Update(msg)
+		d.run(next)
+	}
+}
+
+func (d *driver) press(keys ...string) {
+	d.t.Helper()
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		}
+		_, cmd := d.app.Update(msg)
+		d.run(cmd)
+	}
+}
+
+func (d *driver) expectView(want string) {
+	d.t.Helper()
+	if view := d.app.View(); !strings.Contains(view, want) {
+		d.t.Fatalf("expected the view to contain %q, got:\n%s", want, view)
+	}
+}
+
+func TestScaffoldWizard(t *testing.T) {
+	var confirmed *scaffold.ProjectConfiguration
+	app := newApp(scaffold.ProjectConfiguration{}, func(config scaffold.ProjectConfiguration) {
+		confirmed = &config
+	})
+	d := &driver{t: t, app: app}
+	d.run(app.Init())
+
+	d.expectView("Project directory")
+	d.press("myapp", "enter", "enter")
+	d.expectView("Template")
+
+	// default -> list-detail
+	d.press("l", "enter", "enter")
+	d.expectView("What are the items called")
+	d.press("enter")
+	d.expectView("Log level")
+	d.press("enter", "enter")
+
+	d.expectView("controllers/list.go")
+	d.expectView("y: create project")
+
+	d.press("b")
+	d.expectView("myapp")
+	d.press("enter", "enter", "enter", "enter", "enter", "enter", "enter")
+	d.expectView("controllers/list.go")
+
+	d.press("y")
+	if !d.quit || confirmed == nil {
+		t.Fatal("expected confirming the preview to quit with the configuration")
+	}
+	if confirmed.Name != "myapp" || confirmed.Path != "myapp" || confirmed.Template != "list-detail" {
+		t.Errorf("expected the answers in the configuration, got %+v", confirmed)
+	}
+	if confirmed.Values["noun"] != "item" || confirmed.Logging != "debug" || confirmed.Theme != "" {
+		t.Errorf("expected the defaults of the other answers, got %+v", confirmed)
+	}
+}
+
+func TestScaffoldWizard_BackOverEmptyStep(t *testing.T) {
+	app := newApp(scaffold.ProjectConfiguration{Name: "myapp"}, func(scaffold.ProjectConfiguration) {})
+	d := &driver{t: t, app: app}
+	d.run(app.Init())
+
+	// the default template has no prompts, so their step is passed both ways
+	d.press("enter", "enter", "enter", "enter")
+	d.expectView("Log level")
+	d.press("esc")
+	d.expectView("SQLite database")
+
+	d.press("esc", "esc")
+	if !d.quit {
+		t.Error("expected esc on the first step to quit")
+	}
+}
+
+func TestFormStep_ValidationWithoutForm(t *testing.T) {
+	step := &formStep{}
+	if cmd := step.OnValidationMsg(forms.ValidationMsg{Form: "prompts", Field: "name"}); cmd != nil {
+		t.Error("expected a step without fields to ignore validation results")
+	}
+}
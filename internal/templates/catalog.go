@@ -0,0 +1,144 @@
+package templates
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultTemplate is the project template used without --template
+const DefaultTemplate = "default"
+
+// ManifestFile is the manifest of a project template
+const ManifestFile = "template.json"
+
+//go:embed all:catalog
+var catalog embed.FS
+
+// Prompt is a value asked for when a project is scaffolded, templates use
+// it as {{.Values.<name>}}
+type Prompt struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	Default string `json:"default"`
+	// Type is "string", the default, or "bool"
+	Type string `json:"type"`
+}
+
+// Route is a route of the project, the first one is the default route
+type Route struct {
+	// Name is the field in the R struct of controllers/routing.go
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Controller is the call creating the controller in package
+	// controllers, like NewHomeController(). Routes without one, like the
+	// route of a wizard, are not registered with RegisterController
+	Controller string `json:"controller"`
+	// If is a template condition, like .Values.settings
+	If string `json:"if"`
+}
+
+// FileRule adds a condition to a file of the template
+type FileRule struct {
+	Path string `json:"path"`
+	If   string `json:"if"`
+}
+
+// Manifest describes a project template
+type Manifest struct {
+	Description string   `json:"description"`
+	Prompts     []Prompt `json:"prompts"`
+	Routes      []Route  `json:"routes"`
+	// Setup are statements added to main after the routes are registered,
+	// the application is app
+	Setup []string `json:"setup"`
+	// Files lists the files only written when their condition holds, the
+	// other files are always written
+	Files []FileRule `json:"files"`
+}
+
+// Template is a project template. Its files are rendered with
+// text/template when their name ends in .tmpl, which is dropped, and
+// copied otherwise
+type Template struct {
+	Name string
+	Manifest
+	FS fs.FS
+}
+
+// Builtin returns the templates shipped with mvct sorted by name
+func Builtin() []Template {
+	entries, err := catalog.ReadDir("catalog")
+	if err != nil {
+		panic(err)
+	}
+
+	var builtin []Template
+	for _, entry := range entries {
+		sub, err := fs.Sub(catalog, "catalog/"+entry.Name())
+		if err != nil {
+			panic(err)
+		}
+		t, err := load(entry.Name(), sub)
+		if err != nil {
+			panic(err)
+		}
+		builtin = append(builtin, *t)
+	}
+	sort.Slice(builtin, func(i, j int) bool { return builtin[i].Name < builtin[j].Name })
+	return builtin
+}
+
+// Load returns the built-in template with the given name, or the template
+// in a local directory when name is a path
+func Load(name string) (*Template, error) {
+	if name == "" {
+		name = DefaultTemplate
+	}
+	if strings.ContainsAny(name, `/\.`) {
+		dir, err := filepath.Abs(name)
+		if err != nil {
+			return nil, err
+		}
+		return load(filepath.Base(dir), os.DirFS(dir))
+	}
+
+	var names []string
+	for _, t := range Builtin() {
+		if t.Name == name {
+			return &t, nil
+		}
+		names = append(names, t.Name)
+	}
+	return nil, fmt.Errorf("unknown template %q, use one of %s or a directory", name, strings.Join(names, ", "))
+}
+
+func load(name string, fsys fs.FS) (*Template, error) {
+	data, err := fs.ReadFile(fsys, ManifestFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("template %s has no %s", name, ManifestFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Template{Name: name, FS: fsys}
+	if err := json.Unmarshal(data, &t.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s of template %s: %w", ManifestFile, name, err)
+	}
+	if len(t.Routes) == 0 {
+		return nil, fmt.Errorf("template %s declares no routes", name)
+	}
+	for _, prompt := range t.Prompts {
+		if prompt.Type != "" && prompt.Type != "string" && prompt.Type != "bool" {
+			return nil, fmt.Errorf("prompt %s of template %s has unknown type %q", prompt.Name, name, prompt.Type)
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,11 @@
+-- name: GetTodos :many
+SELECT * FROM todos ORDER BY id;
+
+-- name: CreateTodo :one
+INSERT INTO todos (text, done) VALUES (?, ?) RETURNING *;
+
+-- name: UpdateTodo :exec
+UPDATE todos SET done = ? WHERE id = ?;
+
+-- name: DeleteTodo :exec
+DELETE FROM todos WHERE id = ?;
@@ -1,72 +1,119 @@
 package templates
 
-const GoModTemplate = `module {{.Name}}
+import (
+	"embed"
+	"io/fs"
+)
 
-go 1.25.1
+// GoVersion is the go directive of scaffolded projects, the minimum
+// version mvct builds with
+const GoVersion = "1.25.1"
+
+//go:embed all:database
+var database embed.FS
+
+// Database returns the files added to projects scaffolded with --db. They
+// are rendered like the files of a project template
+func Database() fs.FS {
+	sub, err := fs.Sub(database, "database")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
 
-require (
-	github.com/michael-duren/mvct v0.0.0
-	github.com/charmbracelet/log v0.4.0
-)
+const GoModTemplate = `module {{.Module}}
 
-replace github.com/michael-duren/mvct => ../
-`
+go {{.GoVersion}}
+{{if .Version}}
+require github.com/michael-duren/mvct {{.Version}}
+{{end}}{{if .Replace}}
+replace github.com/michael-duren/mvct => {{.Replace}}
+{{end}}`
 
 const MainGoTemplate = `package main
 
 import (
-	"log/slog"
-{{if .WithDB}}
-    "database/sql"
-    "log"
+{{- if .WithDB}}
+	"context"
+	"database/sql"
+
 	_ "github.com/mattn/go-sqlite3"
-{{end}}
 
-	"github.com/michael-duren/mvct"
+	"{{.Module}}/db"
+	"{{.Module}}/repository"
+{{- end}}
+
+	"{{.Module}}/components"
+	"{{.Module}}/controllers"
+
 	"github.com/charmbracelet/log"
-	"{{.Name}}/components"
-	"{{.Name}}/controllers"
+	"github.com/michael-duren/mvct"
 )
 
 type AppModel struct {
-{{if .WithDB}}
-    DB *sql.DB
-{{end}}
+{{- if .WithDB}}
+	Todos repository.TodoRepository
+{{- end}}
 }
 
 func main() {
-{{if .WithDB}}
-    db, err := sql.Open("sqlite3", "app.db")
-    if err != nil {
-        log.Fatal(err)
-    }
-    defer db.Close()
+{{- if .WithDB}}
+	conn, err := sql.Open("sqlite3", "app.db")
+	if err != nil {
+		log.Fatal("failed to open database", "error", err)
+	}
+	defer conn.Close()
+	if err := db.Migrate(context.Background(), conn); err != nil {
+		log.Fatal("failed to migrate database", "error", err)
+	}
 {{end}}
-
+	model := AppModel{
+{{- if .WithDB}}
+		Todos: repository.NewTodoRepository(conn),
+{{- end}}
+	}
 	R := controllers.R
 	app := mvct.NewApplication(mvct.Config{
-		DefaultRoute: R.Home,
-	}, AppModel{
-{{if .WithDB}}
-        DB: db,
+		DefaultRoute: R.{{(index .Routes 0).Name}},
+	}, model)
+
+{{- if .LogLevel}}
+	if err := app.UseLogger(mvct.LoggerConfig{
+		LogLevel: mvct.LogLevel(log.{{.LogLevel}}),
+	}); err != nil {
+		log.Fatal("failed to set up logging", "error", err)
+	}
 {{end}}
-    })
-
-	app.UseLogger(mvct.LoggerConfig{
-		Path:     "app.log",
-		LogLevel: mvct.LogLevel(slog.LevelDebug),
-	})
-
-	app.SetLayout(func(content string, width, height int) string {
-		return components.Layout(content, width, height)
-	})
-
-	app.UseGlobalHandler(mvct.QuitHandler("ctrl+c"))
-	app.UseGlobalHandler(mvct.QuitHandler("q"))
-
-	app.RegisterController(R.Home, controllers.NewHomeController())
-
-	app.Run()
+{{- if .Theme}}
+	if err := app.UseTheme(mvct.ThemeConfig{
+{{- if ne .Theme "auto"}}
+		Name: "{{.Theme}}",
+{{- end}}
+	}); err != nil {
+		log.Error("failed to load theme", "error", err)
+	}
+{{end}}
+	app.SetLayout(components.Layout)
+
+	app.UseGlobalHandler(mvct.QuitHandler("ctrl+c", "q"))
+{{- if .Theme}}
+	app.UseGlobalHandler(mvct.ThemeSwitchHandler("ctrl+t"))
+{{- end}}
+{{- if .WithDB}}
+	app.UseGlobalHandler(controllers.TodosHandler("ctrl+o"))
+{{- end}}
+
+{{range .Routes}}{{if .Controller}}
+	app.RegisterController(R.{{.Name}}, controllers.{{.Controller}})
+{{- end}}{{end}}
+{{- range .Setup}}
+	{{.}}
+{{- end}}
+
+	if err := app.Run(); err != nil {
+		log.Fatal("app failed", "error", err)
+	}
 }
 `
 
@@ -75,91 +122,104 @@ const LayoutTemplate = `package components
 import "github.com/charmbracelet/lipgloss"
 
 func Layout(content string, width, height int) string {
-    style := lipgloss.NewStyle().
-        Width(width).
-        Height(height).
-        Align(lipgloss.Center, lipgloss.Center).
-        BorderStyle(lipgloss.RoundedBorder()).
-        BorderForeground(lipgloss.Color("62"))
-
-    return style.Render(content)
+	style := lipgloss.NewStyle().
+		Width(width-2).
+		Height(height-2).
+		Align(lipgloss.Center, lipgloss.Center).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62"))
+
+	return style.Render(content)
 }
 `
 
-const HomeControllerTemplate = `package controllers
+const RoutingTemplate = `package controllers
+
+//go:generate mvct gen
+
+var R = struct {
+{{- range .Routes}}
+	{{.Name}} string
+{{- end}}
+}{
+{{- range .Routes}}
+	{{.Name}}: "{{.Path}}",
+{{- end}}
+}
+`
+
+const ControllerTemplate = `package controllers
 
 import (
-    "fmt"
 	"github.com/michael-duren/mvct"
-	container "github.com/charmbracelet/bubbletea"
 )
 
-type HomeController struct{}
+type {{.Type}} struct{}
+
+func New{{.Type}}() *{{.Type}} {
+	return &{{.Type}}{}
+}
 
-func NewHomeController() *HomeController {
-	return &HomeController{}
+func (c *{{.Type}}) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	c.RegisterKeyHandlers(handlers)
+	return nil
 }
 
-func (c *HomeController) Init(m mvct.Model) (mvct.Model, container.Cmd) {
-	return m, nil
+func (c *{{.Type}}) View() string {
+	return "{{.Title}}\n\nesc: back"
 }
 
-func (c *HomeController) Update(m mvct.Model, msg container.Msg) (mvct.Model, container.Cmd) {
-    if msg, ok := msg.(container.KeyMsg); ok {
-        if msg.String() == "enter" {
-            // Do something
-        }
-    }
-	return m, nil
+// RegisterKeyHandlers registers the keys of the {{.Title}} screen
+func (c *{{.Type}}) RegisterKeyHandlers(handlers mvct.KeyHandlers) {
+	handlers["esc"] = c.onBack
 }
 
-func (c *HomeController) View(m mvct.Model) string {
-	return fmt.Sprintf("Welcome to %s!\nPress q to quit.", "{{.Name}}")
+func (c *{{.Type}}) onBack(msg mvct.KeyMsg) mvct.Cmd {
+	return mvct.Navigate(R.{{.Back}})
 }
 `
 
-const RoutingTemplate = `package controllers
+const MiddlewareTemplate = `package middleware
 
-import "github.com/michael-duren/mvct"
+import (
+	"github.com/michael-duren/mvct"
+)
 
-var R = struct {
-    Home mvct.Route
-}{
-    Home: mvct.Route("home"),
+// {{.Type}} runs before every navigation, return false to block it
+func {{.Type}}() mvct.Middleware {
+	return mvct.MiddlewareFunc(func(ctx *mvct.Context) bool {
+		return true
+	})
 }
 `
 
-const SqlcYamlTemplate = `version: "2"
-sql:
-  - schema: "db/schema.sql"
-    queries: "db/queries.sql"
-    engine: "sqlite"
-    gen:
-      go:
-        package: "db"
-        out: "internal/db"
-`
+const ComponentTemplate = `package components
 
-const SchemaSqlTemplate = `CREATE TABLE todos (
-  id   INTEGER PRIMARY KEY,
-  text TEXT    NOT NULL,
-  done BOOLEAN NOT NULL DEFAULT 0
-);
-`
+import (
+	"github.com/michael-duren/mvct"
+	mvctcomponents "github.com/michael-duren/mvct/components"
+)
 
-const QueriesSqlTemplate = `-- name: GetTodos :many
-SELECT * FROM todos;
+var _ mvctcomponents.Component = (*{{.Type}})(nil)
 
--- name: CreateTodo :one
-INSERT INTO todos (text, done) VALUES (?, ?) RETURNING *;
+// {{.Type}} is a component, call its Init from the Init of the controller
+// that owns it
+type {{.Type}} struct{}
 
--- name: UpdateTodo :exec
-UPDATE todos SET done = ? WHERE id = ?;
+func New{{.Type}}() *{{.Type}} {
+	return &{{.Type}}{}
+}
 
--- name: DeleteTodo :exec
-DELETE FROM todos WHERE id = ?;
-`
+func (c *{{.Type}}) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	return nil
+}
 
-const MakefileTemplate = `sqlc:
-	sqlc generate
+func (c *{{.Type}}) View() string {
+	return "{{.Title}}"
+}
+
+// Bindings returns the key bindings of the component for help views
+func (c *{{.Type}}) Bindings() []mvct.KeyBinding {
+	return nil
+}
 `
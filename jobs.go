@@ -0,0 +1,390 @@
+package mvct
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// JobStatus is the state of a background job
+type JobStatus int
+
+const (
+	JobRunning JobStatus = iota
+	JobSucceeded
+	JobFailed
+	JobCancelled
+)
+
+func (s JobStatus) String() string {
+	switch s {
+	case JobRunning:
+		return "running"
+	case JobSucceeded:
+		return "done"
+	case JobFailed:
+		return "failed"
+	case JobCancelled:
+		return "cancelled"
+	}
+	return fmt.Sprintf("status(%d)", int(s))
+}
+
+// Job is a snapshot of a background job
+type Job struct {
+	ID     int
+	Name   string
+	Status JobStatus
+	// Progress is between 0 and 1
+	Progress float64
+	// Message is the last status text reported by the job
+	Message  string
+	Err      error
+	Started  time.Time
+	Finished time.Time
+}
+
+// JobDoneMsg is published on the event bus when a job started with
+// StartJob finishes, subscribe to it with HandleMsg to receive the result
+// on any controller. Job.Err is context.Canceled for cancelled jobs
+type JobDoneMsg[T any] struct {
+	Job    Job
+	Result T
+}
+
+// JobProgress reports the progress of a running job
+type JobProgress struct {
+	job *runningJob
+}
+
+// Report updates the progress, between 0 and 1, and the status text of
+// the job. Reports are coalesced so calling it often is cheap
+func (p *JobProgress) Report(progress float64, message string) {
+	p.job.mu.Lock()
+	p.job.job.Progress = min(max(progress, 0), 1)
+	p.job.job.Message = message
+	p.job.mu.Unlock()
+
+	select {
+	case p.job.updated <- struct{}{}:
+	default:
+	}
+}
+
+// CancelJobMsg cancels a running job
+type CancelJobMsg struct {
+	ID int
+}
+
+// startJobMsg asks the application to start a job
+type startJobMsg struct {
+	name string
+	run  func(ctx context.Context, progress *JobProgress) (any, error)
+	done func(job Job, result any) tea.Msg
+}
+
+// StartJob runs fn in the background. The job survives navigation, is
+// listed on the jobs route and its result is published as a JobDoneMsg[T]
+func StartJob[T any](name string, fn func(ctx context.Context, progress *JobProgress) (T, error)) Cmd {
+	return func() Msg {
+		return Msg{
+			Inner: startJobMsg{
+				name: name,
+				run: func(ctx context.Context, progress *JobProgress) (any, error) {
+					return fn(ctx, progress)
+				},
+				done: func(job Job, result any) tea.Msg {
+					value, _ := result.(T)
+					return JobDoneMsg[T]{Job: job, Result: value}
+				},
+			},
+		}
+	}
+}
+
+// CancelJob cancels a running job, it finishes once its function returns
+func CancelJob(id int) Cmd {
+	return func() Msg {
+		return Msg{
+			Inner: CancelJobMsg{ID: id},
+		}
+	}
+}
+
+// runningJob is a job tracked by the application
+type runningJob struct {
+	mu      sync.Mutex
+	job     Job
+	result  any
+	cancel  context.CancelFunc
+	updated chan struct{}
+	done    chan struct{}
+	message func(job Job, result any) tea.Msg
+}
+
+// jobUpdatedMsg re-renders the application when a job reported progress
+type jobUpdatedMsg struct {
+	job *runningJob
+}
+
+// jobFinishedMsg is returned once the function of a job returned
+type jobFinishedMsg struct {
+	job *runningJob
+}
+
+func (j *runningJob) snapshot() Job {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.job
+}
+
+// wait returns the next progress update or the end of the job
+func (j *runningJob) wait() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case <-j.done:
+			return jobFinishedMsg{job: j}
+		case <-j.updated:
+			return jobUpdatedMsg{job: j}
+		}
+	}
+}
+
+func (j *runningJob) start(run func(ctx context.Context, progress *JobProgress) (any, error)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+
+	go func() {
+		defer close(j.done)
+		defer cancel()
+
+		result, err := func() (result any, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("job panicked: %v", r)
+				}
+			}()
+			return run(ctx, &JobProgress{job: j})
+		}()
+
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		j.result = result
+		j.job.Err = err
+		j.job.Finished = time.Now()
+		switch {
+		case err == nil:
+			j.job.Status = JobSucceeded
+			j.job.Progress = 1
+		case errors.Is(err, context.Canceled):
+			j.job.Status = JobCancelled
+		default:
+			j.job.Status = JobFailed
+		}
+	}()
+}
+
+// JobsConfig configures background jobs
+type JobsConfig struct {
+	// Route registers a route listing jobs when set, the selected job can
+	// be cancelled from it
+	Route string
+	// Notify shows a notification when a job fails or succeeds
+	Notify bool
+	// HistoryLimit is the number of finished jobs kept for the jobs route
+	HistoryLimit int
+}
+
+func (c JobsConfig) withDefaults() JobsConfig {
+	if c.HistoryLimit <= 0 {
+		c.HistoryLimit = 20
+	}
+	return c
+}
+
+// jobManager keeps track of the jobs of an application
+type jobManager struct {
+	config JobsConfig
+	nextID int
+	jobs   []*runningJob
+}
+
+func (m *jobManager) find(id int) *runningJob {
+	for _, job := range m.jobs {
+		if job.job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+// prune drops the oldest finished jobs over the history limit
+func (m *jobManager) prune() {
+	finished := 0
+	for i := len(m.jobs) - 1; i >= 0; i-- {
+		if m.jobs[i].snapshot().Status == JobRunning {
+			continue
+		}
+		finished++
+		if finished > m.config.HistoryLimit {
+			m.jobs = append(m.jobs[:i], m.jobs[i+1:]...)
+		}
+	}
+}
+
+func (m *jobManager) cancelAll() {
+	for _, job := range m.jobs {
+		job.cancel()
+	}
+}
+
+// UseJobs configures background jobs. Jobs work without calling this, it
+// only needs to be called to change the defaults
+func (a *Application[M]) UseJobs(config JobsConfig) {
+	slog.Debug("Configuring jobs", "route", config.Route)
+	a.jobs.config = config.withDefaults()
+
+	if config.Route != "" {
+		a.RegisterController(config.Route, &jobsController{
+			jobs:          a.Jobs,
+			previousRoute: a.router.PreviousRoute,
+		})
+	}
+}
+
+// Jobs returns the running jobs and the most recent finished jobs, oldest
+// first
+func (a *Application[M]) Jobs() []Job {
+	jobs := make([]Job, 0, len(a.jobs.jobs))
+	for _, job := range a.jobs.jobs {
+		jobs = append(jobs, job.snapshot())
+	}
+	return jobs
+}
+
+func (a *Application[M]) handleStartJob(msg startJobMsg) (tea.Model, tea.Cmd) {
+	a.jobs.nextID++
+	job := &runningJob{
+		job: Job{
+			ID:      a.jobs.nextID,
+			Name:    msg.name,
+			Status:  JobRunning,
+			Started: time.Now(),
+		},
+		updated: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		message: msg.done,
+	}
+	a.jobs.jobs = append(a.jobs.jobs, job)
+
+	slog.Info("Starting job", "id", job.job.ID, "name", msg.name)
+	job.start(msg.run)
+	return a, job.wait()
+}
+
+func (a *Application[M]) handleCancelJob(msg CancelJobMsg) (tea.Model, tea.Cmd) {
+	job := a.jobs.find(msg.ID)
+	if job == nil {
+		slog.Warn("Cannot cancel unknown job", "id", msg.ID)
+		return a, nil
+	}
+	slog.Info("Cancelling job", "id", msg.ID)
+	job.cancel()
+	return a, nil
+}
+
+func (a *Application[M]) handleJobFinished(msg jobFinishedMsg) (tea.Model, tea.Cmd) {
+	job := msg.job.snapshot()
+	slog.Info("Job finished", "id", job.ID, "name", job.Name, "status", job.Status, "error", job.Err)
+	a.jobs.prune()
+
+	cmds := []tea.Cmd{a.events.publish(msg.job.message(job, msg.job.result), a.router.Current())}
+	if a.jobs.config.Notify {
+		switch job.Status {
+		case JobSucceeded:
+			cmds = append(cmds, a.notifications.push(NotifyMsg{Level: NotifySuccess, Text: job.Name + " finished"}))
+		case JobFailed:
+			cmds = append(cmds, a.notifications.push(NotifyMsg{Level: NotifyError, Text: job.Name + " failed: " + job.Err.Error()}))
+		}
+	}
+	return a, tea.Batch(cmds...)
+}
+
+// jobsController lists jobs, newest first, and cancels the selected one
+type jobsController struct {
+	jobs          func() []Job
+	previousRoute func() string
+	selected      int
+}
+
+func (c *jobsController) Init(handlers KeyHandlers) Cmd {
+	c.selected = 0
+	handlers["esc"] = func(msg KeyMsg) Cmd {
+		if route := c.previousRoute(); route != "" {
+			return Navigate(route)
+		}
+		return nil
+	}
+	handlers["up"] = func(msg KeyMsg) Cmd {
+		c.selected = max(c.selected-1, 0)
+		return nil
+	}
+	handlers["k"] = handlers["up"]
+	handlers["down"] = func(msg KeyMsg) Cmd {
+		c.selected = min(c.selected+1, max(len(c.jobs())-1, 0))
+		return nil
+	}
+	handlers["j"] = handlers["down"]
+	handlers["x"] = func(msg KeyMsg) Cmd {
+		jobs := c.newestFirst()
+		if c.selected < len(jobs) && jobs[c.selected].Status == JobRunning {
+			return CancelJob(jobs[c.selected].ID)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (c *jobsController) newestFirst() []Job {
+	jobs := c.jobs()
+	for i, j := 0, len(jobs)-1; i < j; i, j = i+1, j-1 {
+		jobs[i], jobs[j] = jobs[j], jobs[i]
+	}
+	return jobs
+}
+
+func (c *jobsController) View() string {
+	var b strings.Builder
+	jobs := c.newestFirst()
+
+	b.WriteString(fmt.Sprintf("Jobs (%d)\n\n", len(jobs)))
+	if len(jobs) == 0 {
+		b.WriteString("  No jobs yet\n")
+	}
+	for i, job := range jobs {
+		cursor := " "
+		if i == c.selected {
+			cursor = ">"
+		}
+		message := job.Message
+		if job.Err != nil {
+			message = job.Err.Error()
+		}
+		b.WriteString(fmt.Sprintf("%s #%-3d %-20s %s %3.0f%%  %-9s  %s\n",
+			cursor, job.ID, job.Name, progressBar(job.Progress, 20), job.Progress*100, job.Status, message))
+	}
+	b.WriteString("\n↑/↓: select • x: cancel • esc: back")
+
+	return b.String()
+}
+
+func progressBar(progress float64, width int) string {
+	filled := int(progress * float64(width))
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
@@ -0,0 +1,126 @@
+package mvct
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type importResult struct {
+	Rows int
+}
+
+// ImportController receives job results while it is not active
+type ImportController struct {
+	MockController
+	done []JobDoneMsg[importResult]
+}
+
+func (c *ImportController) onImportDone(msg JobDoneMsg[importResult]) Cmd {
+	c.done = append(c.done, msg)
+	return nil
+}
+
+// runJob starts the job of cmd and feeds its messages to app until it
+// finishes
+func runJob(app *Application[string], cmd Cmd) {
+	_, next := app.Update(cmd().Inner)
+	for next != nil {
+		msg := next()
+		_, next = app.Update(msg)
+		if _, ok := msg.(jobFinishedMsg); ok {
+			return
+		}
+	}
+}
+
+func TestJobs_TypedResult(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/import"}, "model")
+	importer := &ImportController{}
+	app.RegisterController("/import", importer)
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.UseJobs(JobsConfig{Route: "/jobs"})
+	app.Init()
+	app.Events().Subscribe(importer, DeliverQueued, HandleMsg(importer.onImportDone))
+
+	release := make(chan struct{})
+	cmd := StartJob("import", func(ctx context.Context, progress *JobProgress) (importResult, error) {
+		progress.Report(0.5, "halfway")
+		<-release
+		return importResult{Rows: 42}, nil
+	})
+
+	_, wait := app.Update(cmd().Inner)
+	if _, ok := wait().(jobUpdatedMsg); !ok {
+		t.Fatal("expected a progress update")
+	}
+	jobs := app.Jobs()
+	if len(jobs) != 1 || jobs[0].Progress != 0.5 || jobs[0].Message != "halfway" {
+		t.Fatalf("expected a running job at 50%%, got %+v", jobs)
+	}
+
+	app.Update(NavigateMsg{Route: "/jobs"})
+	if view := app.View(); !strings.Contains(view, "import") || !strings.Contains(view, "halfway") {
+		t.Errorf("expected the jobs route to list the job, got %q", view)
+	}
+
+	close(release)
+	app.Update(wait())
+	if len(importer.done) != 0 {
+		t.Fatal("inactive subscriber should receive the result on activation")
+	}
+	app.Update(NavigateMsg{Route: "/import"})
+	if len(importer.done) != 1 || importer.done[0].Result.Rows != 42 || importer.done[0].Job.Status != JobSucceeded {
+		t.Errorf("expected the typed result, got %+v", importer.done)
+	}
+}
+
+func TestJobs_Cancel(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/import"}, "model")
+	importer := &ImportController{}
+	app.RegisterController("/import", importer)
+	app.Init()
+	app.Events().Subscribe(importer, DeliverQueued, HandleMsg(importer.onImportDone))
+
+	_, wait := app.Update(StartJob("sync", func(ctx context.Context, progress *JobProgress) (importResult, error) {
+		<-ctx.Done()
+		return importResult{}, ctx.Err()
+	})().Inner)
+
+	id := app.Jobs()[0].ID
+	app.Update(CancelJob(id)().Inner)
+	app.Update(wait())
+
+	if status := app.Jobs()[0].Status; status != JobCancelled {
+		t.Errorf("expected a cancelled job, got %s", status)
+	}
+	if len(importer.done) != 1 || !errors.Is(importer.done[0].Job.Err, context.Canceled) {
+		t.Errorf("expected a cancelled result, got %+v", importer.done)
+	}
+}
+
+func TestJobs_FailureNotifies(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.UseJobs(JobsConfig{Notify: true, HistoryLimit: 1})
+	app.Init()
+
+	for range 2 {
+		runJob(app, StartJob("build", func(ctx context.Context, progress *JobProgress) (int, error) {
+			panic("boom")
+		}))
+	}
+
+	jobs := app.Jobs()
+	if len(jobs) != 1 {
+		t.Fatalf("expected the history to be limited to 1 job, got %d", len(jobs))
+	}
+	if jobs[0].Status != JobFailed || !strings.Contains(jobs[0].Err.Error(), "boom") {
+		t.Errorf("expected a failed job, got %+v", jobs[0])
+	}
+	notifications := app.Notifications()
+	if len(notifications) == 0 || notifications[0].Level != NotifyError {
+		t.Errorf("expected an error notification, got %+v", notifications)
+	}
+}
@@ -1,6 +1,10 @@
 package mvct
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
 
 type KeyMsg = tea.KeyMsg
 
@@ -8,6 +12,7 @@ type KeyMsg = tea.KeyMsg
 type KeyBinding struct {
 	keys    []string
 	handler KeyMsgHandler
+	help    string
 }
 
 // Key creates a new key binding with the specified keys
@@ -23,6 +28,27 @@ func (kb KeyBinding) To(handler KeyMsgHandler) KeyBinding {
 	return kb
 }
 
+// Help sets the description shown for the binding in help views
+func (kb KeyBinding) Help(description string) KeyBinding {
+	kb.help = description
+	return kb
+}
+
+// Keys returns the keys the binding responds to
+func (kb KeyBinding) Keys() []string {
+	return kb.keys
+}
+
+// Description returns the help description of the binding
+func (kb KeyBinding) Description() string {
+	return kb.help
+}
+
+// Matches reports whether msg is one of the binding's keys
+func (kb KeyBinding) Matches(msg KeyMsg) bool {
+	return slices.Contains(kb.keys, msg.String())
+}
+
 // Keys converts a list of KeyBindings into a map of key strings to handlers
 // this is registered with a controller
 func Keys(bindings ...KeyBinding) map[string]KeyMsgHandler {
@@ -35,7 +61,28 @@ func Keys(bindings ...KeyBinding) map[string]KeyMsgHandler {
 	return keyMap
 }
 
+// Bind registers bindings that only handle their keys while active returns
+// true. While inactive a key falls through to the handler registered for it
+// before, which lets several focusable components share the same keys
+func Bind(handlers KeyHandlers, active func() bool, bindings ...KeyBinding) {
+	for _, binding := range bindings {
+		for _, k := range binding.keys {
+			handler, previous := binding.handler, handlers[k]
+			handlers[k] = func(msg KeyMsg) Cmd {
+				if active() {
+					return handler(msg)
+				}
+				if previous != nil {
+					return previous(msg)
+				}
+				return nil
+			}
+		}
+	}
+}
+
 const (
+	KeyRunes     = tea.KeyRunes
 	KeyBackspace = tea.KeyBackspace
 	KeyEnter     = tea.KeyEnter
 	KeyEsc       = tea.KeyEsc
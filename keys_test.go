@@ -56,3 +56,44 @@ func TestKeys(t *testing.T) {
 		t.Error("Wrapper for 'c' does not match handler2")
 	}
 }
+
+func TestKeyBindingHelp(t *testing.T) {
+	kb := Key("up", "k").Help("move up")
+
+	if kb.Description() != "move up" {
+		t.Errorf("expected description 'move up', got %q", kb.Description())
+	}
+	if len(kb.Keys()) != 2 || kb.Keys()[1] != "k" {
+		t.Errorf("expected keys [up k], got %v", kb.Keys())
+	}
+	if !kb.Matches(KeyMsg{Type: KeyUp}) {
+		t.Error("expected binding to match up arrow")
+	}
+	if kb.Matches(KeyMsg{Type: KeyRunes, Runes: []rune("j")}) {
+		t.Error("binding should not match j")
+	}
+}
+
+func TestBind(t *testing.T) {
+	handlers := KeyHandlers{}
+	var calls []string
+	firstActive, secondActive := true, false
+
+	Bind(handlers, func() bool { return firstActive },
+		Key("enter").To(func(msg KeyMsg) Cmd { calls = append(calls, "first"); return nil }))
+	Bind(handlers, func() bool { return secondActive },
+		Key("enter").To(func(msg KeyMsg) Cmd { calls = append(calls, "second"); return nil }))
+
+	enter := KeyMsg{Type: KeyEnter}
+	handlers["enter"](enter)
+
+	firstActive, secondActive = false, true
+	handlers["enter"](enter)
+
+	secondActive = false
+	handlers["enter"](enter)
+
+	if len(calls) != 2 || calls[0] != "first" || calls[1] != "second" {
+		t.Errorf("expected [first second], got %v", calls)
+	}
+}
@@ -0,0 +1,66 @@
+package lint
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// Check runs the analyzer on the packages matching patterns from dir and
+// writes the diagnostics to w, it returns the number of diagnostics
+func Check(dir string, w io.Writer, patterns ...string) (int, error) {
+	pkgs, err := load(dir, patterns)
+	if err != nil {
+		return 0, err
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{Analyzer}, pkgs, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	type finding struct {
+		pos     string
+		message string
+	}
+	var findings []finding
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return 0, fmt.Errorf("%s: %w", act.Package.PkgPath, act.Err)
+		}
+		for _, diag := range act.Diagnostics {
+			findings = append(findings, finding{
+				pos:     act.Package.Fset.Position(diag.Pos).String(),
+				message: diag.Message,
+			})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool { return findings[i].pos < findings[j].pos })
+	for _, f := range findings {
+		fmt.Fprintf(w, "%s: %s\n", f.pos, f.message)
+	}
+	return len(findings), nil
+}
+
+// load loads the packages matching patterns from dir with their syntax and
+// types, default ./...
+func load(dir string, patterns []string) ([]*packages.Package, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	cfg := &packages.Config{Mode: packages.LoadAllSyntax, Dir: dir}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, fmt.Errorf("%d package loading errors", n)
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,625 @@
+// Package lint provides a go/analysis analyzer for common mvct mistakes:
+//
+//   - On* handlers of controllers that don't have the func(msg T) mvct.Cmd
+//     signature reflection dispatch expects, such as handlers with two
+//     parameters or returning tea.Cmd
+//   - navigation to routes that are never registered
+//   - key strings tea.KeyMsg.String never produces, such as "Enter"
+//
+// Routes are checked in the package registering controllers, navigations
+// in the packages it imports are passed to it as facts. Routes are resolved
+// from constants and from string fields of package level struct literals
+// such as the route table scaffolded in controllers/routing.go
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	mvctPath = "github.com/michael-duren/mvct"
+	teaPath  = "github.com/charmbracelet/bubbletea"
+)
+
+// Analyzer reports common mvct mistakes
+var Analyzer = &analysis.Analyzer{
+	Name:      "mvct",
+	Doc:       "report invalid On* handlers, navigation to unregistered routes and key strings bubbletea never produces",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(routesFact)},
+	Run:       run,
+}
+
+// remoteNavigation is a navigation recorded in the fact of a package
+type remoteNavigation struct {
+	Route    string
+	Position string
+}
+
+// routesFact carries the routes a package registers and navigates to
+type routesFact struct {
+	Registered []string
+	// Prefixes are wizard paths, their steps are registered below them
+	Prefixes []string
+	// Unresolved is set when a route was registered with a value that could
+	// not be resolved, unregistered routes can't be reported then
+	Unresolved  bool
+	Navigations []remoteNavigation
+	// Constants maps pkgpath.Var.Field to the string fields of package
+	// level struct literals
+	Constants map[string]string
+}
+
+func (*routesFact) AFact() {}
+
+func (f *routesFact) String() string {
+	return fmt.Sprintf("routes(%d registered, %d navigations)", len(f.Registered), len(f.Navigations))
+}
+
+type linter struct {
+	pass *analysis.Pass
+	mvct *types.Package
+	fact *routesFact
+	// registerPos is the first RegisterController call, routes navigated
+	// to in other packages are reported there
+	registerPos token.Pos
+	navigations []navigation
+	// aliases are local variables copying a route table, like R :=
+	// controllers.R
+	aliases map[*types.Var]*types.Var
+}
+
+type navigation struct {
+	route string
+	pos   token.Pos
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	mvct := lookupMvct(pass.Pkg)
+	if mvct == nil {
+		return nil, nil
+	}
+
+	l := &linter{
+		pass: pass,
+		mvct: mvct,
+		fact: &routesFact{Constants: map[string]string{}},
+	}
+	l.collectConstants()
+	l.aliases = localAliases(pass.TypesInfo, pass.Files)
+	l.checkHandlers()
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodes := []ast.Node{
+		(*ast.CallExpr)(nil),
+		(*ast.CompositeLit)(nil),
+		(*ast.IndexExpr)(nil),
+		(*ast.SwitchStmt)(nil),
+		(*ast.BinaryExpr)(nil),
+	}
+	inspect.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			l.call(n)
+		case *ast.CompositeLit:
+			l.compositeLit(n)
+		case *ast.IndexExpr:
+			l.index(n)
+		case *ast.SwitchStmt:
+			l.keySwitch(n)
+		case *ast.BinaryExpr:
+			l.keyComparison(n)
+		}
+	})
+
+	pass.ExportPackageFact(l.fact)
+	// mvct registers routes of its own with values only known at runtime
+	if l.registerPos.IsValid() && pass.Pkg != mvct {
+		l.checkRoutes()
+	}
+	return nil, nil
+}
+
+func lookupMvct(pkg *types.Package) *types.Package {
+	if pkg.Path() == mvctPath {
+		return pkg
+	}
+	for _, imported := range pkg.Imports() {
+		if imported.Path() == mvctPath {
+			return imported
+		}
+	}
+	return nil
+}
+
+func (l *linter) mvctType(name string) types.Type {
+	return l.mvct.Scope().Lookup(name).Type()
+}
+
+// collectConstants records the string fields of package level struct
+// literals so route tables like R.Home can be resolved
+func (l *linter) collectConstants() {
+	for key, value := range structConstants(l.pass.Pkg, l.pass.TypesInfo, l.pass.Files) {
+		l.fact.Constants[key] = value
+	}
+}
+
+// structConstants returns the constant string fields of the package level
+// struct literals in files keyed by pkgpath.Var.Field
+func structConstants(pkg *types.Package, info *types.Info, files []*ast.File) map[string]string {
+	constants := map[string]string{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.ValueSpec)
+				for i, name := range spec.Names {
+					if i >= len(spec.Values) {
+						break
+					}
+					lit, ok := ast.Unparen(spec.Values[i]).(*ast.CompositeLit)
+					if !ok {
+						continue
+					}
+					for _, elt := range lit.Elts {
+						kv, ok := elt.(*ast.KeyValueExpr)
+						if !ok {
+							continue
+						}
+						key, ok := kv.Key.(*ast.Ident)
+						if !ok {
+							continue
+						}
+						if value, ok := constString(info, kv.Value); ok {
+							constants[pkg.Path()+"."+name.Name+"."+key.Name] = value
+						}
+					}
+				}
+			}
+		}
+	}
+	return constants
+}
+
+func (l *linter) constString(expr ast.Expr) (string, bool) {
+	return constString(l.pass.TypesInfo, expr)
+}
+
+func constString(info *types.Info, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// route resolves a route expression to its value
+func (l *linter) route(expr ast.Expr) (string, bool) {
+	return resolveRoute(l.pass.TypesInfo, l.aliases, expr, func(pkg *types.Package, key string) (string, bool) {
+		if pkg == l.pass.Pkg {
+			value, ok := l.fact.Constants[key]
+			return value, ok
+		}
+		var fact routesFact
+		if !l.pass.ImportPackageFact(pkg, &fact) {
+			return "", false
+		}
+		value, ok := fact.Constants[key]
+		return value, ok
+	})
+}
+
+// localAliases returns the local variables defined as a copy of a package
+// level variable
+func localAliases(info *types.Info, files []*ast.File) map[*types.Var]*types.Var {
+	aliases := map[*types.Var]*types.Var{}
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != len(assign.Rhs) {
+				return true
+			}
+			for i, lhs := range assign.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				local, ok := info.Defs[ident].(*types.Var)
+				if !ok {
+					continue
+				}
+				if v := packageVar(info, assign.Rhs[i]); v != nil {
+					aliases[local] = v
+				}
+			}
+			return true
+		})
+	}
+	return aliases
+}
+
+// packageVar returns the package level variable expr names
+func packageVar(info *types.Info, expr ast.Expr) *types.Var {
+	var obj types.Object
+	switch x := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		obj = info.Uses[x]
+	case *ast.SelectorExpr:
+		obj = info.Uses[x.Sel]
+	}
+	v, ok := obj.(*types.Var)
+	if !ok || v.Pkg() == nil || v.Pkg().Scope().Lookup(v.Name()) != v {
+		return nil
+	}
+	return v
+}
+
+// resolveRoute resolves a constant or a field of a package level struct
+// literal, whose value lookup returns by the key structConstants uses, or a
+// concatenation of them. Fields of local copies of the struct are resolved
+// through aliases
+func resolveRoute(info *types.Info, aliases map[*types.Var]*types.Var, expr ast.Expr, lookup func(pkg *types.Package, key string) (string, bool)) (string, bool) {
+	if value, ok := constString(info, expr); ok {
+		return value, true
+	}
+	if bin, ok := ast.Unparen(expr).(*ast.BinaryExpr); ok && bin.Op == token.ADD {
+		x, ok := resolveRoute(info, aliases, bin.X, lookup)
+		if !ok {
+			return "", false
+		}
+		y, ok := resolveRoute(info, aliases, bin.Y, lookup)
+		return x + y, ok
+	}
+
+	sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	v := packageVar(info, sel.X)
+	if ident, ok := ast.Unparen(sel.X).(*ast.Ident); ok && v == nil {
+		if local, ok := info.Uses[ident].(*types.Var); ok {
+			v = aliases[local]
+		}
+	}
+	if v == nil {
+		return "", false
+	}
+	return lookup(v.Pkg(), v.Pkg().Path()+"."+v.Name()+"."+sel.Sel.Name)
+}
+
+// checkHandlers reports On* methods of controllers with a signature
+// reflection dispatch silently skips
+func (l *linter) checkHandlers() {
+	controller := l.mvctType("Controller").Underlying().(*types.Interface)
+	cmd := l.mvctType("Cmd")
+	seen := map[*types.Func]bool{}
+
+	scope := l.pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 || types.IsInterface(named) {
+			continue
+		}
+
+		var recv types.Type = named
+		if !types.Implements(recv, controller) {
+			recv = types.NewPointer(named)
+			if !types.Implements(recv, controller) {
+				continue
+			}
+		}
+
+		methods := types.NewMethodSet(recv)
+		for i := range methods.Len() {
+			fn := methods.At(i).Obj().(*types.Func)
+			if seen[fn] || fn.Pkg() != l.pass.Pkg || !fn.Exported() || !strings.HasPrefix(fn.Name(), "On") {
+				continue
+			}
+			seen[fn] = true
+
+			sig := fn.Type().(*types.Signature)
+			if callbackSetter(sig) {
+				continue
+			}
+			if n := sig.Params().Len(); n != 1 {
+				l.pass.Reportf(fn.Pos(), "%s has %d parameters, On* handlers take a single message: func(msg T) mvct.Cmd", fn.Name(), n)
+			}
+			switch results := sig.Results(); {
+			case results.Len() == 1 && isTeaCmd(results.At(0).Type()):
+				l.pass.Reportf(fn.Pos(), "%s returns tea.Cmd, On* handlers must return mvct.Cmd", fn.Name())
+			case results.Len() != 1 || !types.Identical(results.At(0).Type(), cmd):
+				l.pass.Reportf(fn.Pos(), "%s must return a single mvct.Cmd", fn.Name())
+			}
+		}
+	}
+}
+
+// callbackSetter reports whether an On* method registers a callback, like
+// a fluent OnSelect(fn) *Table, instead of handling a message. It takes a
+// func or returns its own receiver
+func callbackSetter(sig *types.Signature) bool {
+	for i := range sig.Params().Len() {
+		if _, ok := sig.Params().At(i).Type().Underlying().(*types.Signature); ok {
+			return true
+		}
+	}
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	for i := range sig.Results().Len() {
+		result := sig.Results().At(i).Type()
+		if ptr, ok := result.(*types.Pointer); ok {
+			result = ptr.Elem()
+		}
+		if types.Identical(result, recv) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTeaCmd(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == teaPath && named.Obj().Name() == "Cmd"
+}
+
+func (l *linter) call(call *ast.CallExpr) {
+	fn := typeutil.StaticCallee(l.pass.TypesInfo, call)
+	if fn == nil || fn.Pkg() != l.mvct {
+		return
+	}
+	isMethod := fn.Type().(*types.Signature).Recv() != nil
+
+	switch {
+	case (fn.Name() == "Navigate" && len(call.Args) == 1 || fn.Name() == "NavigateWith" && len(call.Args) == 2) && !isMethod:
+		l.navigate(call.Args[0])
+
+	case (fn.Name() == "RegisterController" || fn.Name() == "Register") && isMethod && len(call.Args) == 2:
+		if !l.registerPos.IsValid() {
+			l.registerPos = call.Pos()
+		}
+		l.register(call.Args[0])
+
+	case fn.Name() == "NewWizard" && !isMethod && len(call.Args) == 1:
+		if prefix, ok := l.route(call.Args[0]); ok {
+			l.fact.Prefixes = append(l.fact.Prefixes, strings.TrimSuffix(prefix, "/")+"/")
+		} else {
+			l.fact.Unresolved = true
+		}
+
+	case (fn.Name() == "Key" || fn.Name() == "QuitHandler" || fn.Name() == "ThemeSwitchHandler") && !isMethod:
+		for _, arg := range call.Args {
+			l.checkKey(arg)
+		}
+	}
+}
+
+func (l *linter) navigate(expr ast.Expr) {
+	if route, ok := l.route(expr); ok {
+		l.navigations = append(l.navigations, navigation{route: route, pos: expr.Pos()})
+		l.fact.Navigations = append(l.fact.Navigations, remoteNavigation{
+			Route:    route,
+			Position: l.pass.Fset.Position(expr.Pos()).String(),
+		})
+	}
+}
+
+func (l *linter) register(expr ast.Expr) {
+	if route, ok := l.route(expr); ok {
+		l.fact.Registered = append(l.fact.Registered, route)
+	} else {
+		l.fact.Unresolved = true
+	}
+}
+
+// compositeLit handles the routes set through struct fields
+func (l *linter) compositeLit(lit *ast.CompositeLit) {
+	named, ok := l.pass.TypesInfo.TypeOf(lit).(*types.Named)
+	if !ok || named.Obj().Pkg() != l.mvct {
+		return
+	}
+
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		switch named.Obj().Name() + "." + key.Name {
+		case "NavigateMsg.Route", "Config.DefaultRoute":
+			l.navigate(kv.Value)
+		case "NotificationConfig.HistoryRoute":
+			l.register(kv.Value)
+		}
+	}
+}
+
+// index checks the keys of KeyHandlers maps
+func (l *linter) index(expr *ast.IndexExpr) {
+	m, ok := l.pass.TypesInfo.TypeOf(expr.X).Underlying().(*types.Map)
+	if !ok || !types.Identical(m.Elem(), l.mvctType("KeyMsgHandler")) {
+		return
+	}
+	l.checkKey(expr.Index)
+}
+
+// keySwitch checks the cases of switch msg.String()
+func (l *linter) keySwitch(stmt *ast.SwitchStmt) {
+	if stmt.Tag == nil || !l.isKeyString(stmt.Tag) {
+		return
+	}
+	for _, clause := range stmt.Body.List {
+		for _, expr := range clause.(*ast.CaseClause).List {
+			l.checkKey(expr)
+		}
+	}
+}
+
+// keyComparison checks msg.String() == "key"
+func (l *linter) keyComparison(expr *ast.BinaryExpr) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return
+	}
+	switch {
+	case l.isKeyString(expr.X):
+		l.checkKey(expr.Y)
+	case l.isKeyString(expr.Y):
+		l.checkKey(expr.X)
+	}
+}
+
+// isKeyString reports whether expr calls String on a tea key
+func (l *linter) isKeyString(expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	fn := typeutil.StaticCallee(l.pass.TypesInfo, call)
+	if fn == nil || fn.Name() != "String" || fn.Pkg() == nil || fn.Pkg().Path() != teaPath {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	named, ok := types.Unalias(recv.Type()).(*types.Named)
+	return ok && (named.Obj().Name() == "KeyMsg" || named.Obj().Name() == "Key")
+}
+
+func (l *linter) checkKey(expr ast.Expr) {
+	key, ok := l.constString(expr)
+	if !ok || validKey(key) {
+		return
+	}
+	if suggestion, ok := suggestKey(key); ok {
+		l.pass.Reportf(expr.Pos(), "key %q is never produced by tea.KeyMsg.String, did you mean %q?", key, suggestion)
+		return
+	}
+	l.pass.Reportf(expr.Pos(), "key %q is never produced by tea.KeyMsg.String", key)
+}
+
+// checkRoutes reports navigations to routes no package registers
+func (l *linter) checkRoutes() {
+	registered := map[string]bool{}
+	prefixes := l.fact.Prefixes
+	unresolved := l.fact.Unresolved
+	for _, route := range l.fact.Registered {
+		registered[route] = true
+	}
+
+	var imported []remoteNavigation
+	for _, pf := range l.pass.AllPackageFacts() {
+		fact := pf.Fact.(*routesFact)
+		if pf.Package == l.pass.Pkg || pf.Package == l.mvct {
+			continue
+		}
+		for _, route := range fact.Registered {
+			registered[route] = true
+		}
+		prefixes = append(prefixes, fact.Prefixes...)
+		unresolved = unresolved || fact.Unresolved
+		imported = append(imported, fact.Navigations...)
+	}
+	if unresolved {
+		return
+	}
+
+	known := func(route string) bool {
+		if registered[route] {
+			return true
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(route, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, nav := range l.navigations {
+		if !known(nav.route) {
+			l.pass.Reportf(nav.pos, "route %q is never registered", nav.route)
+		}
+	}
+	for _, nav := range imported {
+		if !known(nav.Route) {
+			l.pass.Reportf(l.registerPos, "route %q navigated to at %s is never registered", nav.Route, nav.Position)
+		}
+	}
+}
+
+// keyNames holds the names tea.KeyMsg.String produces for special keys
+var keyNames = func() map[string]bool {
+	names := map[string]bool{}
+	for k := tea.KeyType(-512); k < 512; k++ {
+		if name := k.String(); name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+// validKey reports whether tea.KeyMsg.String can produce key
+func validKey(key string) bool {
+	key = strings.TrimPrefix(key, "alt+")
+	if keyNames[key] {
+		return true
+	}
+	r, size := utf8.DecodeRuneInString(key)
+	return size == len(key) && r != utf8.RuneError && !unicode.IsControl(r)
+}
+
+// keyAliases maps common names of keys to the names bubbletea uses
+var keyAliases = map[string]string{
+	"space":      " ",
+	"return":     "enter",
+	"escape":     "esc",
+	"del":        "delete",
+	"pageup":     "pgup",
+	"pagedown":   "pgdown",
+	"arrowup":    "up",
+	"arrowdown":  "down",
+	"arrowleft":  "left",
+	"arrowright": "right",
+}
+
+func suggestKey(key string) (string, bool) {
+	alt := ""
+	if strings.HasPrefix(strings.ToLower(key), "alt+") {
+		alt, key = "alt+", key[len("alt+"):]
+	}
+
+	lower := strings.ToLower(key)
+	if alias, ok := keyAliases[strings.NewReplacer("_", "", "-", "", " ", "").Replace(lower)]; ok {
+		return alt + alias, true
+	}
+	if validKey(lower) {
+		return alt + lower, true
+	}
+	return "", false
+}
@@ -0,0 +1,45 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "example/controllers", "example/app")
+}
+
+func TestValidKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"enter":     true,
+		"alt+enter": true,
+		"ctrl+c":    true,
+		" ":         true,
+		"é":         true,
+		"Enter":     false,
+		"space":     false,
+		"ctrl+C":    false,
+		"ab":        false,
+		"":          false,
+	} {
+		if got := validKey(key); got != want {
+			t.Errorf("validKey(%q): expected %v, got %v", key, want, got)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var out strings.Builder
+	n, err := Check(analysistest.TestData(), &out)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if n != strings.Count(out.String(), "\n") || n == 0 {
+		t.Errorf("expected one line per diagnostic, got %d for:\n%s", n, out.String())
+	}
+	if !strings.Contains(out.String(), `key "Enter" is never produced`) {
+		t.Errorf("expected the Enter key to be reported, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,523 @@
+package lint
+
+import (
+	"go/ast"
+	"go/types"
+	"maps"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// RouteTable is the route table of an application as Routes finds it in the
+// source
+type RouteTable struct {
+	DefaultRoute string  `json:"defaultRoute,omitempty"`
+	Routes       []Route `json:"routes"`
+	// Wizards are the paths wizards register their steps under
+	Wizards     []string     `json:"wizards,omitempty"`
+	Middleware  []Middleware `json:"middleware,omitempty"`
+	Navigations []Navigation `json:"navigations,omitempty"`
+}
+
+// Route is a route registered with RegisterController or a wizard step
+type Route struct {
+	Path string `json:"path"`
+	// Controller is the controller type, or the registered expression when
+	// its type can't be found
+	Controller string `json:"controller"`
+	// Wizard is the path of the wizard the route is a step of
+	Wizard string `json:"wizard,omitempty"`
+	// Keys are bound in the methods of the controller type
+	Keys     []Binding `json:"keys,omitempty"`
+	Position string    `json:"position"`
+}
+
+// Binding is a key binding of a controller
+type Binding struct {
+	Keys []string `json:"keys"`
+	Help string   `json:"help,omitempty"`
+}
+
+// Middleware is added with Use and runs on every navigation
+type Middleware struct {
+	Name string `json:"name"`
+	// Routes are routes passed to the middleware, like the protected routes
+	// of AuthMiddleware
+	Routes   []string `json:"routes,omitempty"`
+	Position string   `json:"position"`
+}
+
+// Navigation is a Navigate call, a NavigateMsg or a wizard moving on
+type Navigation struct {
+	// From is a route of the controller navigating, empty for code outside
+	// registered controllers
+	From string `json:"from,omitempty"`
+	// Source is the controller type or function navigating
+	Source   string `json:"source"`
+	To       string `json:"to"`
+	Position string `json:"position"`
+}
+
+// Routes loads the packages matching patterns from dir, default ./..., and
+// collects their RegisterController, Use, key binding and Navigate calls.
+// Routes are resolved like the analyzer resolves them, routes that can't be
+// resolved are shown as their expression
+func Routes(dir string, patterns ...string) (*RouteTable, error) {
+	pkgs, err := load(dir, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &routeCollector{
+		constants: map[string]string{},
+		aliases:   map[*types.Var]*types.Var{},
+		funcs:     map[*types.Func]funcSource{},
+		bindings:  map[*types.TypeName][]Binding{},
+		completes: map[*ast.FuncDecl]foundNavigation{},
+	}
+	for _, pkg := range pkgs {
+		maps.Copy(c.constants, structConstants(pkg.Types, pkg.TypesInfo, pkg.Syntax))
+		maps.Copy(c.aliases, localAliases(pkg.TypesInfo, pkg.Syntax))
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok && fd.Body != nil {
+					if fn, ok := pkg.TypesInfo.Defs[fd.Name].(*types.Func); ok {
+						c.funcs[fn] = funcSource{decl: fd, pkg: pkg}
+					}
+				}
+			}
+		}
+	}
+
+	for _, pkg := range pkgs {
+		mvct := lookupMvct(pkg.Types)
+		if mvct == nil || mvct == pkg.Types {
+			continue
+		}
+		c.mvct = mvct
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok && fd.Body != nil {
+					c.collect(pkg, fd)
+				}
+			}
+		}
+	}
+	return c.build(), nil
+}
+
+// funcSource is a function declared in the loaded packages
+type funcSource struct {
+	decl *ast.FuncDecl
+	pkg  *packages.Package
+}
+
+type routeCollector struct {
+	mvct      *types.Package
+	constants map[string]string
+	aliases   map[*types.Var]*types.Var
+	funcs     map[*types.Func]funcSource
+
+	table RouteTable
+	// types are the controller types of table.Routes
+	types       []*types.TypeName
+	bindings    map[*types.TypeName][]Binding
+	navigations []foundNavigation
+	wizards     []foundWizard
+	steps       []foundStep
+	// completes are the OnComplete calls of the wizards created in a
+	// function
+	completes map[*ast.FuncDecl]foundNavigation
+}
+
+type foundNavigation struct {
+	owner    *types.TypeName
+	source   string
+	to       string
+	position string
+}
+
+type foundWizard struct {
+	decl *ast.FuncDecl
+	path string
+}
+
+type foundStep struct {
+	decl       *ast.FuncDecl
+	name       string
+	controller *types.TypeName
+	expr       string
+	position   string
+}
+
+// routeScope is the function calls are collected from, key bindings and
+// navigations belong to the type of its receiver
+type routeScope struct {
+	*routeCollector
+	pkg    *packages.Package
+	decl   *ast.FuncDecl
+	owner  *types.TypeName
+	source string
+	// help holds the descriptions of Key calls, Help is visited first
+	help map[*ast.CallExpr]string
+}
+
+func (c *routeCollector) collect(pkg *packages.Package, fd *ast.FuncDecl) {
+	s := &routeScope{
+		routeCollector: c,
+		pkg:            pkg,
+		decl:           fd,
+		source:         pkg.Name + "." + fd.Name.Name,
+		help:           map[*ast.CallExpr]string{},
+	}
+	if fd.Recv != nil && len(fd.Recv.List) == 1 {
+		if named := namedOf(pkg.TypesInfo.TypeOf(fd.Recv.List[0].Type)); named != nil {
+			s.owner = named.Obj()
+			s.source = qualifiedName(s.owner)
+		}
+	}
+
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			s.call(n)
+		case *ast.CompositeLit:
+			s.compositeLit(n)
+		case *ast.IndexExpr:
+			s.index(n)
+		}
+		return true
+	})
+}
+
+func (s *routeScope) position(n ast.Node) string {
+	return s.pkg.Fset.Position(n.Pos()).String()
+}
+
+// route resolves a route, falling back to its expression
+func (s *routeScope) route(expr ast.Expr) (string, bool) {
+	route, ok := resolveRoute(s.pkg.TypesInfo, s.aliases, expr, func(_ *types.Package, key string) (string, bool) {
+		value, ok := s.constants[key]
+		return value, ok
+	})
+	if !ok {
+		return types.ExprString(expr), false
+	}
+	return route, true
+}
+
+func (s *routeScope) call(call *ast.CallExpr) {
+	fn := typeutil.StaticCallee(s.pkg.TypesInfo, call)
+	if fn == nil || fn.Pkg() != s.mvct {
+		return
+	}
+	isMethod := fn.Type().(*types.Signature).Recv() != nil
+
+	switch {
+	case (fn.Name() == "Navigate" && len(call.Args) == 1 || fn.Name() == "NavigateWith" && len(call.Args) == 2) && !isMethod:
+		s.navigate(call.Args[0])
+
+	case (fn.Name() == "RegisterController" || fn.Name() == "Register") && isMethod && len(call.Args) == 2:
+		path, _ := s.route(call.Args[0])
+		typ := s.controllerType(s.pkg, call.Args[1], 0)
+		s.table.Routes = append(s.table.Routes, Route{
+			Path:       path,
+			Controller: controllerName(typ, types.ExprString(call.Args[1])),
+			Position:   s.position(call),
+		})
+		s.types = append(s.types, typ)
+
+	case fn.Name() == "Use" && isMethod && len(call.Args) == 1:
+		middleware := Middleware{Name: types.ExprString(call.Args[0]), Position: s.position(call)}
+		if inner, ok := ast.Unparen(call.Args[0]).(*ast.CallExpr); ok {
+			for _, arg := range inner.Args {
+				middleware.Routes = append(middleware.Routes, s.routeList(arg)...)
+			}
+		}
+		s.table.Middleware = append(s.table.Middleware, middleware)
+
+	case fn.Name() == "Key" && !isMethod:
+		binding := Binding{Help: s.help[call]}
+		for _, arg := range call.Args {
+			if key, ok := constString(s.pkg.TypesInfo, arg); ok {
+				binding.Keys = append(binding.Keys, key)
+			}
+		}
+		s.bind(binding)
+
+	case fn.Name() == "Help" && isMethod && len(call.Args) == 1:
+		help, ok := constString(s.pkg.TypesInfo, call.Args[0])
+		if key := s.keyCall(call); ok && key != nil {
+			s.help[key] = help
+		}
+
+	case fn.Name() == "NewWizard" && !isMethod && len(call.Args) == 1:
+		path, _ := s.route(call.Args[0])
+		s.wizards = append(s.wizards, foundWizard{decl: s.decl, path: path})
+
+	case fn.Name() == "OnComplete" && isMethod && len(call.Args) == 1:
+		route, _ := s.route(call.Args[0])
+		s.completes[s.decl] = foundNavigation{to: route, position: s.position(call.Args[0])}
+	}
+}
+
+// keyCall returns the Key call a chain like Key("a").To(fn).Help("add")
+// starts with
+func (s *routeScope) keyCall(call *ast.CallExpr) *ast.CallExpr {
+	for {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return nil
+		}
+		inner, ok := ast.Unparen(sel.X).(*ast.CallExpr)
+		if !ok {
+			return nil
+		}
+		fn := typeutil.StaticCallee(s.pkg.TypesInfo, inner)
+		if fn == nil || fn.Pkg() != s.mvct {
+			return nil
+		}
+		if fn.Name() == "Key" {
+			return inner
+		}
+		call = inner
+	}
+}
+
+// routeList resolves a []string literal of routes
+func (s *routeScope) routeList(expr ast.Expr) []string {
+	lit, ok := ast.Unparen(expr).(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	var routes []string
+	for _, elt := range lit.Elts {
+		if route, ok := s.route(elt); ok {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+func (s *routeScope) navigate(expr ast.Expr) {
+	to, _ := s.route(expr)
+	s.navigations = append(s.navigations, foundNavigation{
+		owner:    s.owner,
+		source:   s.source,
+		to:       to,
+		position: s.position(expr),
+	})
+}
+
+func (s *routeScope) bind(binding Binding) {
+	if s.owner == nil || len(binding.Keys) == 0 {
+		return
+	}
+	for _, b := range s.bindings[s.owner] {
+		if slices.Equal(b.Keys, binding.Keys) && b.Help == binding.Help {
+			return
+		}
+	}
+	s.bindings[s.owner] = append(s.bindings[s.owner], binding)
+}
+
+func (s *routeScope) compositeLit(lit *ast.CompositeLit) {
+	named := namedOf(s.pkg.TypesInfo.TypeOf(lit))
+	if named == nil || named.Obj().Pkg() != s.mvct {
+		return
+	}
+
+	step := foundStep{decl: s.decl, position: s.position(lit)}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		switch named.Obj().Name() + "." + key.Name {
+		case "NavigateMsg.Route":
+			s.navigate(kv.Value)
+		case "Config.DefaultRoute":
+			s.table.DefaultRoute, _ = s.route(kv.Value)
+		case "WizardStep.Name":
+			step.name, _ = constString(s.pkg.TypesInfo, kv.Value)
+		case "WizardStep.Controller":
+			step.controller = s.controllerType(s.pkg, kv.Value, 0)
+			step.expr = types.ExprString(kv.Value)
+		}
+	}
+	if named.Obj().Name() == "WizardStep" && step.name != "" {
+		s.steps = append(s.steps, step)
+	}
+}
+
+// index records the keys of KeyHandlers maps
+func (s *routeScope) index(expr *ast.IndexExpr) {
+	m, ok := s.pkg.TypesInfo.TypeOf(expr.X).Underlying().(*types.Map)
+	if !ok || !types.Identical(m.Elem(), s.mvct.Scope().Lookup("KeyMsgHandler").Type()) {
+		return
+	}
+	if key, ok := constString(s.pkg.TypesInfo, expr.Index); ok {
+		s.bind(Binding{Keys: []string{key}})
+	}
+}
+
+// controllerType finds the controller type of an expression. Controllers
+// returned as an interface or wrapped, like by mvct.Load, are found in the
+// arguments of the call and in the return statements of the function
+// called
+func (c *routeCollector) controllerType(pkg *packages.Package, expr ast.Expr, depth int) *types.TypeName {
+	if depth > 3 {
+		return nil
+	}
+	if named := namedOf(pkg.TypesInfo.TypeOf(expr)); named != nil && c.isController(named) {
+		return named.Obj()
+	}
+
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	for _, arg := range call.Args {
+		if typ := c.controllerType(pkg, arg, depth+1); typ != nil {
+			return typ
+		}
+	}
+
+	fn := typeutil.StaticCallee(pkg.TypesInfo, call)
+	source, ok := c.funcs[fn]
+	if !ok {
+		return nil
+	}
+	var found *types.TypeName
+	ast.Inspect(source.decl.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			// its returns are not the function's
+			return false
+		case *ast.ReturnStmt:
+			for _, result := range n.Results {
+				if found == nil {
+					found = c.controllerType(source.pkg, result, depth+1)
+				}
+			}
+		}
+		return found == nil
+	})
+	return found
+}
+
+// isController reports whether named is a controller type of the
+// application, not one of mvct like Loader
+func (c *routeCollector) isController(named *types.Named) bool {
+	if named.Obj().Pkg() == nil || named.Obj().Pkg() == c.mvct || types.IsInterface(named) {
+		return false
+	}
+	controller := c.mvct.Scope().Lookup("Controller").Type().Underlying().(*types.Interface)
+	return types.Implements(named, controller) || types.Implements(types.NewPointer(named), controller)
+}
+
+// build resolves the controllers of navigations to their routes and adds
+// the steps of wizards
+func (c *routeCollector) build() *RouteTable {
+	table := c.table
+
+	// wizards created in a function own the steps created in it
+	wizardPaths := map[string]string{}
+	for _, wizard := range c.wizards {
+		table.Wizards = append(table.Wizards, wizard.path)
+		var steps []string
+		for _, step := range c.steps {
+			if step.decl != wizard.decl {
+				continue
+			}
+			path := wizard.path + "/" + step.name
+			table.Routes = append(table.Routes, Route{
+				Path:       path,
+				Controller: controllerName(step.controller, step.expr),
+				Wizard:     wizard.path,
+				Position:   step.position,
+			})
+			c.types = append(c.types, step.controller)
+			if len(steps) > 0 {
+				table.Navigations = append(table.Navigations, Navigation{From: steps[len(steps)-1], Source: "wizard " + wizard.path, To: path, Position: step.position})
+			}
+			steps = append(steps, path)
+		}
+		if len(steps) == 0 {
+			continue
+		}
+		wizardPaths[wizard.path] = steps[0]
+		if complete, ok := c.completes[wizard.decl]; ok {
+			table.Navigations = append(table.Navigations, Navigation{From: steps[len(steps)-1], Source: "wizard " + wizard.path, To: complete.to, Position: complete.position})
+		}
+	}
+	for i := range table.Navigations {
+		if first, ok := wizardPaths[table.Navigations[i].To]; ok {
+			table.Navigations[i].To = first
+		}
+	}
+
+	for i := range table.Routes {
+		if typ := c.types[i]; typ != nil {
+			table.Routes[i].Keys = c.bindings[typ]
+		}
+	}
+
+	for _, nav := range c.navigations {
+		to := nav.to
+		if first, ok := wizardPaths[to]; ok {
+			to = first
+		}
+		from := []string{""}
+		if nav.owner != nil {
+			var routes []string
+			for i, typ := range c.types {
+				if typ == nav.owner {
+					routes = append(routes, table.Routes[i].Path)
+				}
+			}
+			if len(routes) > 0 {
+				from = routes
+			}
+		}
+		for _, route := range from {
+			table.Navigations = append(table.Navigations, Navigation{From: route, Source: nav.source, To: to, Position: nav.position})
+		}
+	}
+	return &table
+}
+
+// namedOf returns the named type of t or of the type t points to
+func namedOf(t types.Type) *types.Named {
+	if t == nil {
+		return nil
+	}
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, _ := types.Unalias(t).(*types.Named)
+	return named
+}
+
+func qualifiedName(obj *types.TypeName) string {
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return obj.Pkg().Name() + "." + obj.Name()
+}
+
+// controllerName names a controller by its type, or by its expression when
+// the type isn't known
+func controllerName(typ *types.TypeName, expr string) string {
+	if typ != nil {
+		return qualifiedName(typ)
+	}
+	return expr
+}
@@ -0,0 +1,272 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// anyRoute names the source of navigations outside registered controllers
+// in graphs
+const anyRoute = "any route"
+
+// Formats are the formats Write supports
+var Formats = []string{"text", "json", "dot", "mermaid"}
+
+// Write writes the table in one of Formats
+func (t *RouteTable) Write(w io.Writer, format string) error {
+	switch format {
+	case "text", "":
+		return t.WriteText(w)
+	case "json":
+		return t.WriteJSON(w)
+	case "dot":
+		return t.WriteDot(w)
+	case "mermaid":
+		return t.WriteMermaid(w)
+	}
+	return fmt.Errorf("unknown format %q, use one of %s", format, strings.Join(Formats, ", "))
+}
+
+// WriteText writes the routes as a table followed by the middleware and the
+// navigations that don't come from a route
+func (t *RouteTable) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROUTE\tCONTROLLER\tKEYS\tNAVIGATES TO")
+	for _, route := range t.Routes {
+		path := route.Path
+		if path == t.DefaultRoute {
+			path += " (default)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", path, route.Controller, keysText(route.Keys), strings.Join(t.targets(route.Path), ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(t.Middleware) > 0 {
+		fmt.Fprintln(w, "\nMiddleware, in the order it runs:")
+		for _, middleware := range t.Middleware {
+			fmt.Fprintf(w, "  %s", middleware.Name)
+			if len(middleware.Routes) > 0 {
+				fmt.Fprintf(w, " (%s)", strings.Join(middleware.Routes, ", "))
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	var global []string
+	for _, nav := range t.Navigations {
+		if nav.From == "" {
+			global = append(global, fmt.Sprintf("  %s -> %s", nav.Source, nav.To))
+		}
+	}
+	if len(global) > 0 {
+		fmt.Fprintln(w, "\nNavigation outside controllers:")
+		fmt.Fprintln(w, strings.Join(global, "\n"))
+	}
+
+	if missing := t.unregistered(); len(missing) > 0 {
+		fmt.Fprintln(w, "\nNavigated to but never registered:")
+		for _, nav := range t.Navigations {
+			if slices.Contains(missing, nav.To) {
+				fmt.Fprintf(w, "  %s at %s\n", nav.To, nav.Position)
+			}
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the table as indented JSON
+func (t *RouteTable) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t)
+}
+
+// WriteDot writes the navigation graph in the Graphviz dot language. The
+// default route has a double border, routes that are never registered are
+// dashed and wizard steps are grouped in a cluster
+func (t *RouteTable) WriteDot(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph routes {\n\trankdir=LR;\n\tnode [shape=box];\n")
+
+	wizards := map[string][]Route{}
+	var order []string
+	for _, route := range t.Routes {
+		if route.Wizard == "" {
+			fmt.Fprintf(&b, "\t%s;\n", dotNode(route, t.DefaultRoute))
+			continue
+		}
+		if _, ok := wizards[route.Wizard]; !ok {
+			order = append(order, route.Wizard)
+		}
+		wizards[route.Wizard] = append(wizards[route.Wizard], route)
+	}
+	for i, wizard := range order {
+		fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n\t\tlabel=%q;\n", i, wizard)
+		for _, route := range wizards[wizard] {
+			fmt.Fprintf(&b, "\t\t%s;\n", dotNode(route, t.DefaultRoute))
+		}
+		b.WriteString("\t}\n")
+	}
+	for _, route := range t.unregistered() {
+		fmt.Fprintf(&b, "\t%q [style=dashed];\n", route)
+	}
+	if t.hasGlobal() {
+		fmt.Fprintf(&b, "\t%q [shape=plaintext];\n", anyRoute)
+	}
+
+	for _, edge := range t.edges() {
+		from := edge[0]
+		if from == "" {
+			fmt.Fprintf(&b, "\t%q -> %q [style=dashed];\n", anyRoute, edge[1])
+			continue
+		}
+		fmt.Fprintf(&b, "\t%q -> %q;\n", from, edge[1])
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotNode(route Route, defaultRoute string) string {
+	attrs := fmt.Sprintf("label=%q", route.Path+"\n"+route.Controller)
+	if route.Path == defaultRoute {
+		attrs += ", peripheries=2"
+	}
+	return fmt.Sprintf("%q [%s]", route.Path, attrs)
+}
+
+// WriteMermaid writes the navigation graph as a Mermaid flowchart, styled
+// like WriteDot
+func (t *RouteTable) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	ids := map[string]string{}
+	id := func(route string) string {
+		if _, ok := ids[route]; !ok {
+			ids[route] = fmt.Sprintf("r%d", len(ids))
+		}
+		return ids[route]
+	}
+	node := func(route Route) string {
+		return fmt.Sprintf("%s[\"%s<br/>%s\"]", id(route.Path), mermaidText(route.Path), mermaidText(route.Controller))
+	}
+
+	wizard := ""
+	for _, route := range t.Routes {
+		if route.Wizard != wizard {
+			if wizard != "" {
+				b.WriteString("\tend\n")
+			}
+			if route.Wizard != "" {
+				fmt.Fprintf(&b, "\tsubgraph %s[\"%s\"]\n", id("wizard "+route.Wizard), mermaidText(route.Wizard))
+			}
+			wizard = route.Wizard
+		}
+		indent := "\t"
+		if wizard != "" {
+			indent = "\t\t"
+		}
+		fmt.Fprintf(&b, "%s%s\n", indent, node(route))
+	}
+	if wizard != "" {
+		b.WriteString("\tend\n")
+	}
+	missing := t.unregistered()
+	for _, route := range missing {
+		fmt.Fprintf(&b, "\t%s[\"%s\"]\n", id(route), mermaidText(route))
+	}
+	if t.hasGlobal() {
+		fmt.Fprintf(&b, "\t%s([\"%s\"])\n", id(""), anyRoute)
+	}
+
+	for _, edge := range t.edges() {
+		arrow := "-->"
+		if edge[0] == "" {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "\t%s %s %s\n", id(edge[0]), arrow, id(edge[1]))
+	}
+
+	if t.DefaultRoute != "" && ids[t.DefaultRoute] != "" {
+		fmt.Fprintf(&b, "\tclassDef start stroke-width:3px\n\tclass %s start\n", ids[t.DefaultRoute])
+	}
+	if len(missing) > 0 {
+		var missingIDs []string
+		for _, route := range missing {
+			missingIDs = append(missingIDs, ids[route])
+		}
+		fmt.Fprintf(&b, "\tclassDef unregistered stroke-dasharray:5 5\n\tclass %s unregistered\n", strings.Join(missingIDs, ","))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mermaidText(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+// targets returns the routes navigated to from route
+func (t *RouteTable) targets(route string) []string {
+	var targets []string
+	for _, nav := range t.Navigations {
+		if nav.From == route && !slices.Contains(targets, nav.To) {
+			targets = append(targets, nav.To)
+		}
+	}
+	return targets
+}
+
+// edges returns the distinct from and to routes of the navigations
+func (t *RouteTable) edges() [][2]string {
+	var edges [][2]string
+	for _, nav := range t.Navigations {
+		edge := [2]string{nav.From, nav.To}
+		if !slices.Contains(edges, edge) {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+func (t *RouteTable) hasGlobal() bool {
+	return slices.ContainsFunc(t.Navigations, func(nav Navigation) bool { return nav.From == "" })
+}
+
+// unregistered returns the routes navigated to that no route or wizard
+// matches
+func (t *RouteTable) unregistered() []string {
+	var missing []string
+	for _, nav := range t.Navigations {
+		registered := slices.ContainsFunc(t.Routes, func(route Route) bool { return route.Path == nav.To }) ||
+			slices.ContainsFunc(t.Wizards, func(wizard string) bool { return strings.HasPrefix(nav.To, wizard+"/") })
+		if !registered && !slices.Contains(missing, nav.To) {
+			missing = append(missing, nav.To)
+		}
+	}
+	return missing
+}
+
+// keysText lists the keys of bindings, the keys of one binding joined by /
+func keysText(bindings []Binding) string {
+	var keys []string
+	for _, binding := range bindings {
+		names := slices.Clone(binding.Keys)
+		for i, name := range names {
+			if name == " " {
+				names[i] = "space"
+			}
+		}
+		key := strings.Join(names, "/")
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return strings.Join(keys, ", ")
+}
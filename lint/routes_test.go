@@ -0,0 +1,115 @@
+package lint
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestRoutes(t *testing.T) {
+	table, err := Routes(analysistest.TestData(), "./...")
+	if err != nil {
+		t.Fatalf("Routes failed: %v", err)
+	}
+
+	if table.DefaultRoute != "/home" {
+		t.Errorf("expected default route /home, got %q", table.DefaultRoute)
+	}
+	if len(table.Routes) != 1 {
+		t.Fatalf("expected 1 route, got %d", len(table.Routes))
+	}
+	home := table.Routes[0]
+	if home.Path != "/home" || home.Controller != "controllers.HomeController" {
+		t.Errorf("expected /home on controllers.HomeController, got %s on %s", home.Path, home.Controller)
+	}
+	if keys := keysText(home.Keys); !strings.Contains(keys, "enter") || !strings.Contains(keys, "up/K/PgUp") {
+		t.Errorf("expected the handler and Bind keys, got %q", keys)
+	}
+	if !slices.Equal(table.Wizards, []string{"/setup"}) {
+		t.Errorf("expected the /setup wizard, got %v", table.Wizards)
+	}
+	if len(table.Middleware) != 1 || table.Middleware[0].Name != "mvct.LoggingMiddleware()" {
+		t.Errorf("expected the logging middleware, got %+v", table.Middleware)
+	}
+
+	if targets := table.targets("/home"); !slices.Equal(targets, []string{"/settings", "/setings", "/home"}) {
+		t.Errorf("expected /home to navigate to /settings, /setings and /home, got %v", targets)
+	}
+	// R is a local copy of controllers.R in main
+	if !slices.ContainsFunc(table.Navigations, func(nav Navigation) bool {
+		return nav.From == "" && nav.Source == "main.main" && nav.To == "/settings/profile"
+	}) {
+		t.Errorf("expected main.main to navigate to /settings/profile, got %+v", table.Navigations)
+	}
+	if missing := table.unregistered(); !slices.Equal(missing, []string{"/settings", "/setings", "/missing", "/settings/profile"}) {
+		t.Errorf("expected the wizard step to be registered, got unregistered %v", missing)
+	}
+}
+
+func TestRouteTableWrite(t *testing.T) {
+	table := &RouteTable{
+		DefaultRoute: "/home",
+		Routes: []Route{
+			{Path: "/home", Controller: "controllers.HomeController", Keys: []Binding{{Keys: []string{"enter"}}, {Keys: []string{" "}}}},
+			{Path: "/setup/name", Controller: "controllers.NameStep", Wizard: "/setup"},
+		},
+		Wizards: []string{"/setup"},
+		Navigations: []Navigation{
+			{From: "/home", To: "/setup/name"},
+			{From: "/setup/name", To: "/done"},
+			{Source: "controllers.QuitHandler", To: "/home"},
+		},
+	}
+
+	for format, want := range map[string][]string{
+		"text": {
+			"/home (default)  controllers.HomeController  enter, space  /setup/name",
+			"controllers.QuitHandler -> /home",
+			"Navigated to but never registered:\n  /done",
+		},
+		"dot": {
+			`"/home" [label="/home\ncontrollers.HomeController", peripheries=2];`,
+			"subgraph cluster_0 {\n\t\tlabel=\"/setup\";",
+			`"/done" [style=dashed];`,
+			`"any route" -> "/home" [style=dashed];`,
+			`"/home" -> "/setup/name";`,
+		},
+		"mermaid": {
+			"flowchart LR\n\tr0[\"/home<br/>controllers.HomeController\"]",
+			"\tsubgraph r1[\"/setup\"]\n\t\tr2[",
+			"r0 --> r2",
+			"r4 -.-> r0",
+			"class r0 start",
+			"class r3 unregistered",
+		},
+	} {
+		var out strings.Builder
+		if err := table.Write(&out, format); err != nil {
+			t.Fatalf("Write %s failed: %v", format, err)
+		}
+		for _, s := range want {
+			if !strings.Contains(out.String(), s) {
+				t.Errorf("expected %s output to contain %q, got:\n%s", format, s, out.String())
+			}
+		}
+	}
+
+	var out strings.Builder
+	if err := table.Write(&out, "json"); err != nil {
+		t.Fatalf("Write json failed: %v", err)
+	}
+	var decoded RouteTable
+	if err := json.Unmarshal([]byte(out.String()), &decoded); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+	if len(decoded.Routes) != 2 || decoded.Routes[1].Wizard != "/setup" {
+		t.Errorf("expected the routes to round trip, got %+v", decoded.Routes)
+	}
+
+	if err := table.Write(&out, "svg"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,24 @@
+package main // want package:"routes\\(1 registered, 4 navigations\\)"
+
+import (
+	"example/controllers"
+
+	"github.com/michael-duren/mvct"
+)
+
+func main() {
+	app := mvct.NewApplication(mvct.Config{DefaultRoute: controllers.R.Home}, struct{}{})
+	app.RegisterController(controllers.R.Home, &controllers.HomeController{}) // want `route "/setings" navigated to at .*controllers.go:\d+:\d+ is never registered` `route "/settings" navigated to at .* is never registered`
+	app.UseGlobalHandler(mvct.QuitHandler("ctrl+c", "Q", "Esc"))              // want `did you mean "esc"\?`
+
+	wizard := mvct.NewWizard("/setup")
+	app.RegisterWizard(wizard)
+
+	app.Update(mvct.NavigateMsg{Route: "/setup/account"})
+	app.Update(mvct.NavigateMsg{Route: "/missing"}) // want `route "/missing" is never registered`
+
+	R := controllers.R
+	app.Update(mvct.NavigateMsg{Route: R.Settings + "/profile"}) // want `route "/settings/profile" is never registered`
+	app.Use(mvct.LoggingMiddleware())
+	app.Run()
+}
@@ -0,0 +1,77 @@
+package controllers // want package:"routes\\(0 registered, 3 navigations\\)"
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-duren/mvct"
+)
+
+var R = struct {
+	Home     string
+	Settings string
+}{
+	Home:     "/home",
+	Settings: "/settings",
+}
+
+type savedMsg struct{}
+
+type HomeController struct{}
+
+func (c *HomeController) Init(handlers mvct.KeyHandlers) mvct.Cmd {
+	handlers["enter"] = c.onOpen
+	handlers["Enter"] = c.onOpen  // want `key "Enter" is never produced by tea.KeyMsg.String, did you mean "enter"\?`
+	handlers["ctrl+S"] = c.onOpen // want `key "ctrl\+S" is never produced by tea.KeyMsg.String, did you mean "ctrl\+s"\?`
+	handlers["space"] = c.onOpen  // want `did you mean " "\?`
+	handlers["alt+x"] = c.onOpen
+	handlers["G"] = c.onOpen
+	mvct.Bind(handlers, func() bool { return true },
+		mvct.Key("up", "K", "PgUp").To(c.onOpen), // want `key "PgUp" is never produced by tea.KeyMsg.String, did you mean "pgup"\?`
+	)
+	return nil
+}
+
+func (c *HomeController) View() string { return "home" }
+
+func (c *HomeController) onOpen(msg mvct.KeyMsg) mvct.Cmd {
+	switch msg.String() {
+	case "tab", "Tab": // want `did you mean "tab"\?`
+		return mvct.Navigate(R.Settings)
+	}
+	if msg.String() == "Backspace" { // want `did you mean "backspace"\?`
+		return mvct.Navigate("/setings")
+	}
+	return mvct.Navigate(R.Home)
+}
+
+func (c *HomeController) OnSavedMsg(msg savedMsg) mvct.Cmd { return nil }
+
+func (c *HomeController) OnPair(a, b savedMsg) mvct.Cmd { return nil } // want `OnPair has 2 parameters`
+
+func (c *HomeController) OnTea(msg savedMsg) tea.Cmd { return nil } // want `OnTea returns tea.Cmd, On\* handlers must return mvct.Cmd`
+
+func (c *HomeController) OnNothing(msg savedMsg) {} // want `OnNothing must return a single mvct.Cmd`
+
+// ListController configures its selection with fluent On* builders, which
+// are not handlers
+type ListController struct {
+	onSelect func(index int) mvct.Cmd
+	filter   string
+}
+
+func (c *ListController) Init(handlers mvct.KeyHandlers) mvct.Cmd { return nil }
+func (c *ListController) View() string                            { return "list" }
+
+func (c *ListController) OnSelect(fn func(index int) mvct.Cmd) *ListController {
+	c.onSelect = fn
+	return c
+}
+
+func (c *ListController) OnFilter(filter string) *ListController {
+	c.filter = filter
+	return c
+}
+
+// helper is not a controller, its On* methods are never dispatched
+type helper struct{}
+
+func (h *helper) OnPair(a, b savedMsg) {}
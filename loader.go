@@ -0,0 +1,328 @@
+package mvct
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// LoadState is the state of a route loader
+type LoadState int
+
+const (
+	Loading LoadState = iota
+	LoadFailed
+	Loaded
+)
+
+func (s LoadState) String() string {
+	switch s {
+	case Loading:
+		return "loading"
+	case LoadFailed:
+		return "failed"
+	case Loaded:
+		return "loaded"
+	}
+	return fmt.Sprintf("state(%d)", int(s))
+}
+
+// DataController is a controller whose data is fetched by a Loader. Loaded
+// is called with the data before Init and again whenever the data is
+// revalidated while the route is active
+type DataController[T any] interface {
+	Controller
+	Loaded(data T) Cmd
+}
+
+// LoaderConfig configures caching and the views shown while loading
+type LoaderConfig struct {
+	// TTL keeps loaded data, entering the route again within it skips the
+	// loader. Zero loads every time the route is entered
+	TTL time.Duration
+	// Revalidate shows expired data straight away and loads fresh data in
+	// the background instead of showing the loading view
+	Revalidate bool
+	// Timeout cancels the loader after the duration, zero waits until the
+	// route is left
+	Timeout time.Duration
+	// RetryKeys retry a failed load, "r" by default. Otherwise the keys
+	// reach the handlers bound to them, like the controller's own
+	RetryKeys []string
+	// LoadingView renders the route while loading, a muted "Loading…" by
+	// default
+	LoadingView func() string
+	// ErrorView renders the route after the loader failed
+	ErrorView func(err error) string
+}
+
+// ReloadMsg makes the loader of the active route fetch its data again
+type ReloadMsg struct{}
+
+// Reload fetches the data of the active route again. Loaded data stays on
+// screen while it reloads
+func Reload() Cmd {
+	return func() Msg {
+		return Msg{
+			Inner: ReloadMsg{},
+		}
+	}
+}
+
+// Loader is a controller that runs a load function when its route is
+// entered and shows the wrapped controller once the data is ready
+type Loader[T any] struct {
+	load   func(ctx context.Context, route *Context) (T, error)
+	ctlr   DataController[T]
+	config LoaderConfig
+	// route is the navigation that entered the route
+	route *Context
+
+	state    LoadState
+	err      error
+	data     T
+	loadedAt time.Time
+	hasData  bool
+
+	// handlers is the key handler map of the current activation, the
+	// wrapped controller is initialized with it once the data is ready
+	handlers    KeyHandlers
+	initialized bool
+	cancel      context.CancelFunc
+	generation  int
+}
+
+// loaderResultMsg carries the result of a load function into Update
+type loaderResultMsg[T any] struct {
+	loader     *Loader[T]
+	generation int
+	data       T
+	err        error
+}
+
+// Load wraps a controller with a loader. The load function gets a context
+// that is cancelled when the route is left
+func Load[T any](load func(ctx context.Context) (T, error), ctlr DataController[T], config LoaderConfig) *Loader[T] {
+	return LoadRoute(func(ctx context.Context, _ *Context) (T, error) {
+		return load(ctx)
+	}, ctlr, config)
+}
+
+// LoadRoute is Load for load functions that depend on the navigation, like
+// the id in the Data of a NavigateWith or the params of a deep link
+func LoadRoute[T any](load func(ctx context.Context, route *Context) (T, error), ctlr DataController[T], config LoaderConfig) *Loader[T] {
+	if len(config.RetryKeys) == 0 {
+		config.RetryKeys = []string{"r"}
+	}
+	return &Loader[T]{load: load, ctlr: ctlr, config: config}
+}
+
+// State returns the load state of the route
+func (l *Loader[T]) State() LoadState {
+	return l.state
+}
+
+// Data returns the last loaded data
+func (l *Loader[T]) Data() (T, bool) {
+	return l.data, l.hasData
+}
+
+// Controller returns the wrapped controller
+func (l *Loader[T]) Controller() DataController[T] {
+	return l.ctlr
+}
+
+func (l *Loader[T]) Init(handlers KeyHandlers) Cmd {
+	l.handlers = handlers
+	l.initialized = false
+	// the retry keys fall through to the handlers already bound to them
+	// unless the load failed
+	Bind(handlers, func() bool { return l.state == LoadFailed }, Key(l.config.RetryKeys...).To(l.onRetry))
+
+	fresh := l.hasData && l.config.TTL > 0 && time.Since(l.loadedAt) < l.config.TTL
+	switch {
+	case fresh:
+		slog.Debug("Using cached route data", "loaded_at", l.loadedAt)
+		return l.ready()
+	case l.hasData && l.config.Revalidate:
+		slog.Debug("Revalidating route data", "loaded_at", l.loadedAt)
+		return Batch(l.ready(), l.start())
+	}
+
+	l.state = Loading
+	return l.start()
+}
+
+func (l *Loader[T]) View() string {
+	switch l.state {
+	case Loading:
+		if l.config.LoadingView != nil {
+			return l.config.LoadingView()
+		}
+		return CurrentTheme().Style(CurrentTheme().Muted).Render("Loading…")
+	case LoadFailed:
+		if l.config.ErrorView != nil {
+			return l.config.ErrorView(l.err)
+		}
+		theme := CurrentTheme()
+		return theme.Style(theme.Error).Render("Failed to load: "+l.err.Error()) +
+			"\n\n" + theme.Style(theme.Muted).Render(fmt.Sprintf("%s: retry", l.config.RetryKeys[0]))
+	}
+	return l.ctlr.View()
+}
+
+// MessageHandlers dispatches load results to the loader and every other
+// message to the wrapped controller once it is initialized
+func (l *Loader[T]) MessageHandlers() []MessageHandler {
+	handlers := []MessageHandler{
+		HandleMsg(l.onResult),
+		HandleMsg(l.onReload),
+	}
+	for _, handler := range messageHandlersOf(l.ctlr) {
+		handle := handler.Handle
+		handler.Handle = func(msg tea.Msg) Cmd {
+			if !l.initialized {
+				return nil
+			}
+			return handle(msg)
+		}
+		handlers = append(handlers, handler)
+	}
+	return handlers
+}
+
+// Subscriptions starts the subscriptions of the wrapped controller
+func (l *Loader[T]) Subscriptions() []Subscription {
+	if subscriber, ok := l.ctlr.(Subscriber); ok {
+		return subscriber.Subscriptions()
+	}
+	return nil
+}
+
+// start runs the load function in a command
+func (l *Loader[T]) start() Cmd {
+	if l.cancel != nil {
+		l.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if l.config.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, l.config.Timeout)
+	}
+	l.cancel = cancel
+	l.generation++
+
+	generation, route := l.generation, l.route
+	return func() Msg {
+		data, err := l.load(ctx, route)
+		return Msg{
+			Inner:   loaderResultMsg[T]{loader: l, generation: generation, data: data, err: err},
+			Context: ctx,
+		}
+	}
+}
+
+// ready hands the data to the wrapped controller and initializes it the
+// first time in the activation
+func (l *Loader[T]) ready() Cmd {
+	l.state = Loaded
+	loaded := l.ctlr.Loaded(l.data)
+	if l.initialized {
+		return loaded
+	}
+
+	l.initialized = true
+	return Batch(loaded, l.ctlr.Init(l.handlers))
+}
+
+func (l *Loader[T]) onResult(msg loaderResultMsg[T]) Cmd {
+	if msg.loader != l || msg.generation != l.generation {
+		return nil
+	}
+	l.cancel()
+
+	if msg.err != nil {
+		slog.Error("Route loader failed", "error", msg.err)
+		if l.initialized {
+			// keep showing the data that is already on screen
+			return Notify(NotifyError, "Failed to reload: "+msg.err.Error())
+		}
+		l.state = LoadFailed
+		l.err = msg.err
+		return nil
+	}
+
+	l.data = msg.data
+	l.hasData = true
+	l.loadedAt = time.Now()
+	l.err = nil
+	return l.ready()
+}
+
+func (l *Loader[T]) onRetry(KeyMsg) Cmd {
+	l.state = Loading
+	return l.start()
+}
+
+func (l *Loader[T]) onReload(ReloadMsg) Cmd {
+	if !l.initialized {
+		l.state = Loading
+	}
+	return l.start()
+}
+
+// enter keeps the navigation for the load function, the router calls it
+// before Init
+func (l *Loader[T]) enter(route *Context) {
+	if l.route != nil && !reflect.DeepEqual(l.route.Data, route.Data) {
+		// cached data belongs to other params, like another id
+		var zero T
+		l.data, l.hasData = zero, false
+	}
+	l.route = route
+}
+
+func (l *Loader[T]) unwrap() Controller {
+	return l.ctlr
+}
+
+// deactivate cancels a load that is still running when the route is left
+func (l *Loader[T]) deactivate() {
+	if l.cancel != nil {
+		l.cancel()
+	}
+	l.generation++
+}
+
+// deactivator is implemented by framework controllers that release
+// resources when the application navigates away from them
+type deactivator interface {
+	deactivate()
+}
+
+// navigationReceiver is implemented by framework controllers that need the
+// navigation that activates them
+type navigationReceiver interface {
+	enter(route *Context)
+}
+
+// wrapper is implemented by framework controllers that wrap another one.
+// The wrapped controller's Undoable, Persistable and event subscriptions
+// apply to the route
+type wrapper interface {
+	unwrap() Controller
+}
+
+// unwrapController returns the controller inside any wrappers
+func unwrapController(ctlr Controller) Controller {
+	for {
+		w, ok := ctlr.(wrapper)
+		if !ok {
+			return ctlr
+		}
+		ctlr = w.unwrap()
+	}
+}
@@ -0,0 +1,275 @@
+package mvct
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// UsersController renders the users handed to it by a loader
+type UsersController struct {
+	users    []string
+	inits    int
+	received []string
+}
+
+func (c *UsersController) Init(handlers KeyHandlers) Cmd {
+	c.inits++
+	return nil
+}
+
+func (c *UsersController) View() string {
+	return strings.Join(c.users, ",")
+}
+
+func (c *UsersController) Loaded(users []string) Cmd {
+	c.users = users
+	return nil
+}
+
+func (c *UsersController) OnStringMsg(msg StringMsg) Cmd {
+	c.received = append(c.received, msg.Value)
+	return nil
+}
+
+// fakeUsers returns the users of the current call and counts the calls
+type fakeUsers struct {
+	calls int
+	err   error
+}
+
+func (f *fakeUsers) load(ctx context.Context) ([]string, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []string{"ada", "grace"}, nil
+}
+
+func newLoaderApp(config LoaderConfig) (*Application[string], *fakeUsers, *UsersController) {
+	users := &fakeUsers{}
+	ctlr := &UsersController{}
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.RegisterController("/users", Load(users.load, ctlr, config))
+	app.Init()
+	return app, users, ctlr
+}
+
+// runCmds feeds the messages of cmd back into the application
+func runCmds(app *Application[string], cmd tea.Cmd) {
+	for _, msg := range collectMsgs(cmd) {
+		if msg != nil {
+			_, next := app.Update(msg)
+			runCmds(app, next)
+		}
+	}
+}
+
+func TestLoader_States(t *testing.T) {
+	app, users, ctlr := newLoaderApp(LoaderConfig{})
+	users.err = errors.New("offline")
+
+	_, cmd := app.Update(NavigateMsg{Route: "/users"})
+	if view := app.View(); !strings.Contains(view, "Loading") {
+		t.Errorf("expected the loading view, got %q", view)
+	}
+
+	app.Update(StringMsg{Value: "early"})
+	runCmds(app, cmd)
+	if view := app.View(); !strings.Contains(view, "offline") {
+		t.Errorf("expected the error view, got %q", view)
+	}
+	if ctlr.inits != 0 || len(ctlr.received) != 0 {
+		t.Error("the controller should not be initialized before its data is loaded")
+	}
+
+	users.err = nil
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	runCmds(app, cmd)
+	if view := app.View(); view != "ada,grace" {
+		t.Errorf("expected the controller view after retry, got %q", view)
+	}
+	if ctlr.inits != 1 {
+		t.Errorf("expected a single init, got %d", ctlr.inits)
+	}
+
+	app.Update(StringMsg{Value: "hello"})
+	if len(ctlr.received) != 1 {
+		t.Errorf("expected messages to reach the loaded controller, got %v", ctlr.received)
+	}
+}
+
+func TestLoader_Cache(t *testing.T) {
+	app, users, _ := newLoaderApp(LoaderConfig{TTL: time.Hour})
+
+	_, cmd := app.Update(NavigateMsg{Route: "/users"})
+	runCmds(app, cmd)
+	app.Update(NavigateMsg{Route: "/home"})
+	_, cmd = app.Update(NavigateMsg{Route: "/users"})
+	runCmds(app, cmd)
+
+	if users.calls != 1 {
+		t.Errorf("expected cached data within the TTL, got %d loads", users.calls)
+	}
+	if view := app.View(); view != "ada,grace" {
+		t.Errorf("expected cached data on screen, got %q", view)
+	}
+
+	_, cmd = app.Update(ReloadMsg{})
+	if view := app.View(); view != "ada,grace" {
+		t.Errorf("expected data to stay on screen while reloading, got %q", view)
+	}
+	runCmds(app, cmd)
+	if users.calls != 2 {
+		t.Errorf("expected Reload to load again, got %d loads", users.calls)
+	}
+}
+
+func TestLoader_Revalidate(t *testing.T) {
+	app, users, _ := newLoaderApp(LoaderConfig{Revalidate: true})
+
+	_, cmd := app.Update(NavigateMsg{Route: "/users"})
+	runCmds(app, cmd)
+	app.Update(NavigateMsg{Route: "/home"})
+
+	_, cmd = app.Update(NavigateMsg{Route: "/users"})
+	if view := app.View(); view != "ada,grace" {
+		t.Errorf("expected stale data while revalidating, got %q", view)
+	}
+	runCmds(app, cmd)
+	if users.calls != 2 {
+		t.Errorf("expected a background load, got %d loads", users.calls)
+	}
+}
+
+func TestLoader_StaleResultDropped(t *testing.T) {
+	app, _, ctlr := newLoaderApp(LoaderConfig{})
+
+	_, cmd := app.Update(NavigateMsg{Route: "/users"})
+	app.Update(NavigateMsg{Route: "/home"})
+	runCmds(app, cmd)
+
+	if ctlr.users != nil {
+		t.Errorf("a result arriving after the route was left should be dropped, got %v", ctlr.users)
+	}
+}
+
+func TestLoader_RouteContext(t *testing.T) {
+	var loaded []any
+	ctlr := &UsersController{}
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.RegisterController("/users", LoadRoute(func(ctx context.Context, route *Context) ([]string, error) {
+		loaded = append(loaded, route.Data["id"])
+		return []string{route.From}, nil
+	}, ctlr, LoaderConfig{TTL: time.Hour}))
+	app.Init()
+
+	_, cmd := app.Update(NavigateMsg{Route: "/users", Data: map[string]any{"id": 1}})
+	runCmds(app, cmd)
+	if len(loaded) != 1 || loaded[0] != 1 {
+		t.Fatalf("expected the navigation data in the load function, got %v", loaded)
+	}
+	if view := app.View(); view != "/home" {
+		t.Errorf("expected the route the navigation came from, got %q", view)
+	}
+
+	app.Update(NavigateMsg{Route: "/home"})
+	_, cmd = app.Update(NavigateMsg{Route: "/users", Data: map[string]any{"id": 1}})
+	runCmds(app, cmd)
+	if len(loaded) != 1 {
+		t.Errorf("expected cached data for the same data, got %d loads", len(loaded))
+	}
+
+	app.Update(NavigateMsg{Route: "/home"})
+	_, cmd = app.Update(NavigateMsg{Route: "/users", Data: map[string]any{"id": 2}})
+	if view := app.View(); !strings.Contains(view, "Loading") {
+		t.Errorf("expected other data to skip the cache, got %q", view)
+	}
+	runCmds(app, cmd)
+	if len(loaded) != 2 || loaded[1] != 2 {
+		t.Errorf("expected a load for the new data, got %v", loaded)
+	}
+}
+
+// NotesController is loaded and keeps its own undo history and state
+type NotesController struct {
+	UsersController
+	history *UndoHistory
+	saved   string
+}
+
+func (c *NotesController) UndoHistory() *UndoHistory {
+	return c.history
+}
+
+func (c *NotesController) SaveState() ([]byte, error) {
+	return []byte(c.saved), nil
+}
+
+func (c *NotesController) RestoreState(data []byte) error {
+	c.saved = string(data)
+	return nil
+}
+
+func (c *NotesController) onMessageSent(event messageSent) Cmd {
+	c.received = append(c.received, event.Text)
+	return nil
+}
+
+func TestLoader_ForwardsWrappedController(t *testing.T) {
+	users := &fakeUsers{}
+	notes := &NotesController{history: NewUndoHistory(10)}
+	loader := Load(users.load, notes, LoaderConfig{})
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.RegisterController("/notes", loader)
+	app.Init()
+
+	if history := app.undoHistoryOf(loader); history != notes.history {
+		t.Error("expected the undo history of the wrapped controller")
+	}
+	if _, ok := unwrapController(loader).(Persistable); !ok {
+		t.Error("expected the wrapped controller to be persisted")
+	}
+
+	app.Events().Subscribe(notes, DeliverQueued, HandleMsg(notes.onMessageSent))
+	app.Update(PublishMsg{Event: messageSent{Text: "queued"}})
+	_, cmd := app.Update(NavigateMsg{Route: "/notes"})
+	runCmds(app, cmd)
+	app.Update(PublishMsg{Event: messageSent{Text: "active"}})
+	if len(notes.received) != 2 || notes.received[0] != "queued" || notes.received[1] != "active" {
+		t.Errorf("expected events of the wrapped controller while its loader is active, got %v", notes.received)
+	}
+}
+
+func TestLoader_RetryKeysKeepHandlers(t *testing.T) {
+	users := &fakeUsers{err: errors.New("offline")}
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.RegisterController("/users", Load(users.load, &UsersController{}, LoaderConfig{}))
+	app.UseUndo(UndoConfig{UndoKeys: []string{"r"}})
+	app.Init()
+
+	_, cmd := app.Update(NavigateMsg{Route: "/users"})
+	runCmds(app, cmd)
+	users.err = nil
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	runCmds(app, cmd)
+	if users.calls != 2 || app.View() != "ada,grace" {
+		t.Fatalf("expected r to retry the failed load, got %d loads", users.calls)
+	}
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if cmd == nil {
+		t.Fatal("expected r to reach the undo binding once loaded")
+	}
+	if _, ok := cmd().(UndoMsg); !ok || users.calls != 2 {
+		t.Errorf("expected the undo binding on r once loaded, got %d loads", users.calls)
+	}
+}
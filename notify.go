@@ -0,0 +1,322 @@
+package mvct
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DefaultNotificationDuration is how long a notification stays on screen
+// when neither the message nor the config specify a duration
+const DefaultNotificationDuration = 3 * time.Second
+
+// NotificationLevel describes the severity of a notification
+type NotificationLevel int
+
+const (
+	NotifyInfo NotificationLevel = iota
+	NotifySuccess
+	NotifyWarning
+	NotifyError
+)
+
+func (l NotificationLevel) String() string {
+	switch l {
+	case NotifyInfo:
+		return "info"
+	case NotifySuccess:
+		return "success"
+	case NotifyWarning:
+		return "warning"
+	case NotifyError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+// Notification is a single message shown to the user by the layout
+type Notification struct {
+	ID    int
+	Level NotificationLevel
+	Text  string
+	Time  time.Time
+}
+
+// NotifyMsg queues a notification. A zero Duration uses the configured
+// default, a negative Duration keeps the notification until it is dismissed
+type NotifyMsg struct {
+	Level    NotificationLevel
+	Text     string
+	Duration time.Duration
+}
+
+// DismissNotificationsMsg removes every visible and queued notification
+type DismissNotificationsMsg struct{}
+
+// notificationExpiredMsg is sent by the tick started when a notification
+// becomes visible
+type notificationExpiredMsg struct {
+	id int
+}
+
+// Notify queues a notification to be rendered on top of the layout
+func Notify(level NotificationLevel, text string) Cmd {
+	return func() Msg {
+		return Msg{
+			Inner: NotifyMsg{Level: level, Text: text},
+		}
+	}
+}
+
+// DismissNotifications clears all notifications currently on screen
+func DismissNotifications() Cmd {
+	return func() Msg {
+		return Msg{
+			Inner: DismissNotificationsMsg{},
+		}
+	}
+}
+
+// NotificationConfig configures how notifications are displayed
+type NotificationConfig struct {
+	// Position is the corner notifications are rendered in
+	Position Corner
+	// Duration is how long a notification is visible before it is dismissed
+	Duration time.Duration
+	// MaxVisible is the number of notifications shown at once, the rest
+	// wait in a queue until a slot frees up
+	MaxVisible int
+	// HistoryRoute registers a route listing past notifications when set
+	HistoryRoute string
+	// HistoryLimit is the number of notifications kept in the history
+	HistoryLimit int
+	// Render renders a single notification, defaults to RenderNotification
+	Render func(n Notification, maxWidth int) string
+}
+
+func (c NotificationConfig) withDefaults() NotificationConfig {
+	if c.Duration == 0 {
+		c.Duration = DefaultNotificationDuration
+	}
+	if c.MaxVisible <= 0 {
+		c.MaxVisible = 3
+	}
+	if c.HistoryLimit <= 0 {
+		c.HistoryLimit = 50
+	}
+	if c.Render == nil {
+		c.Render = RenderNotification
+	}
+	return c
+}
+
+// UseNotifications configures notifications. Notifications work without
+// calling this, it only needs to be called to change the defaults
+func (a *Application[M]) UseNotifications(config NotificationConfig) {
+	slog.Debug("Configuring notifications", "position", config.Position, "history_route", config.HistoryRoute)
+	a.notifications.config = config.withDefaults()
+
+	if config.HistoryRoute != "" {
+		a.RegisterController(config.HistoryRoute, &notificationHistoryController{
+			notifications: a.notifications,
+			previousRoute: a.router.PreviousRoute,
+		})
+	}
+}
+
+// Notifications returns the notifications currently on screen
+func (a *Application[M]) Notifications() []Notification {
+	notifications := make([]Notification, 0, len(a.notifications.visible))
+	for _, queued := range a.notifications.visible {
+		notifications = append(notifications, queued.Notification)
+	}
+	return notifications
+}
+
+func (a *Application[M]) handleNotify(msg NotifyMsg) (tea.Model, tea.Cmd) {
+	slog.Debug("Queueing notification", "level", msg.Level, "text", msg.Text)
+	return a, a.notifications.push(msg)
+}
+
+func (a *Application[M]) handleNotificationExpired(msg notificationExpiredMsg) (tea.Model, tea.Cmd) {
+	return a, a.notifications.expire(msg.id)
+}
+
+type queuedNotification struct {
+	Notification
+	duration time.Duration
+}
+
+// notifier keeps track of the notifications for an application
+type notifier struct {
+	config  NotificationConfig
+	nextID  int
+	visible []queuedNotification
+	pending []queuedNotification
+	history []Notification
+}
+
+func newNotifier(config NotificationConfig) *notifier {
+	return &notifier{config: config.withDefaults()}
+}
+
+// push queues a notification and returns the dismissal tick if it is shown
+// straight away
+func (n *notifier) push(msg NotifyMsg) tea.Cmd {
+	n.nextID++
+	duration := msg.Duration
+	if duration == 0 {
+		duration = n.config.Duration
+	}
+
+	queued := queuedNotification{
+		Notification: Notification{
+			ID:    n.nextID,
+			Level: msg.Level,
+			Text:  msg.Text,
+			Time:  time.Now(),
+		},
+		duration: duration,
+	}
+
+	n.history = append(n.history, queued.Notification)
+	if over := len(n.history) - n.config.HistoryLimit; over > 0 {
+		n.history = n.history[over:]
+	}
+
+	if len(n.visible) >= n.config.MaxVisible {
+		n.pending = append(n.pending, queued)
+		return nil
+	}
+	return n.show(queued)
+}
+
+func (n *notifier) show(queued queuedNotification) tea.Cmd {
+	n.visible = append(n.visible, queued)
+	if queued.duration < 0 {
+		return nil
+	}
+
+	id := queued.ID
+	return tea.Tick(queued.duration, func(time.Time) tea.Msg {
+		return notificationExpiredMsg{id: id}
+	})
+}
+
+// expire removes a visible notification and shows the next queued one
+func (n *notifier) expire(id int) tea.Cmd {
+	for i, queued := range n.visible {
+		if queued.ID == id {
+			n.visible = append(n.visible[:i], n.visible[i+1:]...)
+			break
+		}
+	}
+
+	var cmds []tea.Cmd
+	for len(n.pending) > 0 && len(n.visible) < n.config.MaxVisible {
+		next := n.pending[0]
+		n.pending = n.pending[1:]
+		cmds = append(cmds, n.show(next))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (n *notifier) dismissAll() {
+	n.visible = nil
+	n.pending = nil
+}
+
+// overlay renders the visible notifications on top of view
+func (n *notifier) overlay(view string, width, height int) string {
+	if len(n.visible) == 0 {
+		return view
+	}
+
+	screenWidth := width
+	if screenWidth <= 0 {
+		screenWidth = lipgloss.Width(view)
+	}
+	maxWidth := min(40, max(screenWidth/2, 12))
+
+	align := lipgloss.Left
+	if n.config.Position == TopRight || n.config.Position == BottomRight {
+		align = lipgloss.Right
+	}
+
+	boxes := make([]string, 0, len(n.visible))
+	for _, queued := range n.visible {
+		boxes = append(boxes, n.config.Render(queued.Notification, maxWidth))
+	}
+
+	return placeInCorner(n.config.Position, lipgloss.JoinVertical(align, boxes...), view, width, height)
+}
+
+// notificationColor returns the theme token of a notification level
+func notificationColor(level NotificationLevel) ThemeColor {
+	theme := CurrentTheme()
+	switch level {
+	case NotifySuccess:
+		return theme.Success
+	case NotifyWarning:
+		return theme.Warning
+	case NotifyError:
+		return theme.Error
+	}
+	return theme.Info
+}
+
+// RenderNotification is the default notification renderer, a rounded box
+// colored by the notification level
+func RenderNotification(n Notification, maxWidth int) string {
+	color := notificationColor(n.Level).Color()
+	textWidth := min(ansi.StringWidth(n.Text), max(maxWidth-4, 1))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(color).
+		Foreground(color).
+		Padding(0, 1).
+		Width(textWidth + 2).
+		Render(n.Text)
+}
+
+// notificationHistoryController lists past notifications, newest first
+type notificationHistoryController struct {
+	notifications *notifier
+	previousRoute func() string
+}
+
+func (c *notificationHistoryController) Init(handlers KeyHandlers) Cmd {
+	handlers["esc"] = func(msg KeyMsg) Cmd {
+		if route := c.previousRoute(); route != "" {
+			return Navigate(route)
+		}
+		return nil
+	}
+	handlers["c"] = func(msg KeyMsg) Cmd {
+		c.notifications.history = nil
+		return nil
+	}
+	return nil
+}
+
+func (c *notificationHistoryController) View() string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("Notifications (%d)\n\n", len(c.notifications.history)))
+	if len(c.notifications.history) == 0 {
+		b.WriteString("  No notifications yet\n")
+	}
+	for i := len(c.notifications.history) - 1; i >= 0; i-- {
+		n := c.notifications.history[i]
+		b.WriteString(fmt.Sprintf("  %s  %-7s  %s\n", n.Time.Format("15:04:05"), n.Level, n.Text))
+	}
+	b.WriteString("\nesc: back • c: clear")
+
+	return b.String()
+}
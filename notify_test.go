@@ -0,0 +1,139 @@
+package mvct
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNotify(t *testing.T) {
+	cmd := Notify(NotifySuccess, "Saved!")
+	if cmd == nil {
+		t.Fatal("Notify returned nil")
+	}
+
+	msg, ok := cmd().Inner.(NotifyMsg)
+	if !ok {
+		t.Fatal("Notify cmd did not return NotifyMsg")
+	}
+	if msg.Level != NotifySuccess || msg.Text != "Saved!" {
+		t.Errorf("unexpected NotifyMsg %+v", msg)
+	}
+}
+
+func TestApplicationUpdate_Notify(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.Init()
+
+	_, cmd := app.Update(NotifyMsg{Level: NotifyInfo, Text: "hello"})
+	if cmd == nil {
+		t.Error("expected a dismissal tick for a visible notification")
+	}
+
+	notifications := app.Notifications()
+	if len(notifications) != 1 || notifications[0].Text != "hello" {
+		t.Fatalf("expected one visible notification, got %+v", notifications)
+	}
+
+	app.Update(notificationExpiredMsg{id: notifications[0].ID})
+	if len(app.Notifications()) != 0 {
+		t.Errorf("expected notification to be dismissed, got %+v", app.Notifications())
+	}
+}
+
+func TestApplicationUpdate_NotifyQueue(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.UseNotifications(NotificationConfig{MaxVisible: 1})
+	app.Init()
+
+	app.Update(NotifyMsg{Text: "first"})
+	_, cmd := app.Update(NotifyMsg{Text: "second"})
+	if cmd != nil {
+		t.Error("queued notification should not start a dismissal tick")
+	}
+	if len(app.Notifications()) != 1 {
+		t.Fatalf("expected 1 visible notification, got %d", len(app.Notifications()))
+	}
+
+	first := app.Notifications()[0]
+	_, cmd = app.Update(notificationExpiredMsg{id: first.ID})
+	if cmd == nil {
+		t.Error("promoted notification should start a dismissal tick")
+	}
+
+	visible := app.Notifications()
+	if len(visible) != 1 || visible[0].Text != "second" {
+		t.Errorf("expected queued notification to be promoted, got %+v", visible)
+	}
+
+	app.Update(DismissNotificationsMsg{})
+	if len(app.Notifications()) != 0 {
+		t.Error("DismissNotificationsMsg did not clear notifications")
+	}
+}
+
+func TestApplicationView_Notification(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.UseNotifications(NotificationConfig{Position: BottomLeft})
+	app.Init()
+	app.Update(tea.WindowSizeMsg{Width: 60, Height: 10})
+
+	app.Update(NotifyMsg{Level: NotifyError, Text: "Failed to connect"})
+
+	view := app.View()
+	if !strings.Contains(view, "Failed to connect") {
+		t.Errorf("expected notification in view, got:\n%s", view)
+	}
+	if !strings.HasPrefix(view, "home") {
+		t.Errorf("expected controller view to stay visible, got:\n%s", view)
+	}
+	lines := strings.Split(view, "\n")
+	if !strings.Contains(lines[len(lines)-2], "Failed to connect") {
+		t.Errorf("expected notification in the bottom corner, got:\n%s", view)
+	}
+}
+
+func TestNotificationHistoryRoute(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.UseNotifications(NotificationConfig{HistoryRoute: "/notifications"})
+	app.Init()
+
+	app.Update(NotifyMsg{Text: "one"})
+	app.Update(NotifyMsg{Text: "two"})
+	app.Update(NavigateMsg{Route: "/notifications"})
+
+	view := app.router.Current().View()
+	if strings.Index(view, "two") > strings.Index(view, "one") {
+		t.Errorf("expected newest notification first, got:\n%s", view)
+	}
+
+	handler, ok := app.keyHandlers["esc"]
+	if !ok {
+		t.Fatal("history route did not register esc")
+	}
+	nav, ok := handler(KeyMsg{})().Inner.(NavigateMsg)
+	if !ok || nav.Route != "/home" {
+		t.Errorf("expected esc to navigate back to /home, got %+v", nav)
+	}
+}
+
+func TestPlaceOverlay(t *testing.T) {
+	bg := "aaaaa\nbbbbb\nccccc"
+
+	got := placeOverlay(1, 1, "XY", bg)
+	want := "aaaaa\nbXYbb\nccccc"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	got = placeInCorner(BottomRight, "Z", bg, 0, 0)
+	want = "aaaaa\nbbbbb\nccccZ"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
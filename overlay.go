@@ -0,0 +1,68 @@
+package mvct
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Corner identifies a corner of the screen
+type Corner int
+
+const (
+	TopRight Corner = iota
+	TopLeft
+	BottomRight
+	BottomLeft
+)
+
+// placeOverlay draws fg on top of bg with its top left corner at x, y.
+// Both strings may contain ANSI escape sequences
+func placeOverlay(x, y int, fg, bg string) string {
+	fgLines := strings.Split(fg, "\n")
+	bgLines := strings.Split(bg, "\n")
+
+	for len(bgLines) < y+len(fgLines) {
+		bgLines = append(bgLines, "")
+	}
+
+	for i, fgLine := range fgLines {
+		row := y + i
+		bgLine := bgLines[row]
+
+		left := ansi.Truncate(bgLine, x, "")
+		if w := ansi.StringWidth(left); w < x {
+			left += strings.Repeat(" ", x-w)
+		}
+		right := ansi.TruncateLeft(bgLine, x+ansi.StringWidth(fgLine), "")
+
+		bgLines[row] = left + fgLine + right
+	}
+
+	return strings.Join(bgLines, "\n")
+}
+
+// placeInCorner draws fg on top of bg in the given corner of a screen with
+// the given dimensions. When the dimensions are unknown the size of bg is used
+func placeInCorner(corner Corner, fg, bg string, width, height int) string {
+	if width <= 0 {
+		width = lipgloss.Width(bg)
+	}
+	if height <= 0 {
+		height = lipgloss.Height(bg)
+	}
+
+	x, y := 0, 0
+	switch corner {
+	case TopRight:
+		x = width - lipgloss.Width(fg)
+	case BottomLeft:
+		y = height - lipgloss.Height(fg)
+	case BottomRight:
+		x = width - lipgloss.Width(fg)
+		y = height - lipgloss.Height(fg)
+	}
+
+	return placeOverlay(max(x, 0), max(y, 0), fg, bg)
+}
@@ -2,6 +2,9 @@ package mvct
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -15,18 +18,51 @@ type Route[M any] struct {
 // NavigateMsg signals a route change
 type NavigateMsg struct {
 	Route string
+	// Data is handed to the middleware and route loaders in Context.Data
+	Data map[string]any
 }
 
 // Router manages routing between controllers
 type Router struct {
-	routes       map[string]Controller
-	currentRoute string
-	// currently don't have use case for this may come back and enable it
-	// previousRoute string
-	defaultRoute string
-	middleware   []Middleware
+	routes        map[string]Controller
+	currentRoute  string
+	previousRoute string
+	defaultRoute  string
+	middleware    []Middleware
+	// history holds the routes left by successful navigations, oldest
+	// first
+	history []string
+	// traced receives every navigation with the middleware decisions, set
+	// by UseDevtools
+	traced func(trace NavigationTrace)
+	// handleMiddleware runs a middleware in place of its Handle, set by
+	// UseTracing to give each one a span
+	handleMiddleware func(m Middleware, ctx *Context) bool
+	// entered is the navigation that activated the current route
+	entered *Context
 }
 
+// NavigationTrace is a navigation with what each middleware decided
+type NavigationTrace struct {
+	Time time.Time
+	From string
+	To   string
+	// Decisions holds the middleware that ran in order, the last one
+	// blocked the navigation when it was not allowed
+	Decisions []MiddlewareDecision
+	// Err is set when the navigation failed
+	Err error
+}
+
+// MiddlewareDecision is the result of a middleware for a navigation
+type MiddlewareDecision struct {
+	Middleware string
+	Allowed    bool
+}
+
+// maxHistory bounds the navigation history
+const maxHistory = 100
+
 func NewRouter() *Router {
 	return &Router{
 		routes:     make(map[string]Controller),
@@ -61,32 +97,133 @@ func (r *Router) Current() Controller {
 
 // Navigate changes the current route
 func (r *Router) Navigate(handlers KeyHandlers, path string) (tea.Cmd, error) {
+	return r.navigate(handlers, path, nil)
+}
+
+func (r *Router) navigate(handlers KeyHandlers, path string, data map[string]any) (tea.Cmd, error) {
+	oldRoute := r.currentRoute
+	ctx := &Context{
+		From: oldRoute,
+		To:   path,
+		Data: data,
+	}
 	if _, ok := r.routes[path]; !ok {
-		return nil, fmt.Errorf("route not found: %s", path)
+		err := fmt.Errorf("route not found: %s", path)
+		r.trace(ctx, nil, err)
+		return nil, err
 	}
 
-	oldRoute := r.currentRoute
 	r.currentRoute = path
+	if err := r.runMiddleware(ctx); err != nil {
+		r.currentRoute = oldRoute
+		return nil, err
+	}
+
+	r.previousRoute = oldRoute
+	r.pushHistory(oldRoute)
+	r.entered = ctx
+
+	// Initialize new controller
+	return unwrapCmd(r.initCurrent(handlers)), nil
+}
+
+// initCurrent initializes the current controller, controllers that load
+// data get the navigation that activated them first
+func (r *Router) initCurrent(handlers KeyHandlers) Cmd {
+	ctlr := r.Current()
+	if receiver, ok := ctlr.(navigationReceiver); ok {
+		entered := r.entered
+		if entered == nil {
+			entered = &Context{To: r.CurrentRoute()}
+		}
+		receiver.enter(entered)
+	}
+	return ctlr.Init(handlers)
+}
 
-	// Run middleware
+// start makes route the first route instead of the default route. The
+// middleware sees it as a navigation from no route
+func (r *Router) start(route string, data map[string]any) error {
 	ctx := &Context{
-		From: oldRoute,
-		To:   path,
+		To:   route,
+		Data: data,
+	}
+	if _, ok := r.routes[route]; !ok {
+		err := fmt.Errorf("route not found: %s", route)
+		r.trace(ctx, nil, err)
+		return err
 	}
+	if err := r.runMiddleware(ctx); err != nil {
+		return err
+	}
+
+	r.currentRoute = route
+	r.entered = ctx
+	return nil
+}
 
+// runMiddleware runs the middleware in order until one blocks the
+// navigation
+func (r *Router) runMiddleware(ctx *Context) error {
+	var decisions []MiddlewareDecision
 	for _, m := range r.middleware {
-		if !m.Handle(ctx) {
-			// Middleware blocked navigation
-			r.currentRoute = oldRoute
-			return nil, fmt.Errorf("navigation blocked by middleware")
+		var allowed bool
+		if r.handleMiddleware != nil {
+			allowed = r.handleMiddleware(m, ctx)
+		} else {
+			allowed = m.Handle(ctx)
+		}
+		if r.traced != nil {
+			decisions = append(decisions, MiddlewareDecision{Middleware: middlewareName(m), Allowed: allowed})
+		}
+		if !allowed {
+			err := fmt.Errorf("navigation blocked by middleware")
+			r.trace(ctx, decisions, err)
+			return err
 		}
 	}
+	r.trace(ctx, decisions, nil)
+	return nil
+}
 
-	// Initialize new controller
-	return unwrapCmd(r.Current().Init(handlers)), nil
+func (r *Router) trace(ctx *Context, decisions []MiddlewareDecision, err error) {
+	if r.traced == nil {
+		return
+	}
+	r.traced(NavigationTrace{Time: time.Now(), From: ctx.From, To: ctx.To, Decisions: decisions, Err: err})
+}
+
+// middlewareName names a middleware by its type, or by the function of a
+// MiddlewareFunc
+func middlewareName(m Middleware) string {
+	if f, ok := m.(MiddlewareFunc); ok {
+		return funcName(f)
+	}
+	return reflect.TypeOf(m).String()
 }
 
 // CurrentRoute returns the current route path
 func (r *Router) CurrentRoute() string {
 	return r.currentRoute
 }
+
+// PreviousRoute returns the route that was active before the last
+// successful navigation
+func (r *Router) PreviousRoute() string {
+	return r.previousRoute
+}
+
+// History returns the routes visited before the current one, oldest first
+func (r *Router) History() []string {
+	return slices.Clone(r.history)
+}
+
+func (r *Router) pushHistory(route string) {
+	if route == "" {
+		return
+	}
+	r.history = append(r.history, route)
+	if over := len(r.history) - maxHistory; over > 0 {
+		r.history = slices.Delete(r.history, 0, over)
+	}
+}
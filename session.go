@@ -0,0 +1,200 @@
+package mvct
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// sessionFormat is the version of the session file layout written by this
+// version of mvct, files with a newer format are ignored
+const sessionFormat = 1
+
+// Persistable is implemented by controllers whose state, like a cursor
+// position or a draft, survives restarts when sessions are enabled
+type Persistable interface {
+	// SaveState returns the state to store as JSON
+	SaveState() ([]byte, error)
+	// RestoreState is called with the saved state before the controller is
+	// first activated
+	RestoreState(data []byte) error
+}
+
+// SessionConfig configures session persistence
+type SessionConfig struct {
+	// Name is the directory of the session in the user's state dir,
+	// usually the name of the app
+	Name string
+	// Path overrides the session file, Name is not needed then
+	Path string
+	// Version of the persisted controller state. A session saved with
+	// another version is discarded, bump it when the state of a
+	// Persistable controller changes incompatibly
+	Version int
+	// SkipRoutes are never restored as the current route, like a
+	// confirmation screen. Their controller state is still persisted
+	SkipRoutes []string
+}
+
+// session is the content of a session file
+type session struct {
+	Format  int                        `json:"format"`
+	Version int                        `json:"version"`
+	Saved   time.Time                  `json:"saved"`
+	Route   string                     `json:"route"`
+	History []string                   `json:"history,omitempty"`
+	State   map[string]json.RawMessage `json:"state,omitempty"`
+}
+
+// UseSession enables session persistence. Run restores the route, the
+// navigation history and the state of Persistable controllers of the last
+// session and saves them when it returns
+func (a *Application[M]) UseSession(config SessionConfig) error {
+	if config.Path == "" {
+		if config.Name == "" {
+			return errors.New("session needs a name or a path")
+		}
+		dir, err := stateDir()
+		if err != nil {
+			return err
+		}
+		config.Path = filepath.Join(dir, config.Name, "session.json")
+	}
+
+	slog.Debug("Configuring session", "path", config.Path, "version", config.Version)
+	a.session = &config
+	return nil
+}
+
+// stateDir returns the directory for state kept between runs,
+// $XDG_STATE_HOME or ~/.local/state on unix
+func stateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		return os.UserCacheDir()
+	case "darwin":
+		return os.UserConfigDir()
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the state dir: %w", err)
+	}
+	return filepath.Join(home, ".local", "state"), nil
+}
+
+// SaveSession writes the current session, Run calls it on exit
+func (a *Application[M]) SaveSession() error {
+	if a.session == nil {
+		return errors.New("sessions are not enabled, call UseSession")
+	}
+
+	s := session{
+		Format:  sessionFormat,
+		Version: a.session.Version,
+		Saved:   time.Now(),
+		Route:   a.router.CurrentRoute(),
+		History: a.router.History(),
+		State:   map[string]json.RawMessage{},
+	}
+	for route, ctlr := range a.router.routes {
+		persistable, ok := unwrapController(ctlr).(Persistable)
+		if !ok {
+			continue
+		}
+		data, err := persistable.SaveState()
+		if err != nil {
+			slog.Error("Failed to save controller state", "route", route, "error", err)
+			continue
+		}
+		if !json.Valid(data) {
+			slog.Error("Controller state is not JSON", "route", route)
+			continue
+		}
+		s.State[route] = data
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(a.session.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create session dir: %w", err)
+	}
+
+	// write a temporary file first so a crash never leaves half a session
+	tmp := a.session.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	if err := os.Rename(tmp, a.session.Path); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	slog.Info("Session saved", "path", a.session.Path, "route", s.Route)
+	return nil
+}
+
+// restoreSession loads the last session. A missing, broken or outdated
+// session is ignored so the app starts on its default route
+func (a *Application[M]) restoreSession() {
+	if a.session == nil {
+		return
+	}
+
+	data, err := os.ReadFile(a.session.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		slog.Warn("Failed to read session", "path", a.session.Path, "error", err)
+		return
+	}
+
+	var s session
+	if err := json.Unmarshal(data, &s); err != nil {
+		slog.Warn("Ignoring broken session", "path", a.session.Path, "error", err)
+		return
+	}
+	if s.Format > sessionFormat || s.Version != a.session.Version {
+		slog.Info("Discarding session of another version", "format", s.Format, "version", s.Version, "want", a.session.Version)
+		return
+	}
+
+	for route, state := range s.State {
+		persistable, ok := unwrapController(a.router.routes[route]).(Persistable)
+		if !ok {
+			continue
+		}
+		if err := persistable.RestoreState(state); err != nil {
+			slog.Warn("Failed to restore controller state", "route", route, "error", err)
+		}
+	}
+
+	for _, route := range s.History {
+		if _, ok := a.router.routes[route]; ok {
+			a.router.pushHistory(route)
+		}
+	}
+	if len(a.router.history) > 0 {
+		a.router.previousRoute = a.router.history[len(a.router.history)-1]
+	}
+
+	if s.Route == "" || slices.Contains(a.session.SkipRoutes, s.Route) {
+		return
+	}
+	if err := a.router.start(s.Route, nil); err != nil {
+		slog.Warn("Not restoring the last route", "route", s.Route, "error", err)
+		return
+	}
+	slog.Info("Session restored", "route", s.Route, "saved", s.Saved)
+}
@@ -0,0 +1,97 @@
+package mvct
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// DraftController persists a draft and its cursor
+type DraftController struct {
+	MockController
+	Draft  string `json:"draft"`
+	Cursor int    `json:"cursor"`
+}
+
+func (c *DraftController) SaveState() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *DraftController) RestoreState(data []byte) error {
+	return json.Unmarshal(data, c)
+}
+
+func newSessionApp(t *testing.T, path string, version int) (*Application[string], *DraftController) {
+	t.Helper()
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	draft := &DraftController{}
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.RegisterController("/list", &MockController{name: "list"})
+	app.RegisterController("/compose", draft)
+	if err := app.UseSession(SessionConfig{Path: path, Version: version}); err != nil {
+		t.Fatal(err)
+	}
+	return app, draft
+}
+
+func TestSession_SaveAndRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app", "session.json")
+
+	app, draft := newSessionApp(t, path, 1)
+	app.Init()
+	app.Update(NavigateMsg{Route: "/list"})
+	app.Update(NavigateMsg{Route: "/compose"})
+	draft.Draft, draft.Cursor = "Dear Ada", 8
+	if err := app.SaveSession(); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	restored, restoredDraft := newSessionApp(t, path, 1)
+	restored.restoreSession()
+	restored.Init()
+
+	if route := restored.router.CurrentRoute(); route != "/compose" {
+		t.Errorf("expected the last route, got %s", route)
+	}
+	if history := restored.router.History(); len(history) != 2 || history[0] != "/home" || history[1] != "/list" {
+		t.Errorf("expected the navigation history, got %v", history)
+	}
+	if restored.router.PreviousRoute() != "/list" {
+		t.Errorf("expected the previous route to be /list, got %s", restored.router.PreviousRoute())
+	}
+	if restoredDraft.Draft != "Dear Ada" || restoredDraft.Cursor != 8 {
+		t.Errorf("expected the controller state, got %+v", restoredDraft)
+	}
+}
+
+func TestSession_Discarded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	app, _ := newSessionApp(t, path, 1)
+	app.Init()
+	app.Update(NavigateMsg{Route: "/list"})
+	if err := app.SaveSession(); err != nil {
+		t.Fatal(err)
+	}
+
+	newer, _ := newSessionApp(t, path, 2)
+	newer.restoreSession()
+	if route := newer.router.CurrentRoute(); route != "/home" {
+		t.Errorf("a session of another version should be discarded, got %s", route)
+	}
+
+	blocked, _ := newSessionApp(t, path, 1)
+	blocked.Use(AuthMiddleware([]string{"/list"}, func() bool { return false }))
+	blocked.restoreSession()
+	if route := blocked.router.CurrentRoute(); route != "/home" {
+		t.Errorf("middleware should be able to block the restored route, got %s", route)
+	}
+
+	os.WriteFile(path, []byte("{broken"), 0644)
+	broken, _ := newSessionApp(t, path, 1)
+	broken.restoreSession()
+	if route := broken.router.CurrentRoute(); route != "/home" {
+		t.Errorf("a broken session should be ignored, got %s", route)
+	}
+}
@@ -0,0 +1,282 @@
+package mvct
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// Subscription is a long lived source of messages such as a channel, a
+// ticker or a file watcher. Run sends messages until ctx is done and
+// should return ctx.Err() then. Each message is dispatched like any other,
+// so it reaches the On* handlers of the active controller
+type Subscription struct {
+	// Name identifies the subscription in logs and SubscriptionEndedMsg
+	Name string
+	Run  func(ctx context.Context, send func(msg tea.Msg)) error
+}
+
+// Subscriber is implemented by controllers with subscriptions. They are
+// started every time the controller is activated and stopped when the
+// application navigates away
+type Subscriber interface {
+	Subscriptions() []Subscription
+}
+
+// SubscriptionEndedMsg is sent when a subscription returns before it is
+// stopped, Err is nil when its source was exhausted, like a closed channel
+type SubscriptionEndedMsg struct {
+	Name string
+	Err  error
+}
+
+// Channel sends every value received from ch until ch is closed
+func Channel[T any](ch <-chan T) Subscription {
+	return Subscription{
+		Name: fmt.Sprintf("channel %s", reflect.TypeFor[T]()),
+		Run: func(ctx context.Context, send func(msg tea.Msg)) error {
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case value, ok := <-ch:
+					if !ok {
+						return nil
+					}
+					send(value)
+				}
+			}
+		},
+	}
+}
+
+// Every sends the message returned by fn every d. Ticks are dropped while
+// the previous message has not been handled yet
+func Every(d time.Duration, fn func(time.Time) tea.Msg) Subscription {
+	return Subscription{
+		Name: fmt.Sprintf("every %s", d),
+		Run: func(ctx context.Context, send func(msg tea.Msg)) error {
+			ticker := time.NewTicker(d)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case t := <-ticker.C:
+					send(fn(t))
+				}
+			}
+		},
+	}
+}
+
+// FileChangedMsg is sent by WatchFiles
+type FileChangedMsg struct {
+	Path string
+	// Op lists the changes, such as CREATE, WRITE, REMOVE or RENAME
+	Op string
+}
+
+// WatchFiles sends a FileChangedMsg when one of the files or a file in one
+// of the directories changes. Directories are not watched recursively
+func WatchFiles(paths ...string) Subscription {
+	return Subscription{
+		Name: fmt.Sprintf("watch %v", paths),
+		Run: func(ctx context.Context, send func(msg tea.Msg)) error {
+			watcher, err := fsnotify.NewWatcher()
+			if err != nil {
+				return fmt.Errorf("failed to create file watcher: %w", err)
+			}
+			defer watcher.Close()
+
+			for _, path := range paths {
+				if err := watcher.Add(filepath.Clean(path)); err != nil {
+					return fmt.Errorf("failed to watch %s: %w", path, err)
+				}
+			}
+
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case event, ok := <-watcher.Events:
+					if !ok {
+						return nil
+					}
+					send(FileChangedMsg{Path: event.Name, Op: event.Op.String()})
+				case err, ok := <-watcher.Errors:
+					if !ok {
+						return nil
+					}
+					return fmt.Errorf("file watcher failed: %w", err)
+				}
+			}
+		},
+	}
+}
+
+// runningSubscription is a started subscription, its messages are read by
+// the command returned from next
+type runningSubscription struct {
+	Subscription
+	ctx    context.Context
+	cancel context.CancelFunc
+	msgs   chan tea.Msg
+	// err is written before msgs is closed
+	err error
+}
+
+// subscriptionMsg carries a message of a subscription into Update
+type subscriptionMsg struct {
+	sub *runningSubscription
+	msg tea.Msg
+}
+
+// subscriptionEndedMsg is returned once the source of a subscription
+// returned
+type subscriptionEndedMsg struct {
+	sub *runningSubscription
+}
+
+func startSubscription(sub Subscription) *runningSubscription {
+	ctx, cancel := context.WithCancel(context.Background())
+	running := &runningSubscription{
+		Subscription: sub,
+		ctx:          ctx,
+		cancel:       cancel,
+		msgs:         make(chan tea.Msg),
+	}
+
+	slog.Debug("Starting subscription", "name", sub.Name)
+	go func() {
+		running.err = sub.Run(ctx, running.send)
+		close(running.msgs)
+	}()
+	return running
+}
+
+func (s *runningSubscription) send(msg tea.Msg) {
+	select {
+	case s.msgs <- msg:
+	case <-s.ctx.Done():
+	}
+}
+
+// next waits for the next message of the subscription
+func (s *runningSubscription) next() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-s.msgs
+		if !ok {
+			return subscriptionEndedMsg{sub: s}
+		}
+		return subscriptionMsg{sub: s, msg: msg}
+	}
+}
+
+func (s *runningSubscription) stop() {
+	slog.Debug("Stopping subscription", "name", s.Name)
+	s.cancel()
+}
+
+func (s *runningSubscription) stopped() bool {
+	return s.ctx.Err() != nil
+}
+
+// Subscribe adds subscriptions that run for as long as the application,
+// they are started by Init
+func (a *Application[M]) Subscribe(subs ...Subscription) {
+	a.appSubscriptions = append(a.appSubscriptions, subs...)
+}
+
+// startAppSubscriptions starts the subscriptions added with Subscribe
+func (a *Application[M]) startAppSubscriptions() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, sub := range a.appSubscriptions {
+		running := startSubscription(sub)
+		a.running = append(a.running, running)
+		cmds = append(cmds, running.next())
+	}
+	return tea.Batch(cmds...)
+}
+
+// restartRouteSubscriptions stops the subscriptions of the previous
+// controller and starts the ones of ctlr
+func (a *Application[M]) restartRouteSubscriptions(ctlr Controller) tea.Cmd {
+	for _, running := range a.routeRunning {
+		running.stop()
+	}
+	a.routeRunning = nil
+
+	subscriber, ok := ctlr.(Subscriber)
+	if !ok {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+	for _, sub := range subscriber.Subscriptions() {
+		running := startSubscription(sub)
+		a.routeRunning = append(a.routeRunning, running)
+		cmds = append(cmds, running.next())
+	}
+	return tea.Batch(cmds...)
+}
+
+// activated runs what follows the Init of a newly active controller, its
+// queued events and its subscriptions
+func (a *Application[M]) activated(ctlr Controller) tea.Cmd {
+	return tea.Batch(a.events.activate(ctlr), a.restartRouteSubscriptions(ctlr))
+}
+
+// stopSubscriptions stops every running subscription
+func (a *Application[M]) stopSubscriptions() {
+	for _, running := range append(a.running, a.routeRunning...) {
+		running.stop()
+	}
+	a.running = nil
+	a.routeRunning = nil
+}
+
+// deliveredMsg returns the message a subscription delivers in place of its
+// wrapper, which devtools and tracing show
+func deliveredMsg(msg tea.Msg) tea.Msg {
+	switch msg := msg.(type) {
+	case subscriptionMsg:
+		return msg.msg
+	case subscriptionEndedMsg:
+		return SubscriptionEndedMsg{Name: msg.sub.Name, Err: msg.sub.err}
+	}
+	return msg
+}
+
+func (a *Application[M]) handleSubscriptionMsg(msg subscriptionMsg, ctx context.Context) (tea.Model, tea.Cmd) {
+	if msg.sub.stopped() {
+		return a, nil
+	}
+
+	model, cmd := a.update(msg.msg, ctx)
+	if msg.sub.stopped() {
+		return model, cmd
+	}
+	return model, tea.Batch(cmd, msg.sub.next())
+}
+
+func (a *Application[M]) handleSubscriptionEnded(msg subscriptionEndedMsg, ctx context.Context) (tea.Model, tea.Cmd) {
+	if msg.sub.stopped() {
+		return a, nil
+	}
+
+	err := msg.sub.err
+	if err != nil {
+		slog.Error("Subscription failed", "name", msg.sub.Name, "error", err)
+		a.Errors = append(a.Errors, err)
+	} else {
+		slog.Debug("Subscription ended", "name", msg.sub.Name)
+	}
+	return a.update(SubscriptionEndedMsg{Name: msg.sub.Name, Err: err}, ctx)
+}
@@ -0,0 +1,190 @@
+package mvct
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ReceiverController records the messages of subscriptions
+type ReceiverController struct {
+	MockController
+	received []string
+	ended    *SubscriptionEndedMsg
+}
+
+func (c *ReceiverController) OnString(msg string) Cmd {
+	c.received = append(c.received, msg)
+	return nil
+}
+
+func (c *ReceiverController) OnSubscriptionEndedMsg(msg SubscriptionEndedMsg) Cmd {
+	c.ended = &msg
+	return nil
+}
+
+// FeedController subscribes to a channel while it is active
+type FeedController struct {
+	ReceiverController
+	feed    chan string
+	started int
+}
+
+func (c *FeedController) Subscriptions() []Subscription {
+	c.started++
+	return []Subscription{Channel(c.feed)}
+}
+
+// nextSubscriptionMsg runs the commands until a subscription message arrives
+func nextSubscriptionMsg(t *testing.T, cmd tea.Cmd) tea.Msg {
+	t.Helper()
+	done := make(chan tea.Msg, 1)
+	go func() {
+		for _, msg := range collectMsgs(cmd) {
+			inner := msg
+			if traced, ok := msg.(Msg); ok {
+				inner = traced.Inner
+			}
+			switch inner.(type) {
+			case subscriptionMsg, subscriptionEndedMsg:
+				done <- msg
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	select {
+	case msg := <-done:
+		if msg == nil {
+			t.Fatal("expected a subscription command")
+		}
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a subscription message")
+	}
+	return nil
+}
+
+func TestSubscriptions_RouteLifecycle(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/feed"}, "model")
+	feed := &FeedController{feed: make(chan string)}
+	app.RegisterController("/feed", feed)
+	app.RegisterController("/other", &MockController{name: "other"})
+
+	cmd := app.Init()
+	go func() { feed.feed <- "one" }()
+	_, cmd = app.Update(nextSubscriptionMsg(t, cmd))
+	if len(feed.received) != 1 || feed.received[0] != "one" {
+		t.Fatalf("expected the channel value to reach the controller, got %v", feed.received)
+	}
+
+	stopped := app.routeRunning[0]
+	app.Update(NavigateMsg{Route: "/other"})
+	if !stopped.stopped() {
+		t.Error("expected the subscription to stop on navigation")
+	}
+	if _, next := app.Update(nextSubscriptionMsg(t, cmd)); next != nil {
+		t.Error("a stopped subscription should not be read again")
+	}
+
+	_, cmd = app.Update(NavigateMsg{Route: "/feed"})
+	if feed.started != 2 {
+		t.Errorf("expected the subscriptions to restart on return, got %d starts", feed.started)
+	}
+	close(feed.feed)
+	app.Update(nextSubscriptionMsg(t, cmd))
+	if feed.ended == nil || feed.ended.Err != nil {
+		t.Errorf("expected a SubscriptionEndedMsg without error, got %+v", feed.ended)
+	}
+}
+
+func TestSubscriptions_Application(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	feed := &ReceiverController{}
+	app.RegisterController("/home", feed)
+
+	failure := errors.New("connection lost")
+	app.Subscribe(Subscription{
+		Name: "socket",
+		Run: func(ctx context.Context, send func(msg tea.Msg)) error {
+			send("hello")
+			return failure
+		},
+	})
+
+	cmd := app.Init()
+	_, cmd = app.Update(nextSubscriptionMsg(t, cmd))
+	if len(feed.received) != 1 {
+		t.Fatalf("expected the app subscription to reach the controller, got %v", feed.received)
+	}
+
+	app.Update(nextSubscriptionMsg(t, cmd))
+	if feed.ended == nil || !errors.Is(feed.ended.Err, failure) {
+		t.Errorf("expected the failure to be reported, got %+v", feed.ended)
+	}
+	if len(app.Errors) != 1 {
+		t.Errorf("expected the failure in app errors, got %v", app.Errors)
+	}
+	app.stopSubscriptions()
+}
+
+func TestWatchFiles(t *testing.T) {
+	dir := t.TempDir()
+	sub := WatchFiles(dir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	msgs := make(chan tea.Msg, 8)
+	errs := make(chan error, 1)
+	go func() { errs <- sub.Run(ctx, func(msg tea.Msg) { msgs <- msg }) }()
+
+	path := filepath.Join(dir, "config.json")
+	deadline := time.After(2 * time.Second)
+	for {
+		// the watcher may not be ready for the first write
+		os.WriteFile(path, []byte("{}"), 0644)
+		select {
+		case msg := <-msgs:
+			if changed, ok := msg.(FileChangedMsg); !ok || changed.Path != path {
+				t.Errorf("expected a change of %s, got %v", path, msg)
+			}
+			cancel()
+			if err := <-errs; !errors.Is(err, context.Canceled) {
+				t.Errorf("expected context.Canceled, got %v", err)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("timed out waiting for a file change")
+		}
+	}
+}
+
+func TestSubscriptions_LoggedAndTracedOnce(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/feed"}, "model")
+	feed := &FeedController{feed: make(chan string)}
+	app.RegisterController("/feed", feed)
+	app.UseDevtools(DevtoolsConfig{})
+	exporter := &memoryExporter{}
+	app.UseTracing(TracingConfig{Exporter: exporter})
+
+	cmd := app.Init()
+	go func() { feed.feed <- "one" }()
+	app.Update(nextSubscriptionMsg(t, cmd))
+	if err := app.tracer.close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(app.devtools.messages) != 1 || app.devtools.messages[0].typ() != "string" {
+		t.Errorf("expected the delivered message logged once, got %+v", app.devtools.messages)
+	}
+	updates := exporter.named("mvct.update")
+	if len(updates) != 1 || attr(updates[0], "mvct.msg.type") != "string" {
+		t.Errorf("expected one update span for the delivered message, got %+v", updates)
+	}
+}
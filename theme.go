@@ -0,0 +1,358 @@
+package mvct
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// ThemeColor is a color token of a theme. Hex is used on true color and
+// 256 color terminals, ANSI is the 0-15 color used on 16 color terminals.
+// Without ANSI the hex color is approximated. In JSON a color is either a
+// hex string or an object with hex and ansi keys
+type ThemeColor struct {
+	Hex  string `json:"hex"`
+	ANSI string `json:"ansi,omitempty"`
+}
+
+// Color returns the lipgloss color of the token, lipgloss drops it on
+// terminals without color support or when NO_COLOR is set
+func (c ThemeColor) Color() lipgloss.TerminalColor {
+	switch {
+	case c.Hex == "" && c.ANSI == "":
+		return lipgloss.NoColor{}
+	case c.ANSI == "":
+		return lipgloss.Color(c.Hex)
+	case c.Hex == "":
+		return lipgloss.Color(c.ANSI)
+	}
+	return lipgloss.CompleteColor{TrueColor: c.Hex, ANSI256: c.Hex, ANSI: c.ANSI}
+}
+
+func (c *ThemeColor) UnmarshalJSON(data []byte) error {
+	var hex string
+	if err := json.Unmarshal(data, &hex); err == nil {
+		*c = ThemeColor{Hex: hex}
+		return nil
+	}
+
+	type plain ThemeColor
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+// Theme maps semantic tokens to colors. Components and layouts render with
+// the tokens of CurrentTheme so switching themes restyles the whole app
+type Theme struct {
+	Name string `json:"name"`
+	// Extends names the theme missing tokens are copied from when the theme
+	// is loaded from a file, dark by default
+	Extends string `json:"extends,omitempty"`
+
+	Primary    ThemeColor `json:"primary"`
+	Text       ThemeColor `json:"text"`
+	Muted      ThemeColor `json:"muted"`
+	Selected   ThemeColor `json:"selected"`
+	Border     ThemeColor `json:"border"`
+	Background ThemeColor `json:"background"`
+	Error      ThemeColor `json:"error"`
+	Warning    ThemeColor `json:"warning"`
+	Success    ThemeColor `json:"success"`
+	Info       ThemeColor `json:"info"`
+}
+
+// Style returns a style with the foreground set to the token
+func (t Theme) Style(token ThemeColor) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(token.Color())
+}
+
+// NoColor reports whether the terminal renders without colors, because it
+// has no color support or NO_COLOR is set. Styles should then rely on
+// bold, underline and reverse to stand out
+func NoColor() bool {
+	return lipgloss.ColorProfile() == termenv.Ascii
+}
+
+// inherit fills the empty tokens of t from base
+func (t Theme) inherit(base Theme) Theme {
+	for _, token := range []struct{ dst, src *ThemeColor }{
+		{&t.Primary, &base.Primary},
+		{&t.Text, &base.Text},
+		{&t.Muted, &base.Muted},
+		{&t.Selected, &base.Selected},
+		{&t.Border, &base.Border},
+		{&t.Background, &base.Background},
+		{&t.Error, &base.Error},
+		{&t.Warning, &base.Warning},
+		{&t.Success, &base.Success},
+		{&t.Info, &base.Info},
+	} {
+		if token.dst.Hex == "" && token.dst.ANSI == "" {
+			*token.dst = *token.src
+		}
+	}
+	return t
+}
+
+// Built-in themes, registered under their names
+var (
+	DarkTheme = Theme{
+		Name:       "dark",
+		Primary:    ThemeColor{Hex: "#7C3AED", ANSI: "5"},
+		Text:       ThemeColor{Hex: "#F3F4F6", ANSI: "7"},
+		Muted:      ThemeColor{Hex: "#9CA3AF", ANSI: "8"},
+		Selected:   ThemeColor{Hex: "#10B981", ANSI: "2"},
+		Border:     ThemeColor{Hex: "#374151", ANSI: "8"},
+		Error:      ThemeColor{Hex: "#EF4444", ANSI: "1"},
+		Warning:    ThemeColor{Hex: "#F59E0B", ANSI: "3"},
+		Success:    ThemeColor{Hex: "#10B981", ANSI: "2"},
+		Info:       ThemeColor{Hex: "#3B82F6", ANSI: "4"},
+		Background: ThemeColor{Hex: "#1F2937", ANSI: "0"},
+	}
+
+	LightTheme = Theme{
+		Name:       "light",
+		Primary:    ThemeColor{Hex: "#6D28D9", ANSI: "5"},
+		Text:       ThemeColor{Hex: "#111827", ANSI: "0"},
+		Muted:      ThemeColor{Hex: "#6B7280", ANSI: "8"},
+		Selected:   ThemeColor{Hex: "#047857", ANSI: "2"},
+		Border:     ThemeColor{Hex: "#D1D5DB", ANSI: "7"},
+		Error:      ThemeColor{Hex: "#B91C1C", ANSI: "1"},
+		Warning:    ThemeColor{Hex: "#B45309", ANSI: "3"},
+		Success:    ThemeColor{Hex: "#047857", ANSI: "2"},
+		Info:       ThemeColor{Hex: "#1D4ED8", ANSI: "4"},
+		Background: ThemeColor{Hex: "#F9FAFB", ANSI: "15"},
+	}
+
+	HighContrastTheme = Theme{
+		Name:       "high-contrast",
+		Primary:    ThemeColor{Hex: "#FFFF00", ANSI: "11"},
+		Text:       ThemeColor{Hex: "#FFFFFF", ANSI: "15"},
+		Muted:      ThemeColor{Hex: "#C0C0C0", ANSI: "7"},
+		Selected:   ThemeColor{Hex: "#00FFFF", ANSI: "14"},
+		Border:     ThemeColor{Hex: "#FFFFFF", ANSI: "15"},
+		Error:      ThemeColor{Hex: "#FF5555", ANSI: "9"},
+		Warning:    ThemeColor{Hex: "#FFFF00", ANSI: "11"},
+		Success:    ThemeColor{Hex: "#00FF00", ANSI: "10"},
+		Info:       ThemeColor{Hex: "#00FFFF", ANSI: "14"},
+		Background: ThemeColor{Hex: "#000000", ANSI: "0"},
+	}
+)
+
+var (
+	themesMu sync.RWMutex
+	themes   = map[string]Theme{
+		DarkTheme.Name:         DarkTheme,
+		LightTheme.Name:        LightTheme,
+		HighContrastTheme.Name: HighContrastTheme,
+	}
+
+	currentTheme atomic.Pointer[Theme]
+	themeVersion atomic.Uint64
+)
+
+func init() {
+	theme := DarkTheme
+	currentTheme.Store(&theme)
+}
+
+// RegisterTheme adds a theme to the registry, replacing a theme with the
+// same name
+func RegisterTheme(theme Theme) error {
+	if theme.Name == "" {
+		return errors.New("theme has no name")
+	}
+
+	themesMu.Lock()
+	defer themesMu.Unlock()
+	themes[theme.Name] = theme
+	slog.Debug("Registered theme", "name", theme.Name)
+	return nil
+}
+
+// LookupTheme returns a registered theme
+func LookupTheme(name string) (Theme, bool) {
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+	theme, ok := themes[name]
+	return theme, ok
+}
+
+// ThemeNames returns the names of the registered themes in sorted order
+func ThemeNames() []string {
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// LoadTheme reads a JSON theme file. The name defaults to the file name
+// and missing tokens are taken from the theme it extends
+func LoadTheme(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, fmt.Errorf("failed to read theme: %w", err)
+	}
+
+	var theme Theme
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return Theme{}, fmt.Errorf("failed to parse theme %s: %w", path, err)
+	}
+
+	if theme.Name == "" {
+		theme.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if theme.Extends == "" {
+		theme.Extends = DarkTheme.Name
+	}
+	base, ok := LookupTheme(theme.Extends)
+	if !ok {
+		return Theme{}, fmt.Errorf("theme %s extends unknown theme %q", theme.Name, theme.Extends)
+	}
+
+	return theme.inherit(base), nil
+}
+
+// LoadThemes registers every *.json theme in dir
+func LoadThemes(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to list themes: %w", err)
+	}
+
+	for _, path := range paths {
+		theme, err := LoadTheme(path)
+		if err != nil {
+			return err
+		}
+		if err := RegisterTheme(theme); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CurrentTheme returns the active theme
+func CurrentTheme() Theme {
+	return *currentTheme.Load()
+}
+
+// ThemeVersion changes every time the active theme changes, renderers
+// caching styles built from the theme compare it to know when to rebuild
+func ThemeVersion() uint64 {
+	return themeVersion.Load()
+}
+
+func applyTheme(theme Theme) {
+	currentTheme.Store(&theme)
+	themeVersion.Add(1)
+	slog.Info("Theme applied", "name", theme.Name)
+}
+
+// ThemeConfig configures the theme of an application
+type ThemeConfig struct {
+	// Name of the theme to start with. Empty picks light or dark from the
+	// terminal background
+	Name string
+	// Dir holds JSON themes registered before Name is resolved
+	Dir string
+}
+
+// UseTheme loads the configured themes and applies the starting theme
+func (a *Application[M]) UseTheme(config ThemeConfig) error {
+	if config.Dir != "" {
+		if err := LoadThemes(config.Dir); err != nil {
+			return err
+		}
+	}
+
+	name := config.Name
+	if name == "" {
+		name = DarkTheme.Name
+		if !lipgloss.HasDarkBackground() {
+			name = LightTheme.Name
+		}
+	}
+
+	theme, ok := LookupTheme(name)
+	if !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+	applyTheme(theme)
+	return nil
+}
+
+// SetThemeMsg asks the application to switch themes
+type SetThemeMsg struct {
+	Name string
+	// Next switches to the theme after the current one instead of Name
+	Next bool
+}
+
+// ThemeChangedMsg is sent to the active controller after the theme
+// changes, controllers caching styles rebuild them in its handler
+type ThemeChangedMsg struct {
+	Theme Theme
+}
+
+// SetTheme switches to a registered theme and re-renders
+func SetTheme(name string) Cmd {
+	return func() Msg {
+		return Msg{
+			Inner: SetThemeMsg{Name: name},
+		}
+	}
+}
+
+// NextTheme switches to the next registered theme in name order
+func NextTheme() Cmd {
+	return func() Msg {
+		return Msg{
+			Inner: SetThemeMsg{Next: true},
+		}
+	}
+}
+
+// ThemeSwitchHandler cycles through the registered themes on the given keys
+func ThemeSwitchHandler(keys ...string) GlobalHandler {
+	return GlobalHandlerFunc(func(msg tea.KeyMsg) tea.Cmd {
+		if slices.Contains(keys, msg.String()) {
+			return unwrapCmd(NextTheme())
+		}
+		return nil
+	})
+}
+
+func (a *Application[M]) handleSetTheme(msg SetThemeMsg) (tea.Model, tea.Cmd) {
+	name := msg.Name
+	if msg.Next {
+		names := ThemeNames()
+		i := slices.Index(names, CurrentTheme().Name)
+		name = names[(i+1)%len(names)]
+	}
+
+	theme, ok := LookupTheme(name)
+	if !ok {
+		err := fmt.Errorf("unknown theme %q", name)
+		slog.Error("Theme switch failed", "error", err)
+		a.Errors = append(a.Errors, err)
+		return a, unwrapCmd(Notify(NotifyError, err.Error()))
+	}
+
+	applyTheme(theme)
+	return a, func() tea.Msg { return ThemeChangedMsg{Theme: theme} }
+}
@@ -0,0 +1,91 @@
+package mvct
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestThemeColor(t *testing.T) {
+	if _, ok := (ThemeColor{}).Color().(lipgloss.NoColor); !ok {
+		t.Error("empty token should have no color")
+	}
+	if c, ok := (ThemeColor{Hex: "#FFFFFF"}).Color().(lipgloss.Color); !ok || c != "#FFFFFF" {
+		t.Errorf("expected hex color, got %v", c)
+	}
+	c, ok := (ThemeColor{Hex: "#FFFFFF", ANSI: "15"}).Color().(lipgloss.CompleteColor)
+	if !ok || c.ANSI != "15" || c.TrueColor != "#FFFFFF" {
+		t.Errorf("expected a color with a 16 color fallback, got %v", c)
+	}
+}
+
+func TestLoadThemes(t *testing.T) {
+	dir := t.TempDir()
+	content := `{
+		"extends": "light",
+		"primary": "#FF00FF",
+		"error": {"hex": "#AA0000", "ansi": "1"}
+	}`
+	if err := os.WriteFile(filepath.Join(dir, "solar.json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadThemes(dir); err != nil {
+		t.Fatalf("LoadThemes failed: %v", err)
+	}
+
+	theme, ok := LookupTheme("solar")
+	if !ok {
+		t.Fatalf("expected solar to be registered, got %v", ThemeNames())
+	}
+	if theme.Primary.Hex != "#FF00FF" {
+		t.Errorf("expected primary #FF00FF, got %s", theme.Primary.Hex)
+	}
+	if theme.Error.Hex != "#AA0000" || theme.Error.ANSI != "1" {
+		t.Errorf("expected error with ansi fallback, got %+v", theme.Error)
+	}
+	if theme.Text != LightTheme.Text {
+		t.Errorf("expected text inherited from light, got %+v", theme.Text)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte(`{"extends": "missing"}`), 0644)
+	if _, err := LoadTheme(bad); err == nil {
+		t.Error("expected an error for an unknown base theme")
+	}
+}
+
+func TestApplicationUpdate_SetTheme(t *testing.T) {
+	t.Cleanup(func() { applyTheme(DarkTheme) })
+
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.Init()
+
+	version := ThemeVersion()
+	_, cmd := app.Update(SetThemeMsg{Name: "light"})
+	if CurrentTheme().Name != "light" {
+		t.Errorf("expected light theme, got %s", CurrentTheme().Name)
+	}
+	if ThemeVersion() == version {
+		t.Error("expected the theme version to change")
+	}
+	if changed, ok := cmd().(ThemeChangedMsg); !ok || changed.Theme.Name != "light" {
+		t.Errorf("expected ThemeChangedMsg for light, got %v", cmd())
+	}
+
+	app.Update(SetThemeMsg{Next: true})
+	names := ThemeNames()
+	for i, name := range names {
+		if name == "light" && CurrentTheme().Name != names[(i+1)%len(names)] {
+			t.Errorf("expected the theme after light, got %s", CurrentTheme().Name)
+		}
+	}
+
+	app.Update(SetThemeMsg{Name: "missing"})
+	if len(app.Errors) != 1 {
+		t.Errorf("expected an error for an unknown theme, got %v", app.Errors)
+	}
+}
@@ -0,0 +1,479 @@
+package mvct
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TraceID identifies a trace, like an OpenTelemetry or W3C trace id
+type TraceID [16]byte
+
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+// IsValid reports whether the id is not all zeros
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+// SpanID identifies a span within its trace
+type SpanID [8]byte
+
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+// IsValid reports whether the id is not all zeros
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+// SpanContext identifies a span
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// IsValid reports whether both ids are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent returns the W3C traceparent header of the span, to continue
+// the trace in a request to another service
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-01", sc.TraceID, sc.SpanID)
+}
+
+// StatusCode is the status of a span, the values match OpenTelemetry
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = iota
+	StatusOK
+	StatusError
+)
+
+// Attribute is a key value pair of a span or a metric. Values are strings,
+// bools, integers or floats like in OpenTelemetry, other values are
+// exported with fmt.Sprint
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Attr creates an Attribute
+func Attr(key string, value any) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// SpanData is a finished span handed to the Exporter
+type SpanData struct {
+	Name        string
+	SpanContext SpanContext
+	// Parent is the id of the parent span, zero for the root of a trace
+	Parent        SpanID
+	Start         time.Time
+	End           time.Time
+	Attributes    []Attribute
+	Status        StatusCode
+	StatusMessage string
+	// Resource describes the app, shared by every span
+	Resource []Attribute
+}
+
+// Span is a span being recorded. Its methods do nothing on a nil Span, which
+// StartSpan returns when the app doesn't trace
+type Span struct {
+	tracer *tracer
+	data   SpanData
+	// metric records the duration in a histogram named after the span, by
+	// its attributes. Only the spans of the framework have few enough
+	// distinct attributes
+	metric bool
+	mu     sync.Mutex
+	ended  bool
+}
+
+// SpanContext returns the ids of the span
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.data.SpanContext
+}
+
+// SetAttributes adds attributes to the span
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+}
+
+// SetError marks the span as failed with err, a nil err does nothing
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Status = StatusError
+	s.data.StatusMessage = err.Error()
+}
+
+// End finishes the span, it is exported with the next batch
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	s.tracer.finish(data)
+	if s.metric {
+		s.tracer.histogram(data.Name+".duration", "Duration of "+data.Name, float64(data.End.Sub(data.Start))/float64(time.Millisecond), data.Attributes)
+	}
+}
+
+type spanKey struct{}
+
+// SpanFromContext returns the span in ctx, nil when there is none
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// StartSpan starts a child of the span in ctx, like the context of a job or
+// the Msg.Context of a traced command. Without a span in ctx the app isn't
+// traced and StartSpan returns ctx and a nil Span
+func StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	return parent.tracer.start(ctx, name, false, attrs...)
+}
+
+// Exporter sends spans and metrics somewhere, like a file or an
+// OpenTelemetry collector. It is called from a single goroutine
+type Exporter interface {
+	ExportSpans(ctx context.Context, spans []SpanData) error
+	ExportMetrics(ctx context.Context, metrics []MetricData) error
+	Shutdown(ctx context.Context) error
+}
+
+// TracingConfig configures tracing
+type TracingConfig struct {
+	// Exporter receives the finished spans and the metrics
+	Exporter Exporter
+	// ServiceName is the service.name of the resource, the name of the
+	// executable when empty
+	ServiceName string
+	// Resource holds more attributes describing the app, like
+	// service.version
+	Resource []Attribute
+	// ExportInterval is how often spans and metrics are exported, 5s when
+	// zero. Run exports the rest when it returns
+	ExportInterval time.Duration
+}
+
+// maxQueuedSpans exports the spans before the interval when this many are
+// queued
+const maxQueuedSpans = 512
+
+// tracer records spans and metrics and exports them in the background
+type tracer struct {
+	config   TracingConfig
+	resource []Attribute
+
+	mu      sync.Mutex
+	spans   []SpanData
+	metrics *meter
+
+	flush    chan struct{}
+	stop     chan struct{}
+	stopped  chan struct{}
+	shutdown sync.Once
+}
+
+// UseTracing traces the app. Every Update, handler call, View, navigation,
+// middleware and command gets a span, and their durations and the
+// navigations are recorded as metrics. A command's span travels in the
+// Msg.Context of its result, so the Update it causes joins its trace
+func (a *Application[M]) UseTracing(config TracingConfig) error {
+	if config.Exporter == nil {
+		return fmt.Errorf("tracing needs an exporter")
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	}
+	if config.ExportInterval <= 0 {
+		config.ExportInterval = 5 * time.Second
+	}
+	slog.Debug("Configuring tracing", "service", config.ServiceName, "exporter", reflect.TypeOf(config.Exporter))
+
+	t := &tracer{
+		config:   config,
+		resource: append([]Attribute{Attr("service.name", config.ServiceName)}, config.Resource...),
+		metrics:  newMeter(),
+		flush:    make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go t.run()
+	a.tracer = t
+	a.router.handleMiddleware = a.traceMiddleware
+	return nil
+}
+
+func (t *tracer) start(ctx context.Context, name string, metric bool, attrs ...Attribute) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	span := &Span{
+		tracer: t,
+		data: SpanData{
+			Name:       name,
+			Start:      time.Now(),
+			Attributes: slices.Clone(attrs),
+			Resource:   t.resource,
+		},
+		metric: metric,
+	}
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.data.SpanContext.TraceID = parent.data.SpanContext.TraceID
+		span.data.Parent = parent.data.SpanContext.SpanID
+	} else {
+		binary.BigEndian.PutUint64(span.data.SpanContext.TraceID[:8], rand.Uint64())
+		binary.BigEndian.PutUint64(span.data.SpanContext.TraceID[8:], rand.Uint64())
+	}
+	binary.BigEndian.PutUint64(span.data.SpanContext.SpanID[:], rand.Uint64()|1)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (t *tracer) finish(data SpanData) {
+	t.mu.Lock()
+	t.spans = append(t.spans, data)
+	full := len(t.spans) >= maxQueuedSpans
+	t.mu.Unlock()
+	if full {
+		select {
+		case t.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run exports every interval, when the queue is full and once more when
+// the tracer shuts down
+func (t *tracer) run() {
+	defer close(t.stopped)
+	ticker := time.NewTicker(t.config.ExportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			t.export(true)
+			return
+		case <-ticker.C:
+			t.export(true)
+		case <-t.flush:
+			t.export(false)
+		}
+	}
+}
+
+func (t *tracer) export(withMetrics bool) {
+	t.mu.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.mu.Unlock()
+
+	ctx := context.Background()
+	if len(spans) > 0 {
+		if err := t.config.Exporter.ExportSpans(ctx, spans); err != nil {
+			slog.Error("Failed to export spans", "count", len(spans), "error", err)
+		}
+	}
+	if withMetrics {
+		if metrics := t.metrics.collect(t.resource); len(metrics) > 0 {
+			if err := t.config.Exporter.ExportMetrics(ctx, metrics); err != nil {
+				slog.Error("Failed to export metrics", "error", err)
+			}
+		}
+	}
+}
+
+// close exports what is left and shuts the exporter down, Run calls it
+func (t *tracer) close(ctx context.Context) error {
+	var err error
+	t.shutdown.Do(func() {
+		close(t.stop)
+		select {
+		case <-t.stopped:
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+		err = t.config.Exporter.Shutdown(ctx)
+	})
+	return err
+}
+
+func (t *tracer) histogram(name, description string, value float64, attrs []Attribute) {
+	t.metrics.histogram(name, description, "ms", value, attrs)
+}
+
+// traceCmd runs cmd in a span that is a child of ctx. Its message carries
+// the span in Msg.Context so the Update it causes continues the trace.
+// Messages of bubbletea itself, like tea.QuitMsg, are returned as they are
+// and the commands of a tea.BatchMsg are traced one by one
+func (t *tracer) traceCmd(ctx context.Context, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		cmdCtx, span := t.start(ctx, "mvct.command", true)
+		msg := cmd()
+
+		switch msg := msg.(type) {
+		case nil:
+			span.End()
+			return nil
+		case Msg:
+			// the command chose its context, like the span of a job
+			span.SetAttributes(Attr("mvct.msg.type", msgType(msg.Inner)))
+			span.End()
+			return msg
+		case tea.BatchMsg:
+			// the batch itself does nothing worth a span
+			for i, c := range msg {
+				msg[i] = t.traceCmd(ctx, c)
+			}
+			return msg
+		}
+		span.SetAttributes(Attr("mvct.msg.type", msgType(msg)))
+		span.End()
+		if teaMsg(msg) {
+			return msg
+		}
+		return Msg{Inner: msg, Context: cmdCtx}
+	}
+}
+
+// teaMsg reports whether msg is a message of bubbletea itself, which the
+// program handles before Update and must not be wrapped
+func teaMsg(msg tea.Msg) bool {
+	return reflect.TypeOf(msg).PkgPath() == reflect.TypeFor[tea.QuitMsg]().PkgPath()
+}
+
+func msgType(msg tea.Msg) string {
+	return fmt.Sprintf("%T", msg)
+}
+
+// traceCtx returns the context of the span being handled, the parent of
+// new spans
+func (a *Application[M]) traceCtx() context.Context {
+	if a.spanCtx != nil {
+		return a.spanCtx
+	}
+	return context.Background()
+}
+
+// traced runs fn in a span that is the parent of the spans fn starts
+func (a *Application[M]) traced(name string, attrs []Attribute, fn func(span *Span)) {
+	if a.tracer == nil {
+		fn(nil)
+		return
+	}
+	previous := a.spanCtx
+	var span *Span
+	a.spanCtx, span = a.tracer.start(a.traceCtx(), name, true, attrs...)
+	defer func() {
+		span.End()
+		a.spanCtx = previous
+	}()
+	fn(span)
+}
+
+// traceUpdate runs Update in a span that continues the trace of the command
+// that sent msg, its commands are traced below it
+func (a *Application[M]) traceUpdate(msg tea.Msg, ctx context.Context, update func(ctx context.Context) (tea.Model, tea.Cmd)) (tea.Model, tea.Cmd) {
+	previous := a.spanCtx
+	a.spanCtx = ctx
+	var model tea.Model
+	var cmd tea.Cmd
+	a.traced("mvct.update", []Attribute{Attr("mvct.msg.type", msgType(msg)), Attr("mvct.route", a.router.CurrentRoute())}, func(*Span) {
+		model, cmd = update(a.spanCtx)
+		cmd = a.tracer.traceCmd(a.spanCtx, cmd)
+		// View renders what this Update changed
+		a.viewCtx = a.spanCtx
+	})
+	a.spanCtx = previous
+	return model, cmd
+}
+
+// traceHandler runs a key or message handler in a span
+func (a *Application[M]) traceHandler(name string, msg tea.Msg, call func() tea.Cmd) tea.Cmd {
+	var cmd tea.Cmd
+	a.traced("mvct.handler", []Attribute{Attr("mvct.handler", name), Attr("mvct.msg.type", msgType(msg))}, func(*Span) {
+		cmd = call()
+	})
+	return cmd
+}
+
+// globalHandlerName names a global handler by its type, or by the function
+// of a GlobalHandlerFunc
+func globalHandlerName(handler GlobalHandler) string {
+	if f, ok := handler.(GlobalHandlerFunc); ok {
+		return funcName(f)
+	}
+	return reflect.TypeOf(handler).String()
+}
+
+// traceMiddleware runs a middleware of a navigation in a span
+func (a *Application[M]) traceMiddleware(m Middleware, ctx *Context) bool {
+	allowed := false
+	a.traced("mvct.middleware", []Attribute{Attr("mvct.middleware", middlewareName(m))}, func(span *Span) {
+		allowed = m.Handle(ctx)
+		span.SetAttributes(Attr("mvct.allowed", allowed))
+	})
+	return allowed
+}
+
+// recordNavigation counts a navigation by its result in the
+// mvct.navigations metric
+func (a *Application[M]) recordNavigation(span *Span, to string, err error) {
+	if a.tracer == nil {
+		return
+	}
+	result := "allowed"
+	if err != nil {
+		result = "blocked"
+		if _, ok := a.router.routes[to]; !ok {
+			result = "not_found"
+		}
+		span.SetError(err)
+	}
+	span.SetAttributes(Attr("mvct.navigation.result", result))
+	a.tracer.metrics.counter("mvct.navigations", "Navigations by their result", []Attribute{Attr("mvct.route.to", to), Attr("mvct.navigation.result", result)})
+}
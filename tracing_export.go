@@ -0,0 +1,316 @@
+package mvct
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricKind is the kind of a metric
+type MetricKind int
+
+const (
+	// MetricCounter counts events, like navigations
+	MetricCounter MetricKind = iota
+	// MetricHistogram records the distribution of values, like durations
+	MetricHistogram
+)
+
+// MetricData is a metric handed to the Exporter. Its points are cumulative
+// since the app started, like OpenTelemetry's cumulative temporality
+type MetricData struct {
+	Name        string
+	Description string
+	Unit        string
+	Kind        MetricKind
+	Points      []MetricPoint
+	// Resource describes the app, shared by every metric
+	Resource []Attribute
+}
+
+// MetricPoint is the value of a metric for one set of attributes
+type MetricPoint struct {
+	Attributes []Attribute
+	Start      time.Time
+	Time       time.Time
+	// Count is the number of recorded values, the value of a counter
+	Count uint64
+	// Sum, Min and Max of the values of a histogram
+	Sum float64
+	Min float64
+	Max float64
+	// BucketCounts counts the values of a histogram up to each of Bounds,
+	// the last bucket counts the values above them
+	Bounds       []float64
+	BucketCounts []uint64
+}
+
+// durationBounds are the default histogram buckets of OpenTelemetry, in
+// milliseconds
+var durationBounds = []float64{0, 5, 10, 25, 50, 75, 100, 250, 500, 750, 1000, 2500, 5000, 7500, 10000}
+
+// meter aggregates the metrics between exports
+type meter struct {
+	mu      sync.Mutex
+	start   time.Time
+	metrics map[string]*metric
+}
+
+type metric struct {
+	description string
+	unit        string
+	kind        MetricKind
+	points      map[string]*MetricPoint
+}
+
+func newMeter() *meter {
+	return &meter{start: time.Now(), metrics: make(map[string]*metric)}
+}
+
+func (m *meter) point(name, description, unit string, kind MetricKind, attrs []Attribute) *MetricPoint {
+	met, ok := m.metrics[name]
+	if !ok {
+		met = &metric{description: description, unit: unit, kind: kind, points: make(map[string]*MetricPoint)}
+		m.metrics[name] = met
+	}
+	var key strings.Builder
+	for _, attr := range attrs {
+		fmt.Fprintf(&key, "%s=%v\x00", attr.Key, attr.Value)
+	}
+	point, ok := met.points[key.String()]
+	if !ok {
+		point = &MetricPoint{Attributes: attrs, Start: m.start}
+		if kind == MetricHistogram {
+			point.Min, point.Max = math.Inf(1), math.Inf(-1)
+			point.Bounds = durationBounds
+			point.BucketCounts = make([]uint64, len(durationBounds)+1)
+		}
+		met.points[key.String()] = point
+	}
+	return point
+}
+
+func (m *meter) counter(name, description string, attrs []Attribute) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.point(name, description, "1", MetricCounter, attrs).Count++
+}
+
+func (m *meter) histogram(name, description, unit string, value float64, attrs []Attribute) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	point := m.point(name, description, unit, MetricHistogram, attrs)
+	point.Count++
+	point.Sum += value
+	point.Min = min(point.Min, value)
+	point.Max = max(point.Max, value)
+	bucket, _ := slices.BinarySearch(point.Bounds, value)
+	point.BucketCounts[bucket]++
+}
+
+// collect copies the metrics sorted by name, with their points sorted by
+// attributes
+func (m *meter) collect(resource []Attribute) []MetricData {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var metrics []MetricData
+	for name, met := range m.metrics {
+		data := MetricData{Name: name, Description: met.description, Unit: met.unit, Kind: met.kind, Resource: resource}
+		keys := make([]string, 0, len(met.points))
+		for key := range met.points {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			point := *met.points[key]
+			point.Time = now
+			point.BucketCounts = slices.Clone(point.BucketCounts)
+			data.Points = append(data.Points, point)
+		}
+		metrics = append(metrics, data)
+	}
+	slices.SortFunc(metrics, func(a, b MetricData) int { return strings.Compare(a.Name, b.Name) })
+	return metrics
+}
+
+// jsonExporter writes each export as a line of OTLP JSON
+type jsonExporter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewJSONExporter returns an Exporter writing every batch of spans or
+// metrics as a line of OTLP JSON to w, the format the OpenTelemetry
+// collector's otlpjsonfile receiver reads. Use os.Stdout for apps without a
+// terminal UI, like tests or headless runs
+func NewJSONExporter(w io.Writer) Exporter {
+	return &jsonExporter{w: w}
+}
+
+// NewFileExporter returns a JSON exporter appending to the file at path,
+// closed on Shutdown
+func NewFileExporter(path string) (Exporter, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace file: %w", err)
+	}
+	return &jsonExporter{w: f, closer: f}, nil
+}
+
+func (e *jsonExporter) ExportSpans(ctx context.Context, spans []SpanData) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	otlp := make([]otlpSpan, len(spans))
+	for i, span := range spans {
+		otlp[i] = otlpSpan{
+			TraceID:           span.SpanContext.TraceID.String(),
+			SpanID:            span.SpanContext.SpanID.String(),
+			Name:              span.Name,
+			Kind:              1, // internal
+			StartTimeUnixNano: unixNano(span.Start),
+			EndTimeUnixNano:   unixNano(span.End),
+			Attributes:        otlpAttributes(span.Attributes),
+			Status:            otlpStatus{Code: int(span.Status), Message: span.StatusMessage},
+		}
+		if span.Parent.IsValid() {
+			otlp[i].ParentSpanID = span.Parent.String()
+		}
+	}
+	return e.write(map[string]any{
+		"resourceSpans": []any{map[string]any{
+			"resource":   otlpResource(spans[0].Resource),
+			"scopeSpans": []any{map[string]any{"scope": otlpScope, "spans": otlp}},
+		}},
+	})
+}
+
+func (e *jsonExporter) ExportMetrics(ctx context.Context, metrics []MetricData) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	otlp := make([]map[string]any, len(metrics))
+	for i, m := range metrics {
+		points := make([]map[string]any, len(m.Points))
+		for j, p := range m.Points {
+			point := map[string]any{
+				"attributes":        otlpAttributes(p.Attributes),
+				"startTimeUnixNano": unixNano(p.Start),
+				"timeUnixNano":      unixNano(p.Time),
+			}
+			if m.Kind == MetricCounter {
+				point["asInt"] = strconv.FormatUint(p.Count, 10)
+			} else {
+				counts := make([]string, len(p.BucketCounts))
+				for k, count := range p.BucketCounts {
+					counts[k] = strconv.FormatUint(count, 10)
+				}
+				point["count"] = strconv.FormatUint(p.Count, 10)
+				point["sum"] = p.Sum
+				point["min"] = p.Min
+				point["max"] = p.Max
+				point["bucketCounts"] = counts
+				point["explicitBounds"] = p.Bounds
+			}
+			points[j] = point
+		}
+		metric := map[string]any{"name": m.Name, "description": m.Description, "unit": m.Unit}
+		if m.Kind == MetricCounter {
+			metric["sum"] = map[string]any{"aggregationTemporality": 2, "isMonotonic": true, "dataPoints": points}
+		} else {
+			metric["histogram"] = map[string]any{"aggregationTemporality": 2, "dataPoints": points}
+		}
+		otlp[i] = metric
+	}
+	return e.write(map[string]any{
+		"resourceMetrics": []any{map[string]any{
+			"resource":     otlpResource(metrics[0].Resource),
+			"scopeMetrics": []any{map[string]any{"scope": otlpScope, "metrics": otlp}},
+		}},
+	})
+}
+
+func (e *jsonExporter) Shutdown(ctx context.Context) error {
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
+
+func (e *jsonExporter) write(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err = e.w.Write(append(data, '\n'))
+	return err
+}
+
+var otlpScope = map[string]string{"name": "github.com/michael-duren/mvct"}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+func otlpResource(attrs []Attribute) map[string]any {
+	return map[string]any{"attributes": otlpAttributes(attrs)}
+}
+
+// otlpAttributes encodes attributes as OTLP AnyValues, integers are strings
+// like in the protobuf JSON mapping
+func otlpAttributes(attrs []Attribute) []otlpAttribute {
+	otlp := make([]otlpAttribute, len(attrs))
+	for i, attr := range attrs {
+		var value map[string]any
+		switch v := attr.Value.(type) {
+		case string:
+			value = map[string]any{"stringValue": v}
+		case bool:
+			value = map[string]any{"boolValue": v}
+		case int:
+			value = map[string]any{"intValue": strconv.Itoa(v)}
+		case int64:
+			value = map[string]any{"intValue": strconv.FormatInt(v, 10)}
+		case float64:
+			value = map[string]any{"doubleValue": v}
+		default:
+			value = map[string]any{"stringValue": fmt.Sprint(v)}
+		}
+		otlp[i] = otlpAttribute{Key: attr.Key, Value: value}
+	}
+	return otlp
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
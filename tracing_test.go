@@ -0,0 +1,265 @@
+package mvct
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// memoryExporter keeps what it exports
+type memoryExporter struct {
+	mu      sync.Mutex
+	spans   []SpanData
+	metrics []MetricData
+	closed  bool
+}
+
+func (e *memoryExporter) ExportSpans(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *memoryExporter) ExportMetrics(ctx context.Context, metrics []MetricData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.metrics = metrics
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(ctx context.Context) error {
+	e.closed = true
+	return nil
+}
+
+func (e *memoryExporter) named(name string) []SpanData {
+	var spans []SpanData
+	for _, span := range e.spans {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func (e *memoryExporter) metric(name string) *MetricData {
+	for i := range e.metrics {
+		if e.metrics[i].Name == name {
+			return &e.metrics[i]
+		}
+	}
+	return nil
+}
+
+// tracingController loads on a key and keeps what it loaded
+type tracingController struct {
+	MockController
+	loaded string
+}
+
+func (c *tracingController) Init(handlers KeyHandlers) Cmd {
+	handlers["l"] = func(msg KeyMsg) Cmd {
+		return wrapCmd(context.Background(), func() tea.Msg { return StringMsg{Value: "loaded"} })
+	}
+	return nil
+}
+
+func (c *tracingController) OnStringMsg(msg StringMsg) Cmd {
+	c.loaded = msg.Value
+	return nil
+}
+
+func newTracingApp(t *testing.T) (*Application[string], *memoryExporter) {
+	t.Helper()
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &tracingController{})
+	app.RegisterController("/settings", &MockController{name: "settings"})
+	exporter := &memoryExporter{}
+	if err := app.UseTracing(TracingConfig{Exporter: exporter, ServiceName: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	app.Init()
+	return app, exporter
+}
+
+func closeTracing(t *testing.T, app *Application[string]) {
+	t.Helper()
+	if err := app.tracer.close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTracing_CommandContinuesTrace(t *testing.T) {
+	app, exporter := newTracingApp(t)
+	home := app.router.Current().(*tracingController)
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	if cmd == nil {
+		t.Fatal("expected the key handler's command")
+	}
+	msg := cmd()
+	traced, ok := msg.(Msg)
+	if !ok || SpanFromContext(traced.Context) == nil {
+		t.Fatalf("expected the result in the command's span, got %#v", msg)
+	}
+	app.Update(msg)
+	app.View()
+	if home.loaded != "loaded" {
+		t.Errorf("expected the handler to get the inner message, got %q", home.loaded)
+	}
+	closeTracing(t, app)
+
+	updates := exporter.named("mvct.update")
+	commands := exporter.named("mvct.command")
+	handlers := exporter.named("mvct.handler")
+	views := exporter.named("mvct.view")
+	if len(updates) != 2 || len(commands) != 1 || len(handlers) != 2 || len(views) != 1 {
+		t.Fatalf("expected 2 updates, a command, 2 handlers and a view, got %+v", exporter.spans)
+	}
+	key, command, result := updates[0], commands[0], updates[1]
+	if command.Parent != key.SpanContext.SpanID || result.Parent != command.SpanContext.SpanID {
+		t.Error("expected the key's Update, its command and the result's Update to form a chain")
+	}
+	for _, span := range append(append(handlers, views...), command, result) {
+		if span.SpanContext.TraceID != key.SpanContext.TraceID {
+			t.Errorf("expected %s in the key's trace", span.Name)
+		}
+	}
+	if handlers[0].Parent != key.SpanContext.SpanID || !strings.Contains(attr(handlers[0], "mvct.handler"), "tracingController") {
+		t.Errorf("expected the key handler below the key's Update, got %+v", handlers[0])
+	}
+	if attr(result, "mvct.msg.type") != "mvct.StringMsg" || views[0].Parent != result.SpanContext.SpanID {
+		t.Errorf("expected the view of the result's Update, got %+v", views[0])
+	}
+	if !exporter.closed {
+		t.Error("expected the exporter to shut down")
+	}
+
+	duration := exporter.metric("mvct.update.duration")
+	if duration == nil || duration.Kind != MetricHistogram || len(duration.Points) != 2 {
+		t.Fatalf("expected a duration per message type, got %+v", duration)
+	}
+	if duration.Points[0].Count != 1 || len(duration.Points[0].BucketCounts) != len(durationBounds)+1 {
+		t.Errorf("expected a histogram point, got %+v", duration.Points[0])
+	}
+}
+
+func TestTracing_NavigationAndMiddleware(t *testing.T) {
+	app, exporter := newTracingApp(t)
+	app.Use(LoggingMiddleware())
+	app.Use(AuthMiddleware([]string{"/settings"}, func() bool { return false }))
+
+	app.Update(NavigateMsg{Route: "/settings"})
+	app.Update(NavigateMsg{Route: "/missing"})
+	closeTracing(t, app)
+
+	navigations := exporter.named("mvct.navigate")
+	if len(navigations) != 2 {
+		t.Fatalf("expected 2 navigations, got %d", len(navigations))
+	}
+	blocked := navigations[0]
+	if blocked.Status != StatusError || attr(blocked, "mvct.navigation.result") != "blocked" {
+		t.Errorf("expected a blocked navigation, got %+v", blocked)
+	}
+	middleware := exporter.named("mvct.middleware")
+	if len(middleware) != 2 || middleware[1].Parent != blocked.SpanContext.SpanID || attr(middleware[1], "mvct.allowed") != "false" {
+		t.Errorf("expected the middleware below the navigation, got %+v", middleware)
+	}
+
+	counter := exporter.metric("mvct.navigations")
+	if counter == nil || counter.Kind != MetricCounter || len(counter.Points) != 2 {
+		t.Fatalf("expected a count per route and result, got %+v", counter)
+	}
+	for _, point := range counter.Points {
+		want := "blocked"
+		if point.Attributes[0].Value == "/missing" {
+			want = "not_found"
+		}
+		if result := point.Attributes[1].Value; result != want || point.Count != 1 {
+			t.Errorf("expected %s counted once, got %v %d times", want, result, point.Count)
+		}
+	}
+}
+
+func TestStartSpan(t *testing.T) {
+	if ctx, span := StartSpan(context.Background(), "work"); span != nil || ctx == nil {
+		t.Error("expected no span without tracing")
+	}
+	var span *Span
+	span.SetAttributes(Attr("key", "value"))
+	span.End()
+
+	app, exporter := newTracingApp(t)
+	ctx, parent := app.tracer.start(context.Background(), "parent", false)
+	_, child := StartSpan(ctx, "child", Attr("rows", 3))
+	child.End()
+	parent.End()
+	closeTracing(t, app)
+
+	children := exporter.named("child")
+	if len(children) != 1 || children[0].Parent != parent.SpanContext().SpanID || attr(children[0], "rows") != "3" {
+		t.Errorf("expected a child of the span in ctx, got %+v", children)
+	}
+	if exporter.metric("child.duration") != nil {
+		t.Error("expected no metrics for spans started with StartSpan")
+	}
+	if tp := parent.SpanContext().Traceparent(); len(tp) != 55 || !strings.HasPrefix(tp, "00-") {
+		t.Errorf("expected a W3C traceparent, got %q", tp)
+	}
+}
+
+func TestJSONExporter(t *testing.T) {
+	var out strings.Builder
+	exporter := NewJSONExporter(&out)
+	resource := []Attribute{Attr("service.name", "test")}
+	span := SpanData{Name: "mvct.update", Attributes: []Attribute{Attr("n", 2), Attr("ok", true)}, Status: StatusError, Resource: resource}
+	span.SpanContext.TraceID[0], span.SpanContext.SpanID[0], span.Parent[0] = 1, 2, 3
+	if err := exporter.ExportSpans(context.Background(), []SpanData{span}); err != nil {
+		t.Fatal(err)
+	}
+	m := newMeter()
+	m.histogram("mvct.view.duration", "", "ms", 7, nil)
+	m.counter("mvct.navigations", "", nil)
+	if err := exporter.ExportMetrics(context.Background(), m.collect(resource)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a line per export, got:\n%s", out.String())
+	}
+	var traces struct {
+		ResourceSpans []struct {
+			Resource   struct{ Attributes []otlpAttribute }
+			ScopeSpans []struct{ Spans []otlpSpan }
+		}
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &traces); err != nil {
+		t.Fatal(err)
+	}
+	got := traces.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	if got.TraceID != "01000000000000000000000000000000" || got.ParentSpanID != "0300000000000000" || got.Status.Code != 2 {
+		t.Errorf("expected OTLP ids and status, got %+v", got)
+	}
+	if got.Attributes[0].Value["intValue"] != "2" || got.Attributes[1].Value["boolValue"] != true {
+		t.Errorf("expected OTLP attribute values, got %+v", got.Attributes)
+	}
+	if !strings.Contains(lines[1], `"explicitBounds":[0,5,10`) || !strings.Contains(lines[1], `"bucketCounts":["0","0","1"`) || !strings.Contains(lines[1], `"asInt":"1"`) {
+		t.Errorf("expected OTLP metrics, got %s", lines[1])
+	}
+}
+
+func attr(span SpanData, key string) string {
+	for _, a := range span.Attributes {
+		if a.Key == key {
+			return fmt.Sprint(a.Value)
+		}
+	}
+	return ""
+}
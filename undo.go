@@ -0,0 +1,320 @@
+package mvct
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultUndoLimit is the number of actions kept by a history without a
+// limit
+const DefaultUndoLimit = 100
+
+// Action is a reversible mutation of controller state. Do applies it and
+// Undo reverts it, either may return a command
+type Action struct {
+	Name string
+	Do   func() Cmd
+	Undo func() Cmd
+}
+
+// UndoHistory records actions so they can be undone and redone. It keeps
+// at most limit actions, dropping the oldest
+type UndoHistory struct {
+	limit  int
+	done   []Action
+	undone []Action
+}
+
+// NewUndoHistory creates a history, a limit of zero uses DefaultUndoLimit
+func NewUndoHistory(limit int) *UndoHistory {
+	if limit <= 0 {
+		limit = DefaultUndoLimit
+	}
+	return &UndoHistory{limit: limit}
+}
+
+// Do applies an action and records it, clearing the actions that could be
+// redone
+func (h *UndoHistory) Do(action Action) Cmd {
+	var cmd Cmd
+	if action.Do != nil {
+		cmd = action.Do()
+	}
+	h.Record(action)
+	return cmd
+}
+
+// Record adds an action that was already applied
+func (h *UndoHistory) Record(action Action) {
+	h.done = append(h.done, action)
+	if over := len(h.done) - h.limit; over > 0 {
+		h.done = slices.Delete(h.done, 0, over)
+	}
+	h.undone = nil
+}
+
+// Undo reverts the last action
+func (h *UndoHistory) Undo() (Cmd, bool) {
+	if len(h.done) == 0 {
+		return nil, false
+	}
+	action := h.done[len(h.done)-1]
+	h.done = h.done[:len(h.done)-1]
+	h.undone = append(h.undone, action)
+
+	slog.Debug("Undo", "action", action.Name)
+	if action.Undo == nil {
+		return nil, true
+	}
+	return action.Undo(), true
+}
+
+// Redo applies the last undone action again
+func (h *UndoHistory) Redo() (Cmd, bool) {
+	if len(h.undone) == 0 {
+		return nil, false
+	}
+	action := h.undone[len(h.undone)-1]
+	h.undone = h.undone[:len(h.undone)-1]
+	h.done = append(h.done, action)
+
+	slog.Debug("Redo", "action", action.Name)
+	if action.Do == nil {
+		return nil, true
+	}
+	return action.Do(), true
+}
+
+// CanUndo reports whether there is an action to undo
+func (h *UndoHistory) CanUndo() bool {
+	return len(h.done) > 0
+}
+
+// CanRedo reports whether there is an action to redo
+func (h *UndoHistory) CanRedo() bool {
+	return len(h.undone) > 0
+}
+
+// Done returns the names of the actions that can be undone, oldest first
+func (h *UndoHistory) Done() []string {
+	return actionNames(h.done)
+}
+
+// Undone returns the names of the actions that can be redone, the next one
+// to redo last
+func (h *UndoHistory) Undone() []string {
+	return actionNames(h.undone)
+}
+
+// Clear forgets every recorded action
+func (h *UndoHistory) Clear() {
+	h.done = nil
+	h.undone = nil
+}
+
+func actionNames(actions []Action) []string {
+	names := make([]string, len(actions))
+	for i, action := range actions {
+		names[i] = action.Name
+	}
+	return names
+}
+
+// Undoable is implemented by controllers with their own undo history.
+// Undo and redo apply to the history of the active controller, falling
+// back to the history of the application
+type Undoable interface {
+	UndoHistory() *UndoHistory
+}
+
+// UndoMsg reverts the last action of the active history
+type UndoMsg struct{}
+
+// RedoMsg applies the last undone action of the active history again
+type RedoMsg struct{}
+
+// DoMsg applies an action and records it in the active history
+type DoMsg struct {
+	Action Action
+}
+
+// Undo reverts the last action
+func Undo() Cmd {
+	return func() Msg {
+		return Msg{
+			Inner: UndoMsg{},
+		}
+	}
+}
+
+// Redo applies the last undone action again
+func Redo() Cmd {
+	return func() Msg {
+		return Msg{
+			Inner: RedoMsg{},
+		}
+	}
+}
+
+// Do applies an action and records it in the history of the active
+// controller or of the application
+func Do(action Action) Cmd {
+	return func() Msg {
+		return Msg{
+			Inner: DoMsg{Action: action},
+		}
+	}
+}
+
+// UndoConfig configures undo and redo
+type UndoConfig struct {
+	// Limit is the number of actions kept by the application history
+	Limit int
+	// UndoKeys and RedoKeys are bound before the controller's Init, which
+	// can rebind them. Default ctrl+z and ctrl+y
+	UndoKeys []string
+	RedoKeys []string
+	// Route registers a route listing the history of the previous route
+	// when set, for debugging
+	Route string
+	// Notify shows a notification naming the undone or redone action
+	Notify bool
+}
+
+// UseUndo enables the application undo history and the undo keys
+func (a *Application[M]) UseUndo(config UndoConfig) {
+	if len(config.UndoKeys) == 0 {
+		config.UndoKeys = []string{"ctrl+z"}
+	}
+	if len(config.RedoKeys) == 0 {
+		config.RedoKeys = []string{"ctrl+y"}
+	}
+	slog.Debug("Configuring undo", "limit", config.Limit, "route", config.Route)
+
+	a.undo = NewUndoHistory(config.Limit)
+	a.undoConfig = config
+	a.keyBindings = append(a.keyBindings, a.UndoBindings()...)
+
+	if config.Route != "" {
+		a.RegisterController(config.Route, &undoHistoryController{
+			history: func() *UndoHistory {
+				return a.undoHistoryOf(a.router.routes[a.router.PreviousRoute()])
+			},
+			previousRoute: a.router.PreviousRoute,
+		})
+	}
+}
+
+// UndoBindings returns the undo and redo key bindings, for use with
+// components.Help, nil until UseUndo is called
+func (a *Application[M]) UndoBindings() []KeyBinding {
+	if a.undo == nil {
+		return nil
+	}
+	return []KeyBinding{
+		Key(a.undoConfig.UndoKeys...).To(undoKey).Help("undo"),
+		Key(a.undoConfig.RedoKeys...).To(redoKey).Help("redo"),
+	}
+}
+
+func undoKey(msg KeyMsg) Cmd {
+	return Undo()
+}
+
+func redoKey(msg KeyMsg) Cmd {
+	return Redo()
+}
+
+// UndoHistory returns the history of the application, nil until UseUndo
+// is called
+func (a *Application[M]) UndoHistory() *UndoHistory {
+	return a.undo
+}
+
+// undoHistoryOf returns the history of a controller or of the application
+func (a *Application[M]) undoHistoryOf(ctlr Controller) *UndoHistory {
+	if undoable, ok := unwrapController(ctlr).(Undoable); ok {
+		if history := undoable.UndoHistory(); history != nil {
+			return history
+		}
+	}
+	return a.undo
+}
+
+func (a *Application[M]) handleUndo(redo bool) (tea.Model, tea.Cmd) {
+	history := a.undoHistoryOf(a.router.Current())
+	if history == nil {
+		slog.Debug("No undo history for the active controller")
+		return a, nil
+	}
+
+	names, apply, verb := history.Done(), history.Undo, "Undid"
+	if redo {
+		names, apply, verb = history.Undone(), history.Redo, "Redid"
+	}
+	cmd, ok := apply()
+	if !ok {
+		return a, nil
+	}
+
+	cmds := []tea.Cmd{unwrapCmd(cmd)}
+	if a.undoConfig.Notify {
+		cmds = append(cmds, a.notifications.push(NotifyMsg{Level: NotifyInfo, Text: verb + " " + names[len(names)-1]}))
+	}
+	return a, tea.Batch(cmds...)
+}
+
+func (a *Application[M]) handleDo(msg DoMsg) (tea.Model, tea.Cmd) {
+	history := a.undoHistoryOf(a.router.Current())
+	if history == nil {
+		err := fmt.Errorf("cannot record %q, the active controller has no undo history and UseUndo was not called", msg.Action.Name)
+		slog.Error("Action not recorded", "error", err)
+		a.Errors = append(a.Errors, err)
+		return a, nil
+	}
+	return a, unwrapCmd(history.Do(msg.Action))
+}
+
+// undoHistoryController lists the undo and redo stacks of the history of
+// the previous route
+type undoHistoryController struct {
+	history       func() *UndoHistory
+	previousRoute func() string
+}
+
+func (c *undoHistoryController) Init(handlers KeyHandlers) Cmd {
+	handlers["esc"] = func(msg KeyMsg) Cmd {
+		if route := c.previousRoute(); route != "" {
+			return Navigate(route)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (c *undoHistoryController) View() string {
+	var b strings.Builder
+	history := c.history()
+
+	b.WriteString(fmt.Sprintf("Undo history of %s\n\n", c.previousRoute()))
+	if history == nil {
+		b.WriteString("  No undo history\n")
+	} else {
+		done, undone := history.Done(), history.Undone()
+		b.WriteString(fmt.Sprintf("Redo (%d)\n", len(undone)))
+		for _, name := range undone {
+			b.WriteString("  " + name + "\n")
+		}
+		b.WriteString(fmt.Sprintf("\nUndo (%d), newest first\n", len(done)))
+		for i := len(done) - 1; i >= 0; i-- {
+			b.WriteString("  " + done[i] + "\n")
+		}
+	}
+	b.WriteString("\nesc: back")
+
+	return b.String()
+}
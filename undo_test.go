@@ -0,0 +1,166 @@
+package mvct
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// EditorController keeps its own undo history
+type EditorController struct {
+	MockController
+	lines   []string
+	history *UndoHistory
+}
+
+func (c *EditorController) UndoHistory() *UndoHistory {
+	return c.history
+}
+
+func (c *EditorController) insert(line string) Action {
+	return Action{
+		Name: "insert " + line,
+		Do: func() Cmd {
+			c.lines = append(c.lines, line)
+			return nil
+		},
+		Undo: func() Cmd {
+			c.lines = c.lines[:len(c.lines)-1]
+			return nil
+		},
+	}
+}
+
+func TestUndoHistory(t *testing.T) {
+	var value int
+	set := func(n int) Action {
+		old := value
+		return Action{
+			Name: "set",
+			Do:   func() Cmd { value = n; return nil },
+			Undo: func() Cmd { value = old; return nil },
+		}
+	}
+
+	history := NewUndoHistory(2)
+	history.Do(set(1))
+	history.Do(set(2))
+	history.Do(set(3))
+	if len(history.Done()) != 2 {
+		t.Fatalf("expected the history to be bounded to 2, got %v", history.Done())
+	}
+
+	history.Undo()
+	history.Undo()
+	if _, ok := history.Undo(); ok || value != 1 {
+		t.Errorf("expected the oldest action to be dropped, got value %d", value)
+	}
+
+	history.Redo()
+	if value != 2 || !history.CanRedo() {
+		t.Errorf("expected redo to apply 2, got %d", value)
+	}
+	history.Do(set(5))
+	if history.CanRedo() {
+		t.Error("a new action should clear the redo stack")
+	}
+}
+
+func TestApplicationUndo(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/editor"}, "model")
+	editor := &EditorController{history: NewUndoHistory(0)}
+	app.RegisterController("/editor", editor)
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.UseUndo(UndoConfig{Route: "/undo", Notify: true})
+	app.Init()
+
+	app.Update(DoMsg{Action: editor.insert("a")})
+	app.Update(DoMsg{Action: editor.insert("b")})
+	if strings.Join(editor.lines, "") != "ab" {
+		t.Fatalf("expected actions to be applied, got %v", editor.lines)
+	}
+	if app.UndoHistory().CanUndo() {
+		t.Error("actions of an undoable controller should use its own history")
+	}
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	app.Update(cmd())
+	if strings.Join(editor.lines, "") != "a" {
+		t.Errorf("expected ctrl+z to undo, got %v", editor.lines)
+	}
+	if notifications := app.Notifications(); len(notifications) != 1 || notifications[0].Text != "Undid insert b" {
+		t.Errorf("expected an undo notification, got %+v", notifications)
+	}
+
+	app.Update(NavigateMsg{Route: "/undo"})
+	if view := app.View(); !strings.Contains(view, "insert b") || !strings.Contains(view, "insert a") {
+		t.Errorf("expected the history view to list both actions, got %q", view)
+	}
+
+	app.Update(NavigateMsg{Route: "/editor"})
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyCtrlY})
+	app.Update(cmd())
+	if strings.Join(editor.lines, "") != "ab" {
+		t.Errorf("expected ctrl+y to redo, got %v", editor.lines)
+	}
+
+	var count int
+	app.Update(NavigateMsg{Route: "/home"})
+	app.Update(DoMsg{Action: Action{Name: "count", Do: func() Cmd { count++; return nil }, Undo: func() Cmd { count--; return nil }}})
+	app.Update(UndoMsg{})
+	if count != 0 || !app.UndoHistory().CanRedo() {
+		t.Errorf("expected other controllers to use the application history, got count %d", count)
+	}
+}
+
+// RebindController uses ctrl+z for something else
+type RebindController struct {
+	MockController
+	pressed int
+}
+
+func (c *RebindController) Init(handlers KeyHandlers) Cmd {
+	handlers["ctrl+z"] = func(msg KeyMsg) Cmd {
+		c.pressed++
+		return nil
+	}
+	return nil
+}
+
+func TestApplicationUndo_Keys(t *testing.T) {
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	rebind := &RebindController{}
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.RegisterController("/rebind", rebind)
+	app.UseUndo(UndoConfig{RedoKeys: []string{"ctrl+r"}})
+	app.Init()
+
+	keys, _ := app.handlerNames()
+	var bound []string
+	for _, key := range keys {
+		bound = append(bound, key[0])
+		if !strings.Contains(key[1], "Key") {
+			t.Errorf("expected the undo handlers by name, got %q", key[1])
+		}
+	}
+	if strings.Join(bound, ",") != "ctrl+r,ctrl+z" {
+		t.Errorf("expected the undo keys among the key handlers, got %v", bound)
+	}
+
+	var count int
+	app.Update(DoMsg{Action: Action{Name: "count", Do: func() Cmd { count++; return nil }, Undo: func() Cmd { count--; return nil }}})
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	app.Update(cmd())
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	app.Update(cmd())
+	if count != 1 || app.UndoHistory().CanRedo() {
+		t.Errorf("expected ctrl+z to undo and ctrl+r to redo, got count %d", count)
+	}
+
+	app.Update(NavigateMsg{Route: "/rebind"})
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	if rebind.pressed != 1 || count != 1 {
+		t.Errorf("expected the controller to rebind ctrl+z, got %d presses and count %d", rebind.pressed, count)
+	}
+}
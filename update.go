@@ -1,27 +1,88 @@
 package mvct
 
 import (
+	"context"
 	"log/slog"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 // Update implements tea.Model
 func (a *Application[M]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	ctx := context.Background()
+	// a traced command sends its message with the context of its span
+	if traced, ok := msg.(Msg); ok {
+		msg = traced.Inner
+		if traced.Context != nil {
+			ctx = traced.Context
+		}
+	}
 	slog.Debug("Application Update", "msg_type", reflect.TypeOf(msg), "msg", msg)
 
+	if a.devtools != nil {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && a.handleDevtoolsKey(keyMsg) {
+			return a, nil
+		}
+		defer a.devtools.logMsg(deliveredMsg(msg), a.router.CurrentRoute(), time.Now())
+	}
+
+	if a.tracer != nil {
+		return a.traceUpdate(deliveredMsg(msg), ctx, func(ctx context.Context) (tea.Model, tea.Cmd) {
+			return a.update(msg, ctx)
+		})
+	}
+	return a.update(msg, ctx)
+}
+
+func (a *Application[M]) update(msg tea.Msg, ctx context.Context) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		return a.handleWindowResize(msg)
 	}
 
 	wrappedMsg := wrapMsg(msg)
+	wrappedMsg.Context = ctx
 
 	switch inner := wrappedMsg.Inner.(type) {
 	case NavigateMsg:
 		return a.handleNavigate(inner)
+	case NotifyMsg:
+		return a.handleNotify(inner)
+	case notificationExpiredMsg:
+		return a.handleNotificationExpired(inner)
+	case DismissNotificationsMsg:
+		a.notifications.dismissAll()
+		return a, nil
+	case WizardMsg:
+		return a.handleWizard(inner)
+	case SetThemeMsg:
+		return a.handleSetTheme(inner)
+	case PublishMsg:
+		return a.handlePublish(inner)
+	case subscriptionMsg:
+		return a.handleSubscriptionMsg(inner, ctx)
+	case subscriptionEndedMsg:
+		return a.handleSubscriptionEnded(inner, ctx)
+	case startJobMsg:
+		return a.handleStartJob(inner)
+	case CancelJobMsg:
+		return a.handleCancelJob(inner)
+	case jobUpdatedMsg:
+		return a, inner.job.wait()
+	case jobFinishedMsg:
+		return a.handleJobFinished(inner)
+	case UndoMsg:
+		return a.handleUndo(false)
+	case RedoMsg:
+		return a.handleUndo(true)
+	case DoMsg:
+		return a.handleDo(inner)
+	case devStatusMsg:
+		return a.handleDevStatus(inner)
 	case KeyMsg:
 		if cmd, ok := a.handleKeyMsg(inner, wrappedMsg); ok {
 			return a, cmd
@@ -49,16 +110,31 @@ func (a *Application[M]) handleWindowResize(msg tea.WindowSizeMsg) (tea.Model, t
 
 func (a *Application[M]) handleNavigate(msg NavigateMsg) (tea.Model, tea.Cmd) {
 	slog.Info("Processing navigation", "route", msg.Route)
-	cmd, err := a.router.Navigate(a.keyHandlers, msg.Route)
+	from := a.router.CurrentRoute()
+	previous := a.router.Current()
+	// the router runs Init with a fresh map so a blocked navigation keeps
+	// the key handlers of the current controller
+	keyHandlers := a.newKeyHandlers()
+	var cmd tea.Cmd
+	var err error
+	a.traced("mvct.navigate", []Attribute{Attr("mvct.route.from", from), Attr("mvct.route.to", msg.Route)}, func(span *Span) {
+		cmd, err = a.router.navigate(keyHandlers, msg.Route, msg.Data)
+		a.recordNavigation(span, msg.Route, err)
+	})
 	if err != nil {
 		slog.Error("Navigation failed", "error", err)
 		a.Errors = append(a.Errors, err)
 		return a, nil
 	}
-	a.scanMessageHandlers()
+	a.keyHandlers = keyHandlers
+	if d, ok := previous.(deactivator); ok {
+		d.deactivate()
+	}
+	a.trackWizards(from, a.router.CurrentRoute())
+	a.bindMessageHandlers()
 	slog.Debug("Navigation successful", "new_route", msg.Route)
 	a.logHandlers()
-	return a, cmd
+	return a, tea.Batch(cmd, a.activated(a.router.Current()))
 }
 
 func (a *Application[M]) handleKeyMsg(msg KeyMsg, wrappedMsg Msg) (tea.Cmd, bool) {
@@ -66,7 +142,7 @@ func (a *Application[M]) handleKeyMsg(msg KeyMsg, wrappedMsg Msg) (tea.Cmd, bool
 
 	// 1. Generic OnKeyMsg
 	if handler, exists := a.msgHandlers[reflect.TypeOf(msg)]; exists {
-		if cmd, ok := a.callReflectedFunc(handler, wrappedMsg); ok {
+		if cmd, ok := a.callHandler(handler, wrappedMsg); ok {
 			cmds = append(cmds, cmd)
 		}
 	}
@@ -74,7 +150,9 @@ func (a *Application[M]) handleKeyMsg(msg KeyMsg, wrappedMsg Msg) (tea.Cmd, bool
 	// 2. Specific Key Handlers
 	slog.Debug("KeyMsg received", "key", msg.String())
 	if handler, exists := a.keyHandlers[msg.String()]; exists {
-		cmds = append(cmds, unwrapCmd(handler(msg)))
+		cmds = append(cmds, a.traceHandler(funcName(handler), msg, func() tea.Cmd {
+			return unwrapCmd(handler(msg))
+		}))
 	}
 
 	if len(cmds) > 0 {
@@ -89,7 +167,7 @@ func (a *Application[M]) handleControllerMsg(msg Msg) (tea.Cmd, bool) {
 	msgType := reflect.TypeOf(msg.Inner)
 	if handler, exists := a.msgHandlers[msgType]; exists {
 		slog.Debug("Calling controller message handler", "msg_type", msgType.String())
-		if cmd, ok := a.callReflectedFunc(handler, msg); ok {
+		if cmd, ok := a.callHandler(handler, msg); ok {
 			return cmd, true
 		}
 		slog.Debug("Controller message handler returned nil, continuing...")
@@ -99,7 +177,10 @@ func (a *Application[M]) handleControllerMsg(msg Msg) (tea.Cmd, bool) {
 
 func (a *Application[M]) handleGlobalKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	for _, handler := range a.globalHandlers {
-		if cmd := handler.Handle(msg); cmd != nil {
+		cmd := a.traceHandler(globalHandlerName(handler), msg, func() tea.Cmd {
+			return handler.Handle(msg)
+		})
+		if cmd != nil {
 			slog.Debug("Global handler handled key", "handler", reflect.TypeOf(handler), "key", msg.String())
 			return a, cmd
 		}
@@ -107,41 +188,114 @@ func (a *Application[M]) handleGlobalKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	return a, nil
 }
 
-func (a *Application[M]) callReflectedFunc(handler reflect.Value, msg Msg) (tea.Cmd, bool) {
-	results := handler.Call([]reflect.Value{reflect.ValueOf(msg.Inner)})
-	if len(results) > 0 && !results[0].IsNil() {
-		cmd := results[0].Interface().(Cmd)
-		unwrapped := unwrapCmd(cmd)
-		return unwrapped, true
+func (a *Application[M]) callHandler(handler MessageHandler, msg Msg) (tea.Cmd, bool) {
+	cmd := a.traceHandler(handler.Name, msg.Inner, func() tea.Cmd {
+		return unwrapCmd(handler.Handle(msg.Inner))
+	})
+	if cmd != nil {
+		return cmd, true
 	}
 	return nil, false
 }
 
-// gets the appropriate handler based on the paramter
-func (a *Application[M]) scanMessageHandlers() {
-	slog.Debug("Scanning message handlers")
-	a.keyHandlers = make(map[string]KeyMsgHandler)
-	a.msgHandlers = make(map[reflect.Type]reflect.Value)
-
+// bindMessageHandlers maps the message types of the current controller to
+// its handlers. Init is not called here, the router calls it on navigation
+// and Application.Init on start so it runs once per activation
+func (a *Application[M]) bindMessageHandlers() {
 	ctlr := a.router.Current()
 	if ctlr == nil {
+		a.msgHandlers = nil
 		return
 	}
 
-	val := reflect.ValueOf(ctlr)
-	typ := val.Type()
+	handlers := messageHandlersOf(ctlr)
+	a.msgHandlers = make(map[reflect.Type]MessageHandler, len(handlers))
+	for _, handler := range handlers {
+		a.msgHandlers[handler.Type] = handler
+	}
+}
 
-	for i := 0; i < val.NumMethod(); i++ {
-		method := val.Method(i)
-		methodName := typ.Method(i).Name
-		methodType := method.Type()
+// messageHandlersOf returns the generated HandlerTable of a controller
+// and its cached On* methods bound to it. On* methods missing from the
+// table, added since mvct gen last ran, are dispatched through reflection
+func messageHandlersOf(ctlr Controller) []MessageHandler {
+	var handlers []MessageHandler
+	generated := map[reflect.Type]bool{}
+	table, hasTable := ctlr.(HandlerTable)
+	if hasTable {
+		handlers = table.MessageHandlers()
+		for _, handler := range handlers {
+			generated[handler.Type] = true
+		}
+	}
 
-		if strings.HasPrefix(methodName, "On") {
-			if methodType.NumIn() == 1 && methodType.NumOut() == 1 {
-				msgType := methodType.In(0)
-				a.msgHandlers[msgType] = method
+	val := reflect.ValueOf(ctlr)
+	for _, m := range reflectedHandlersOf(val.Type()) {
+		if generated[m.msgType] {
+			continue
+		}
+		if hasTable {
+			if _, logged := staleTables.LoadOrStore(m.name, true); !logged {
+				slog.Debug("On method missing from the generated handler table, re-run mvct gen", "method", m.name)
 			}
 		}
+		method := val.Method(m.index)
+		handlers = append(handlers, MessageHandler{
+			Type: m.msgType,
+			Handle: func(msg tea.Msg) Cmd {
+				cmd, _ := method.Call([]reflect.Value{reflect.ValueOf(msg)})[0].Interface().(Cmd)
+				return cmd
+			},
+			Name: m.name,
+		})
+	}
+	return handlers
+}
+
+// staleTables holds the On* methods already logged as missing from their
+// generated table
+var staleTables sync.Map // method name -> true
+
+var cmdType = reflect.TypeFor[Cmd]()
+
+// reflectedHandler is an On* method of a controller type
+type reflectedHandler struct {
+	msgType reflect.Type
+	index   int
+	name    string
+}
+
+// reflectedHandlers caches the On* methods per controller type, reflection
+// runs the first time a type is activated instead of on every navigation
+var reflectedHandlers sync.Map // reflect.Type -> []reflectedHandler
+
+func reflectedHandlersOf(typ reflect.Type) []reflectedHandler {
+	if cached, ok := reflectedHandlers.Load(typ); ok {
+		return cached.([]reflectedHandler)
+	}
+
+	var handlers []reflectedHandler
+	for i := 0; i < typ.NumMethod(); i++ {
+		method := typ.Method(i)
+		if !strings.HasPrefix(method.Name, "On") {
+			continue
+		}
+		// the method type includes the receiver
+		methodType := method.Type
+		if methodType.NumIn() != 2 || methodType.NumOut() != 1 || methodType.Out(0) != cmdType {
+			slog.Warn("Ignoring On method with an invalid handler signature, expected func(T) mvct.Cmd",
+				"controller", typ.String(), "method", method.Name, "signature", methodType.String())
+			continue
+		}
+
+		handlers = append(handlers, reflectedHandler{
+			msgType: methodType.In(1),
+			index:   i,
+			name:    typ.String() + "." + method.Name,
+		})
 	}
-	ctlr.Init(a.keyHandlers)
+
+	slog.Debug("Discovered message handlers", "controller", typ.String(), "count", len(handlers))
+	cached, _ := reflectedHandlers.LoadOrStore(typ, handlers)
+	return cached.([]reflectedHandler)
 }
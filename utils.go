@@ -2,6 +2,10 @@ package mvct
 
 import (
 	"context"
+	"path"
+	"reflect"
+	"runtime"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -44,6 +48,11 @@ func unwrapCmd(cmd Cmd) tea.Cmd {
 	}
 	return func() tea.Msg {
 		msg := cmd()
+		// a message in a span keeps its context so its Update joins the
+		// trace
+		if msg.Inner != nil && SpanFromContext(msg.Context) != nil && !teaMsg(msg.Inner) {
+			return msg
+		}
 		return msg.Inner
 	}
 }
@@ -51,3 +60,49 @@ func unwrapCmd(cmd Cmd) tea.Cmd {
 func Quit() Cmd {
 	return wrapCmd(context.Background(), tea.Quit)
 }
+
+// Batch combines commands into a single command that runs them concurrently
+func Batch(cmds ...Cmd) Cmd {
+	var valid []Cmd
+	for _, cmd := range cmds {
+		if cmd != nil {
+			valid = append(valid, cmd)
+		}
+	}
+
+	switch len(valid) {
+	case 0:
+		return nil
+	case 1:
+		return valid[0]
+	}
+
+	teaCmds := make([]tea.Cmd, 0, len(valid))
+	for _, cmd := range valid {
+		teaCmds = append(teaCmds, unwrapCmd(cmd))
+	}
+	return wrapCmd(context.Background(), tea.Batch(teaCmds...))
+}
+
+// Handled returns a command that produces no message. Return it from an On*
+// handler to mark a message as consumed so global handlers don't see it
+func Handled() Cmd {
+	return func() Msg {
+		return Msg{}
+	}
+}
+
+// Tick produces the message returned by fn once d has elapsed
+func Tick(d time.Duration, fn func(time.Time) tea.Msg) Cmd {
+	return wrapCmd(context.Background(), tea.Tick(d, fn))
+}
+
+// funcName returns the name of a function without the directories of its
+// package path, like mvct.AuthMiddleware.func1
+func funcName(fn any) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return reflect.TypeOf(fn).String()
+	}
+	return path.Base(f.Name())
+}
@@ -82,3 +82,35 @@ func TestQuit(t *testing.T) {
 		t.Error("Quit cmd did not return QuitMsg")
 	}
 }
+
+func TestBatch(t *testing.T) {
+	if Batch(nil, nil) != nil {
+		t.Error("Batch of nil commands should be nil")
+	}
+
+	single := Navigate("/home")
+	if cmd := Batch(nil, single); cmd == nil {
+		t.Error("Batch of one command returned nil")
+	} else if _, ok := cmd().Inner.(NavigateMsg); !ok {
+		t.Error("Batch of one command should return that command")
+	}
+
+	cmd := Batch(Navigate("/a"), Navigate("/b"))
+	batch, ok := cmd().Inner.(tea.BatchMsg)
+	if !ok {
+		t.Fatal("Batch did not return tea.BatchMsg")
+	}
+	if len(batch) != 2 {
+		t.Errorf("expected 2 batched commands, got %d", len(batch))
+	}
+}
+
+func TestHandled(t *testing.T) {
+	cmd := Handled()
+	if cmd == nil {
+		t.Fatal("Handled returned nil")
+	}
+	if unwrapCmd(cmd)() != nil {
+		t.Error("Handled should produce no message")
+	}
+}
@@ -0,0 +1,397 @@
+package mvct
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WizardAction is a step change requested by a wizard command
+type WizardAction int
+
+const (
+	WizardNextAction WizardAction = iota
+	WizardBackAction
+	WizardSkipAction
+	WizardCancelAction
+)
+
+// WizardMsg asks the active wizard to change steps
+type WizardMsg struct {
+	Action WizardAction
+}
+
+// WizardCompletedMsg is sent to the controller active after the last step
+// of a wizard passes validation
+type WizardCompletedMsg struct {
+	Wizard string
+	State  map[string]any
+}
+
+// WizardNext moves to the next step once the current step validates,
+// completing the wizard on the last step
+func WizardNext() Cmd {
+	return wizardCmd(WizardNextAction)
+}
+
+// WizardBack returns to the previously visited step
+func WizardBack() Cmd {
+	return wizardCmd(WizardBackAction)
+}
+
+// WizardSkip moves to the next step without validating the current one,
+// only steps marked Skippable can be skipped
+func WizardSkip() Cmd {
+	return wizardCmd(WizardSkipAction)
+}
+
+// WizardCancel abandons the wizard and returns to the route it was
+// started from
+func WizardCancel() Cmd {
+	return wizardCmd(WizardCancelAction)
+}
+
+func wizardCmd(action WizardAction) Cmd {
+	return func() Msg {
+		return Msg{
+			Inner: WizardMsg{Action: action},
+		}
+	}
+}
+
+// WizardState is the data shared between the steps of a wizard
+type WizardState struct {
+	values map[string]any
+}
+
+// Get returns a value stored by a step
+func (s *WizardState) Get(key string) (any, bool) {
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Set stores a value for later steps
+func (s *WizardState) Set(key string, value any) {
+	s.values[key] = value
+}
+
+// Delete removes a value
+func (s *WizardState) Delete(key string) {
+	delete(s.values, key)
+}
+
+// Values returns a copy of every stored value
+func (s *WizardState) Values() map[string]any {
+	return maps.Clone(s.values)
+}
+
+func (s *WizardState) reset() {
+	s.values = map[string]any{}
+}
+
+// WizardStep is a single screen of a wizard
+type WizardStep struct {
+	// Name is the route segment of the step, the step is registered at
+	// the wizard path followed by /Name
+	Name string
+	// Title is shown in the progress indicator
+	Title      string
+	Controller Controller
+	// Validate gates leaving the step with WizardNext, a returned error is
+	// shown as a notification and keeps the user on the step
+	Validate func(state *WizardState) error
+	// Skippable allows leaving the step with WizardSkip
+	Skippable bool
+}
+
+// WizardProgress describes where the user is in a wizard
+type WizardProgress struct {
+	// Current is the index of the active step
+	Current int
+	Total   int
+	Titles  []string
+}
+
+// Wizard is an ordered sequence of routes sharing a WizardState
+type Wizard struct {
+	path          string
+	steps         []WizardStep
+	state         *WizardState
+	completeRoute string
+	abandonHooks  []func(state *WizardState)
+
+	active  bool
+	current int
+	// visited holds the steps left forward so back returns to the step the
+	// user actually came from
+	visited    []int
+	entryRoute string
+	completing bool
+}
+
+// NewWizard creates a wizard whose steps are registered under path
+func NewWizard(path string) *Wizard {
+	return &Wizard{
+		path:  strings.TrimSuffix(path, "/"),
+		state: &WizardState{values: map[string]any{}},
+	}
+}
+
+// Step appends a step to the wizard
+func (w *Wizard) Step(step WizardStep) *Wizard {
+	w.steps = append(w.steps, step)
+	return w
+}
+
+// OnComplete sets the route navigated to after the last step, it receives
+// the WizardCompletedMsg. Without it the wizard returns to the route it
+// was started from
+func (w *Wizard) OnComplete(route string) *Wizard {
+	w.completeRoute = route
+	return w
+}
+
+// OnAbandon adds a cleanup hook run when the user leaves the wizard
+// without completing it
+func (w *Wizard) OnAbandon(hook func(state *WizardState)) *Wizard {
+	w.abandonHooks = append(w.abandonHooks, hook)
+	return w
+}
+
+// State returns the state shared by the steps, pass it to step controllers
+// when creating them
+func (w *Wizard) State() *WizardState {
+	return w.state
+}
+
+// Route returns the route of the first step, navigate to it to start the
+// wizard
+func (w *Wizard) Route() string {
+	if len(w.steps) == 0 {
+		return w.path
+	}
+	return w.StepRoute(0)
+}
+
+// StepRoute returns the route of the step at index i
+func (w *Wizard) StepRoute(i int) string {
+	return w.path + "/" + w.steps[i].Name
+}
+
+// Active reports whether one of the wizard's steps is the current route
+func (w *Wizard) Active() bool {
+	return w.active
+}
+
+// Progress returns the position of the user in the wizard
+func (w *Wizard) Progress() WizardProgress {
+	titles := make([]string, len(w.steps))
+	for i, step := range w.steps {
+		titles[i] = step.Title
+		if titles[i] == "" {
+			titles[i] = step.Name
+		}
+	}
+	return WizardProgress{
+		Current: w.current,
+		Total:   len(w.steps),
+		Titles:  titles,
+	}
+}
+
+// RenderProgress renders the progress indicator for use in a layout, e.g.
+// "✓ Account › ● Profile › ○ Confirm  (2/3)"
+func (w *Wizard) RenderProgress() string {
+	progress := w.Progress()
+	theme := CurrentTheme()
+	doneStyle := theme.Style(theme.Success)
+	currentStyle := theme.Style(theme.Primary).Bold(true)
+	todoStyle := theme.Style(theme.Muted)
+
+	parts := make([]string, len(progress.Titles))
+	for i, title := range progress.Titles {
+		switch {
+		case i < progress.Current:
+			parts[i] = doneStyle.Render("✓ " + title)
+		case i == progress.Current:
+			parts[i] = currentStyle.Render("● " + title)
+		default:
+			parts[i] = todoStyle.Render("○ " + title)
+		}
+	}
+
+	return strings.Join(parts, todoStyle.Render(" › ")) +
+		todoStyle.Render(fmt.Sprintf("  (%d/%d)", progress.Current+1, progress.Total))
+}
+
+// stepIndex returns the index of the step registered at route
+func (w *Wizard) stepIndex(route string) (int, bool) {
+	for i := range w.steps {
+		if w.StepRoute(i) == route {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (w *Wizard) finish() {
+	w.active = false
+	w.current = 0
+	w.visited = nil
+	w.entryRoute = ""
+	w.state.reset()
+}
+
+func (w *Wizard) abandon() {
+	slog.Info("Wizard abandoned", "wizard", w.path)
+	for _, hook := range w.abandonHooks {
+		hook(w.state)
+	}
+	w.finish()
+}
+
+// RegisterWizard registers the controllers of every wizard step
+func (a *Application[M]) RegisterWizard(w *Wizard) {
+	slog.Debug("Registering wizard", "path", w.path, "steps", len(w.steps))
+	for i, step := range w.steps {
+		a.RegisterController(w.StepRoute(i), step.Controller)
+	}
+	a.wizards = append(a.wizards, w)
+}
+
+// wizardFor returns the wizard a route belongs to
+func (a *Application[M]) wizardFor(route string) (*Wizard, int) {
+	for _, w := range a.wizards {
+		if i, ok := w.stepIndex(route); ok {
+			return w, i
+		}
+	}
+	return nil, 0
+}
+
+// trackWizards updates wizard state after a successful navigation, it
+// starts wizards that are entered, abandons wizards that are left and
+// records the steps left forward for back
+func (a *Application[M]) trackWizards(from, to string) {
+	fromWizard, fromStep := a.wizardFor(from)
+	toWizard, step := a.wizardFor(to)
+
+	if fromWizard != nil && fromWizard != toWizard {
+		switch {
+		case fromWizard.completing:
+			fromWizard.completing = false
+			fromWizard.finish()
+		case fromWizard.active:
+			fromWizard.abandon()
+		}
+	}
+
+	if toWizard == nil {
+		return
+	}
+	if !toWizard.active {
+		slog.Info("Wizard started", "wizard", toWizard.path, "from", from)
+		toWizard.active = true
+		toWizard.entryRoute = from
+	} else if fromWizard == toWizard {
+		n := len(toWizard.visited)
+		switch {
+		case n > 0 && toWizard.visited[n-1] == step:
+			toWizard.visited = toWizard.visited[:n-1]
+		case step > fromStep:
+			toWizard.visited = append(toWizard.visited, fromStep)
+		}
+	}
+	toWizard.current = step
+}
+
+func (a *Application[M]) handleWizard(msg WizardMsg) (tea.Model, tea.Cmd) {
+	w, step := a.wizardFor(a.router.CurrentRoute())
+	if w == nil {
+		slog.Warn("Wizard command outside of a wizard", "route", a.router.CurrentRoute())
+		return a, nil
+	}
+	if !w.active {
+		// completed without a route to go to, the last step stays on screen
+		slog.Debug("Wizard command after completion", "wizard", w.path)
+		return a, nil
+	}
+
+	switch msg.Action {
+	case WizardNextAction:
+		if validate := w.steps[step].Validate; validate != nil {
+			if err := validate(w.state); err != nil {
+				slog.Debug("Wizard step failed validation", "wizard", w.path, "step", w.steps[step].Name, "error", err)
+				return a, unwrapCmd(Notify(NotifyError, err.Error()))
+			}
+		}
+		return a.advanceWizard(w, step)
+
+	case WizardSkipAction:
+		if !w.steps[step].Skippable {
+			return a, unwrapCmd(Notify(NotifyWarning, fmt.Sprintf("%s can't be skipped", w.Progress().Titles[step])))
+		}
+		return a.advanceWizard(w, step)
+
+	case WizardBackAction:
+		if len(w.visited) == 0 {
+			return a, nil
+		}
+		// trackWizards drops the step once the navigation went through
+		return a.handleNavigate(NavigateMsg{Route: w.StepRoute(w.visited[len(w.visited)-1])})
+
+	case WizardCancelAction:
+		route := a.wizardExit(w)
+		if route == "" {
+			// nowhere to return to, the wizard is the app
+			w.abandon()
+			return a, tea.Quit
+		}
+		return a.handleNavigate(NavigateMsg{Route: route})
+	}
+
+	return a, nil
+}
+
+// wizardExit returns the route the wizard was entered from, or the default
+// route when the wizard started the app. It is empty when the default route
+// is part of the wizard
+func (a *Application[M]) wizardExit(w *Wizard) string {
+	if w.entryRoute != "" {
+		return w.entryRoute
+	}
+	if _, ok := w.stepIndex(a.router.defaultRoute); ok {
+		return ""
+	}
+	return a.router.defaultRoute
+}
+
+func (a *Application[M]) advanceWizard(w *Wizard, step int) (tea.Model, tea.Cmd) {
+	if step < len(w.steps)-1 {
+		return a.handleNavigate(NavigateMsg{Route: w.StepRoute(step + 1)})
+	}
+
+	slog.Info("Wizard completed", "wizard", w.path)
+	completed := WizardCompletedMsg{Wizard: w.path, State: w.state.Values()}
+	route := w.completeRoute
+	if route == "" {
+		route = a.wizardExit(w)
+	}
+	if route == "" {
+		w.finish()
+		return a, func() tea.Msg { return completed }
+	}
+
+	w.completing = true
+	model, cmd := a.handleNavigate(NavigateMsg{Route: route})
+	if w.completing {
+		// navigation failed, stay on the last step
+		w.completing = false
+		return model, cmd
+	}
+
+	return model, tea.Batch(cmd, func() tea.Msg { return completed })
+}
@@ -0,0 +1,233 @@
+package mvct
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// collectMsgs runs a command and any batch it returns
+func collectMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, collectMsgs(c)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+func newWizardApp() (*Application[string], *Wizard) {
+	app := NewApplication(Config{DefaultRoute: "/home"}, "model")
+	app.RegisterController("/home", &MockController{name: "home"})
+	app.RegisterController("/done", &MockController{name: "done"})
+
+	setup := NewWizard("/setup")
+	setup.Step(WizardStep{
+		Name:       "account",
+		Title:      "Account",
+		Controller: &MockController{name: "account"},
+		Validate: func(state *WizardState) error {
+			if _, ok := state.Get("user"); !ok {
+				return errors.New("user is required")
+			}
+			return nil
+		},
+	}).Step(WizardStep{
+		Name:       "profile",
+		Title:      "Profile",
+		Controller: &MockController{name: "profile"},
+		Skippable:  true,
+	}).Step(WizardStep{
+		Name:       "confirm",
+		Title:      "Confirm",
+		Controller: &MockController{name: "confirm"},
+	}).OnComplete("/done")
+
+	app.RegisterWizard(setup)
+	app.Init()
+	return app, setup
+}
+
+func TestWizardFlow(t *testing.T) {
+	app, setup := newWizardApp()
+
+	app.Update(NavigateMsg{Route: setup.Route()})
+	if !setup.Active() {
+		t.Fatal("expected the wizard to be active")
+	}
+
+	_, cmd := app.Update(WizardMsg{Action: WizardNextAction})
+	if route := app.router.CurrentRoute(); route != "/setup/account" {
+		t.Errorf("failed validation should keep the step, got %s", route)
+	}
+	if msgs := collectMsgs(cmd); len(msgs) != 1 {
+		t.Errorf("expected a notification command, got %v", msgs)
+	}
+
+	setup.State().Set("user", "ada")
+	app.Update(WizardMsg{Action: WizardNextAction})
+	if route := app.router.CurrentRoute(); route != "/setup/profile" {
+		t.Errorf("expected /setup/profile, got %s", route)
+	}
+
+	app.Update(WizardMsg{Action: WizardSkipAction})
+	if progress := setup.Progress(); progress.Current != 2 || progress.Total != 3 {
+		t.Errorf("expected step 3 of 3, got %+v", progress)
+	}
+
+	app.Update(WizardMsg{Action: WizardBackAction})
+	if route := app.router.CurrentRoute(); route != "/setup/profile" {
+		t.Errorf("expected back to return to /setup/profile, got %s", route)
+	}
+
+	app.Update(WizardMsg{Action: WizardNextAction})
+	_, cmd = app.Update(WizardMsg{Action: WizardNextAction})
+	if route := app.router.CurrentRoute(); route != "/done" {
+		t.Errorf("expected completion to navigate to /done, got %s", route)
+	}
+	if setup.Active() {
+		t.Error("completed wizard should not be active")
+	}
+
+	var completed *WizardCompletedMsg
+	for _, msg := range collectMsgs(cmd) {
+		if c, ok := msg.(WizardCompletedMsg); ok {
+			completed = &c
+		}
+	}
+	if completed == nil {
+		t.Fatal("expected a WizardCompletedMsg")
+	}
+	if completed.Wizard != "/setup" || completed.State["user"] != "ada" {
+		t.Errorf("unexpected completion %+v", completed)
+	}
+	if _, ok := setup.State().Get("user"); ok {
+		t.Error("state should be reset after completion")
+	}
+}
+
+func TestWizardAbandon(t *testing.T) {
+	app, setup := newWizardApp()
+
+	var abandoned map[string]any
+	setup.OnAbandon(func(state *WizardState) {
+		abandoned = state.Values()
+	})
+
+	app.Update(NavigateMsg{Route: setup.Route()})
+	setup.State().Set("user", "ada")
+	app.Update(WizardMsg{Action: WizardNextAction})
+	app.Update(WizardMsg{Action: WizardCancelAction})
+
+	if route := app.router.CurrentRoute(); route != "/home" {
+		t.Errorf("expected cancel to return to /home, got %s", route)
+	}
+	if abandoned["user"] != "ada" {
+		t.Errorf("expected the abandon hook to see the state, got %v", abandoned)
+	}
+	if setup.Active() {
+		t.Error("abandoned wizard should not be active")
+	}
+
+	abandoned = nil
+	app.Update(NavigateMsg{Route: setup.Route()})
+	app.Update(NavigateMsg{Route: "/done"})
+	if abandoned == nil {
+		t.Error("navigating out of a wizard should abandon it")
+	}
+}
+
+func TestWizardAsDefaultRoute(t *testing.T) {
+	newApp := func(complete string) (*Application[string], *Wizard) {
+		setup := NewWizard("/setup")
+		setup.Step(WizardStep{Name: "name", Controller: &MockController{name: "name"}}).
+			Step(WizardStep{Name: "confirm", Controller: &MockController{name: "confirm"}})
+		if complete != "" {
+			setup.OnComplete(complete)
+		}
+		app := NewApplication(Config{DefaultRoute: setup.StepRoute(0)}, "model")
+		app.RegisterController("/done", &MockController{name: "done"})
+		app.RegisterWizard(setup)
+		app.Init()
+		return app, setup
+	}
+
+	app, setup := newApp("/done")
+	if !setup.Active() {
+		t.Fatal("expected a wizard on the default route to start with the app")
+	}
+	abandoned := false
+	setup.OnAbandon(func(*WizardState) { abandoned = true })
+	app.Update(WizardMsg{Action: WizardNextAction})
+	_, cmd := app.Update(WizardMsg{Action: WizardCancelAction})
+	if !abandoned || setup.Active() {
+		t.Error("expected cancel to abandon the wizard")
+	}
+	if cmd == nil {
+		t.Fatal("expected cancel to quit without a route to return to")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("expected cancel to quit without a route to return to")
+	}
+
+	app, setup = newApp("")
+	app.Update(WizardMsg{Action: WizardNextAction})
+	_, cmd = app.Update(WizardMsg{Action: WizardNextAction})
+	if route := app.router.CurrentRoute(); route != "/setup/confirm" {
+		t.Errorf("expected to stay on the last step without a route to complete to, got %s", route)
+	}
+	if msgs := collectMsgs(cmd); len(msgs) != 1 {
+		t.Errorf("expected the completion message, got %v", msgs)
+	} else if _, ok := msgs[0].(WizardCompletedMsg); !ok {
+		t.Errorf("expected the completion message, got %v", msgs)
+	}
+	_, cmd = app.Update(WizardMsg{Action: WizardNextAction})
+	if msgs := collectMsgs(cmd); len(msgs) != 0 {
+		t.Errorf("expected a completed wizard to ignore next, got %v", msgs)
+	}
+	abandoned = false
+	setup.OnAbandon(func(*WizardState) { abandoned = true })
+	app.Update(NavigateMsg{Route: "/done"})
+	if abandoned {
+		t.Error("leaving the last step of a completed wizard should not abandon it")
+	}
+}
+
+func TestWizardBackBlocked(t *testing.T) {
+	app, setup := newWizardApp()
+	blocked := false
+	app.Use(MiddlewareFunc(func(ctx *Context) bool { return !blocked }))
+
+	app.Update(NavigateMsg{Route: setup.Route()})
+	setup.State().Set("user", "ada")
+	app.Update(WizardMsg{Action: WizardNextAction})
+	app.Update(WizardMsg{Action: WizardNextAction})
+
+	blocked = true
+	app.Update(WizardMsg{Action: WizardBackAction})
+	if route := app.router.CurrentRoute(); route != "/setup/confirm" {
+		t.Fatalf("expected the blocked back to stay, got %s", route)
+	}
+	blocked = false
+	app.Update(WizardMsg{Action: WizardBackAction})
+	app.Update(WizardMsg{Action: WizardBackAction})
+	if route := app.router.CurrentRoute(); route != "/setup/account" {
+		t.Errorf("expected back to return through every step, got %s", route)
+	}
+
+	blocked = true
+	app.Update(WizardMsg{Action: WizardNextAction})
+	blocked = false
+	app.Update(WizardMsg{Action: WizardNextAction})
+	app.Update(WizardMsg{Action: WizardBackAction})
+	if route := app.router.CurrentRoute(); route != "/setup/account" {
+		t.Errorf("expected a blocked next to leave no step behind, got %s", route)
+	}
+}